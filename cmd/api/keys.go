@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"project/internal/models"
+	"project/internal/repositories/sqlite"
+	"project/internal/services"
+)
+
+// keysUsage describe el subcomando keys.
+const keysUsage = `Usage: api keys [-db items.db] <command> [flags]
+
+Commands:
+  create -name <name> -scopes <scope,...> [-tier <tier>]
+                                           create a key and print it (only once)
+  list                                     list keys and whether they are revoked
+  rotate <id>                              replace a key, keeping its name, scopes and tier
+  revoke <id>                              revoke a key
+
+Scopes: items:read, items:write, items:compare, admin
+Tiers:  standard (default), premium
+`
+
+// runKeys ejecuta el subcomando keys sobre la base de datos indicada con -db, a la
+// que antes aplica las migraciones pendientes. Los flags pueden ir antes o después
+// del comando: "keys create -name ci -scopes items:read".
+func runKeys(args []string) error {
+	flags := flag.NewFlagSet("keys", flag.ExitOnError)
+	dbPath := flags.String("db", "items.db", "SQLite database file path")
+	name := flags.String("name", "", "Name of the key to create")
+	scopes := flags.String("scopes", "", "Comma-separated scopes of the key to create")
+	tier := flags.String("tier", "", "Rate limit tier of the key to create (default standard)")
+	flags.Usage = func() { fmt.Fprint(flags.Output(), keysUsage) }
+
+	flags.Parse(args)
+	command := flags.Arg(0)
+	if command == "" {
+		flags.Usage()
+		return fmt.Errorf("missing keys command")
+	}
+	flags.Parse(flags.Args()[1:])
+
+	var id int64
+	if command == "rotate" || command == "revoke" {
+		if flags.NArg() == 0 {
+			return fmt.Errorf("missing key id")
+		}
+		var err error
+		if id, err = strconv.ParseInt(flags.Arg(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid key id %q", flags.Arg(0))
+		}
+		flags.Parse(flags.Args()[1:])
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	db, err := sqlite.OpenDatabase(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
+	keys := services.NewAPIKeyService(sqlite.NewSQLiteAPIKeyRepository(db))
+
+	switch command {
+	case "create":
+		key, secret, err := keys.CreateKey(ctx, *name, strings.Split(*scopes, ","), *tier)
+		if err != nil {
+			return err
+		}
+		printNewKey("created", key, secret)
+		return nil
+	case "list":
+		list, err := keys.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		printKeys(list)
+		return nil
+	case "rotate":
+		key, secret, err := keys.RotateKey(ctx, id)
+		if err != nil {
+			return err
+		}
+		printNewKey("rotated", key, secret)
+		return nil
+	case "revoke":
+		if err := keys.RevokeKey(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("revoked key %d\n", id)
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown keys command %q", command)
+	}
+}
+
+// printNewKey imprime una clave recién creada o rotada junto con la clave completa.
+func printNewKey(action string, key *models.APIKey, secret string) {
+	fmt.Printf("%s key %d (%s) with scopes %s and tier %s\n", action, key.ID, key.Name, strings.Join(key.Scopes, ","), key.Tier)
+	fmt.Println(secret)
+	fmt.Println("Store this key now: it cannot be shown again.")
+}
+
+// printKeys imprime las claves como una tabla.
+func printKeys(keys []models.APIKey) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tTIER\tCREATED AT\tLAST USED AT\tSTATUS")
+
+	for _, key := range keys {
+		lastUsed := "-"
+		if key.LastUsedAt != nil {
+			lastUsed = key.LastUsedAt.Format(time.RFC3339)
+		}
+		status := "active"
+		if key.Revoked() {
+			status = "revoked"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix,
+			strings.Join(key.Scopes, ","), key.Tier, key.CreatedAt.Format(time.RFC3339), lastUsed, status)
+	}
+
+	w.Flush()
+}
@@ -5,18 +5,72 @@ import (
 	"log"
 	"os"
 	api "project/internal/server"
+	"strings"
+	"time"
 )
 
 func main() {
+	// Subcomando de migraciones: api migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("Migration error: %v", err)
+		}
+		return
+	}
+
+	// Subcomando de API keys: api keys create|list|rotate|revoke
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		if err := runKeys(os.Args[2:]); err != nil {
+			log.Fatalf("Keys error: %v", err)
+		}
+		return
+	}
+
 	// Analizar los indicadores de la línea de comandos
 	port := flag.String("port", "8080", "Server port")
 	dbPath := flag.String("db", "items.db", "SQLite database file path")
+	compareRules := flag.String("compare-rules", "", "JSON file with comparison direction rules")
+	var seedPaths stringList
+	flag.Var(&seedPaths, "seed", "JSON or YAML fixture file to load at startup (repeatable)")
+	exchangeRates := flag.String("exchange-rates", "", "Exchange rates JSON file or http(s) URL")
+	exchangeRatesTTL := flag.Duration("exchange-rates-ttl", time.Hour, "How long exchange rates fetched from a URL are cached")
+	auth := flag.Bool("auth", true, "Require API keys or JWTs (disable only for local development)")
+	jwks := flag.String("jwks", "", "JWKS file or http(s) URL of the identity provider; enables JWT bearer tokens")
+	jwksTTL := flag.Duration("jwks-ttl", time.Hour, "How long a JWKS fetched from a URL is cached")
+	jwtIssuer := flag.String("jwt-issuer", "", "Required iss claim of JWTs")
+	jwtAudience := flag.String("jwt-audience", "", "Required aud claim of JWTs")
+	jwtRolesClaim := flag.String("jwt-roles-claim", "roles", "JWT claim with the user roles (dots for nested claims)")
+	jwtTierClaim := flag.String("jwt-tier-claim", "tier", "JWT claim with the user rate limit tier (dots for nested claims)")
+	jwtRoles := flag.String("jwt-roles", "", "JSON file mapping JWT roles to API scopes")
+	var trustedProxies stringList
+	flag.Var(&trustedProxies, "trusted-proxy", "CIDR or IP of a proxy whose forwarding header is trusted (repeatable, comma-separated)")
+	trustedProxyHeader := flag.String("trusted-proxy-header", "x-forwarded-for", "Header trusted proxies set with the client IP: x-forwarded-for or forwarded")
+	rateLimits := flag.String("rate-limits", "", "JSON file with rate limit policies per tier and route group")
+	rateLimitRedis := flag.String("rate-limit-redis", "", "Redis URL (redis:// or rediss://) where rate limits are shared across replicas")
+	rateLimitFailOpen := flag.Bool("rate-limit-fail-open", true, "Allow requests without limits while the rate limit Redis is unreachable (false rejects them with 503)")
 	flag.Parse()
 
 	// Crear y iniciar el servidor
 	cfg := api.Config{
-		Port:   *port,
-		DBPath: *dbPath,
+		Port:                *port,
+		DBPath:              *dbPath,
+		ComparisonRulesPath: *compareRules,
+		SeedPaths:           seedPaths,
+		ExchangeRatesSource: *exchangeRates,
+		ExchangeRatesTTL:    *exchangeRatesTTL,
+		AuthDisabled:        !*auth,
+		JWKSSource:          *jwks,
+		JWKSTTL:             *jwksTTL,
+		JWTIssuer:           *jwtIssuer,
+		JWTAudience:         *jwtAudience,
+		JWTRolesClaim:       *jwtRolesClaim,
+		JWTTierClaim:        *jwtTierClaim,
+		JWTRolesPath:        *jwtRoles,
+		TrustedProxies:      strings.Split(strings.Join(trustedProxies, ","), ","),
+		TrustedProxyHeader:  *trustedProxyHeader,
+		RateLimitsPath:      *rateLimits,
+		RateLimitRedisURL:   *rateLimitRedis,
+		RateLimitFailClosed: !*rateLimitFailOpen,
 	}
 	server, err := api.NewServer(cfg)
 	if err != nil {
@@ -30,3 +84,15 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// stringList es un flag que puede repetirse; acumula un valor por aparición.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"project/internal/repositories/sqlite"
+)
+
+// migrateUsage describe el subcomando migrate.
+const migrateUsage = `Usage: api migrate [-db items.db] <command> [-steps n]
+
+Commands:
+  up      apply all pending migrations
+  down    revert the last applied migrations (-steps, default 1)
+  status  list migrations and whether they are applied
+`
+
+// runMigrate ejecuta el subcomando migrate sobre la base de datos indicada con -db.
+// Los flags pueden ir antes o después del comando: "migrate down -steps 2".
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dbPath := flags.String("db", "items.db", "SQLite database file path")
+	steps := flags.Int("steps", 1, "Number of migrations to revert with down")
+	flags.Usage = func() { fmt.Fprint(flags.Output(), migrateUsage) }
+
+	flags.Parse(args)
+	command := flags.Arg(0)
+	if command == "" {
+		flags.Usage()
+		return fmt.Errorf("missing migrate command")
+	}
+	flags.Parse(flags.Args()[1:])
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	db, err := sqlite.OpenDatabase(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied  %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no migrations to revert")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+}
+
+// printMigrationStatus imprime el estado de las migraciones como una tabla.
+func printMigrationStatus(statuses []sqlite.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case status.Unknown:
+			state = "unknown"
+		case status.Modified:
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,196 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateTable_CrossRates: Los tipos de cambio entre monedas que no son la base se
+// calculan a través de ella
+func TestRateTable_CrossRates(t *testing.T) {
+	table := RateTable{Base: "USD", Rates: map[string]float64{"EUR": 0.8, "COP": 4000}}
+
+	rate, err := table.Rate("USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, rate)
+
+	rate, err = table.Rate("EUR", "COP")
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, rate)
+
+	_, err = table.Rate("USD", "GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	amount, err := Convert(context.Background(), NewStaticProvider(table), 19.99, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 15.99, amount)
+}
+
+// TestLoadStaticProvider: El archivo se valida y los códigos se pasan a mayúsculas
+func TestLoadStaticProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "usd", "rates": {"eur": 0.9}}`), 0o644))
+	provider, err := LoadStaticProvider(path)
+	require.NoError(t, err)
+
+	rate, err := provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/0.9, rate, 1e-12)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"base": "USD", "rates": {"EUR": -1}}`), 0o644))
+	_, err = LoadStaticProvider(invalid)
+	assert.ErrorContains(t, err, "must be a positive number")
+}
+
+// TestHTTPProvider_CachesRates: Los tipos de cambio se reutilizan durante el TTL y,
+// al vencer, se actualizan en segundo plano mientras se sigue usando la tabla anterior
+func TestHTTPProvider_CachesRates(t *testing.T) {
+	var requests atomic.Int32
+	rate := atomic.Value{}
+	rate.Store(`0.9`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base": "USD", "rates": {"EUR": ` + rate.Load().(string) + `}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	provider := NewHTTPProvider(server.URL, 10*time.Minute)
+	provider.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	value, err := provider.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, value)
+
+	rate.Store(`0.95`)
+	now = now.Add(5 * time.Minute)
+	value, err = provider.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, value)
+	assert.Equal(t, int32(1), requests.Load())
+
+	now = now.Add(5 * time.Minute)
+	value, err = provider.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, value)
+	assert.Eventually(t, func() bool {
+		value, err := provider.Rate(ctx, "USD", "EUR")
+		return err == nil && value == 0.95
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+// TestHTTPProvider_KeepsRatesOnError: Si la actualización falla se siguen usando
+// los últimos tipos de cambio; sin ninguno previo, se devuelve el error, que se
+// repite sin consultar el servicio hasta que pasa un minuto
+func TestHTTPProvider_KeepsRatesOnError(t *testing.T) {
+	var failing atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"base": "USD", "rates": {"COP": 4100}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	failing.Store(true)
+	provider := NewHTTPProvider(server.URL, time.Hour)
+	provider.now = func() time.Time { return now }
+	_, err := provider.Rate(ctx, "USD", "COP")
+	assert.ErrorContains(t, err, "unexpected status 503")
+
+	failing.Store(false)
+	_, err = provider.Rate(ctx, "USD", "COP")
+	assert.ErrorContains(t, err, "unexpected status 503")
+	assert.Equal(t, int32(1), requests.Load())
+
+	now = now.Add(time.Minute)
+	_, err = provider.Rate(ctx, "USD", "COP")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// La actualización en segundo plano falla y se siguen usando los tipos anteriores.
+	failing.Store(true)
+	now = now.Add(2 * time.Hour)
+	value, err := provider.Rate(ctx, "USD", "COP")
+	require.NoError(t, err)
+	assert.Equal(t, 4100.0, value)
+	require.Eventually(t, func() bool {
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		return provider.lastErr != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// El reintento espera un minuto en lugar de repetirse en cada petición.
+	_, err = provider.Rate(ctx, "USD", "COP")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+
+	now = now.Add(time.Minute)
+	_, err = provider.Rate(ctx, "USD", "COP")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return requests.Load() == 4 }, time.Second, time.Millisecond)
+}
+
+// TestHTTPProvider_SlowRefresh: Mientras la actualización no responde, las peticiones
+// usan la tabla anterior sin esperarla, y cancelar una petición que espera la primera
+// tabla no cancela la consulta
+func TestHTTPProvider_SlowRefresh(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		w.Write([]byte(`{"base": "USD", "rates": {"EUR": 0.9}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	provider := NewHTTPProvider(server.URL, time.Hour)
+	provider.now = func() time.Time { return now }
+
+	_, err := provider.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		value, err := provider.Rate(ctx, "USD", "EUR")
+		cancel()
+		require.NoError(t, err)
+		assert.Equal(t, 0.9, value)
+	}
+	assert.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Never(t, func() bool { return requests.Load() > 2 }, 50*time.Millisecond, time.Millisecond)
+
+	// Sin tabla, una petición cancelada deja de esperar, pero la consulta sigue.
+	empty := NewHTTPProvider(server.URL, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = empty.Rate(ctx, "USD", "EUR")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Eventually(t, func() bool { return requests.Load() == 3 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,152 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultRatesTTL es el tiempo durante el que HTTPProvider reutiliza los tipos
+	// de cambio obtenidos si no se indica otro.
+	DefaultRatesTTL = time.Hour
+
+	// ratesRetryInterval es la espera antes de reintentar una actualización fallida.
+	ratesRetryInterval = time.Minute
+
+	// maxRatesResponseSize limita el tamaño de la respuesta del servicio.
+	maxRatesResponseSize = 1 << 20
+)
+
+// HTTPProvider obtiene los tipos de cambio de un servicio HTTP que responde con una
+// RateTable en JSON y los guarda en caché durante el TTL. Al vencer, la tabla se
+// actualiza en segundo plano mientras se sigue usando la anterior, de modo que un
+// servicio lento no retiene las peticiones. Si una actualización falla, se siguen
+// usando los últimos tipos de cambio obtenidos y se reintenta al cabo de un minuto;
+// si todavía no hay ninguno, se devuelve el error y, durante ese minuto, las
+// peticiones fallan con el mismo error sin volver a consultar el servicio.
+type HTTPProvider struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	// now devuelve el momento actual; los tests lo reemplazan.
+	now func() time.Time
+
+	// refreshes agrupa las consultas concurrentes del servicio en una sola, que se
+	// hace sin mantener mu.
+	refreshes singleflight.Group
+
+	mu        sync.Mutex
+	table     *RateTable
+	refreshAt time.Time
+	lastErr   error
+}
+
+// NewHTTPProvider crea un proveedor que consulta url. Un ttl no positivo usa
+// DefaultRatesTTL.
+func NewHTTPProvider(url string, ttl time.Duration) *HTTPProvider {
+	if ttl <= 0 {
+		ttl = DefaultRatesTTL
+	}
+	return &HTTPProvider{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// Rate implementa ExchangeRateProvider.
+func (p *HTTPProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	table, err := p.rates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return table.Rate(from, to)
+}
+
+// rates devuelve la tabla en caché y, si venció, inicia su actualización en segundo
+// plano. Solo espera a la actualización si todavía no hay ninguna tabla.
+func (p *HTTPProvider) rates(ctx context.Context) (*RateTable, error) {
+	now := p.now()
+
+	p.mu.Lock()
+	table, refreshAt, lastErr := p.table, p.refreshAt, p.lastErr
+	p.mu.Unlock()
+
+	switch {
+	case now.Before(refreshAt) && table == nil:
+		return nil, lastErr
+	case now.Before(refreshAt):
+		return table, nil
+	case table != nil:
+		p.refresh(ctx, now)
+		return table, nil
+	}
+
+	select {
+	case result := <-p.refresh(ctx, now):
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*RateTable), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh inicia, si no hay otra en curso, la consulta del servicio y devuelve el
+// canal por el que llega su resultado: la tabla vigente o, si no hay ninguna, el
+// error. La consulta no se cancela con el contexto de la petición que la inicia,
+// porque otras pueden estar esperándola; la limita el timeout del cliente HTTP.
+func (p *HTTPProvider) refresh(ctx context.Context, now time.Time) <-chan singleflight.Result {
+	return p.refreshes.DoChan(p.url, func() (interface{}, error) {
+		table, err := p.fetch(context.WithoutCancel(ctx))
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if err != nil {
+			p.lastErr = err
+			p.refreshAt = now.Add(ratesRetryInterval)
+			if p.table == nil {
+				return nil, err
+			}
+			return p.table, nil
+		}
+
+		p.table, p.lastErr = &table, nil
+		p.refreshAt = now.Add(p.ttl)
+		return p.table, nil
+	})
+}
+
+// fetch consulta el servicio de tipos de cambio.
+func (p *HTTPProvider) fetch(ctx context.Context) (RateTable, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return RateTable{}, fmt.Errorf("failed to create exchange rates request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return RateTable{}, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RateTable{}, fmt.Errorf("failed to fetch exchange rates: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRatesResponseSize))
+	if err != nil {
+		return RateTable{}, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	return parseRateTable(data)
+}
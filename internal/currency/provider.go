@@ -0,0 +1,102 @@
+// Package currency convierte importes entre monedas con los tipos de cambio de un
+// ExchangeRateProvider.
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrUnknownCurrency indica que el proveedor no tiene el tipo de cambio de una moneda.
+var ErrUnknownCurrency = stdErrors.New("currency: unknown currency")
+
+// ExchangeRateProvider devuelve tipos de cambio entre monedas identificadas por su
+// código ISO 4217 en mayúsculas.
+type ExchangeRateProvider interface {
+	// Rate devuelve cuántas unidades de to equivalen a una unidad de from.
+	// Si no conoce alguna de las monedas, devuelve un error que envuelve
+	// ErrUnknownCurrency.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// RateTable son tipos de cambio respecto de una moneda base: Rates[c] es cuántas
+// unidades de c equivalen a una unidad de Base. Es el formato de los archivos de
+// tipos de cambio y de las respuestas del servicio HTTP:
+//
+//	{"base": "USD", "rates": {"EUR": 0.92, "COP": 4100}}
+type RateTable struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Rate devuelve el tipo de cambio de from a to, calculado a través de la moneda base.
+func (t RateTable) Rate(from, to string) (float64, error) {
+	fromRate, err := t.baseRate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := t.baseRate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+// baseRate devuelve cuántas unidades de code equivalen a una unidad de la base.
+func (t RateTable) baseRate(code string) (float64, error) {
+	if code == t.Base {
+		return 1, nil
+	}
+	rate, ok := t.Rates[code]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return rate, nil
+}
+
+// parseRateTable decodifica y valida una tabla de tipos de cambio. Los códigos de
+// moneda se pasan a mayúsculas.
+func parseRateTable(data []byte) (RateTable, error) {
+	var raw RateTable
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return RateTable{}, fmt.Errorf("invalid exchange rates: %w", err)
+	}
+
+	table := RateTable{
+		Base:  strings.ToUpper(strings.TrimSpace(raw.Base)),
+		Rates: make(map[string]float64, len(raw.Rates)),
+	}
+	if table.Base == "" {
+		return RateTable{}, fmt.Errorf("invalid exchange rates: base currency is required")
+	}
+	for code, rate := range raw.Rates {
+		if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return RateTable{}, fmt.Errorf("invalid exchange rates: rate of %q must be a positive number", code)
+		}
+		table.Rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return table, nil
+}
+
+// Convert convierte amount de la moneda from a la moneda to y redondea el resultado
+// a dos decimales. Si las monedas coinciden, devuelve amount sin consultar al proveedor.
+func Convert(ctx context.Context, provider ExchangeRateProvider, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := provider.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return Round(amount * rate), nil
+}
+
+// Round redondea un importe a dos decimales.
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,37 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// StaticProvider devuelve los tipos de cambio de una tabla fija, normalmente leída
+// de un archivo al iniciar el servidor.
+type StaticProvider struct {
+	table RateTable
+}
+
+// NewStaticProvider crea un proveedor con los tipos de cambio de table.
+func NewStaticProvider(table RateTable) *StaticProvider {
+	return &StaticProvider{table: table}
+}
+
+// LoadStaticProvider lee un archivo JSON con el formato de RateTable.
+func LoadStaticProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	table, err := parseRateTable(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewStaticProvider(table), nil
+}
+
+// Rate implementa ExchangeRateProvider.
+func (p *StaticProvider) Rate(_ context.Context, from, to string) (float64, error) {
+	return p.table.Rate(from, to)
+}
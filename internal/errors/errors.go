@@ -9,18 +9,35 @@ import (
 type ErrorCode string
 
 const (
-	ErrorCodeNotFound        ErrorCode = "NOT_FOUND"
-	ErrorCodeBadRequest      ErrorCode = "BAD_REQUEST"
-	ErrorCodeInternalServer  ErrorCode = "INTERNAL_SERVER_ERROR"
-	ErrorCodeValidation      ErrorCode = "VALIDATION_ERROR"
-	ErrorCodeTooManyRequests ErrorCode = "TOO_MANY_REQUESTS"
+	ErrorCodeNotFound             ErrorCode = "NOT_FOUND"
+	ErrorCodeBadRequest           ErrorCode = "BAD_REQUEST"
+	ErrorCodeInternalServer       ErrorCode = "INTERNAL_SERVER_ERROR"
+	ErrorCodeValidation           ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeTooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"
+	ErrorCodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrorCodeNotAcceptable        ErrorCode = "NOT_ACCEPTABLE"
+	ErrorCodeConflict             ErrorCode = "CONFLICT"
+	ErrorCodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodePreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	ErrorCodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden            ErrorCode = "FORBIDDEN"
+	ErrorCodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
 )
 
+// FieldError describe un problema de validación en un campo concreto de la petición.
+// Field usa la ruta JSON del campo, por ejemplo "price" o "specifications.memory".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 // DomainError es un tipo de dato que representa un error de dominio.
+// Details contiene, opcionalmente, los errores por campo de una validación.
 type DomainError struct {
 	Code    ErrorCode
 	Message string
 	Err     error
+	Details []FieldError
 }
 
 // Error implementa la interfaz error.
@@ -47,9 +64,10 @@ func NewDomainError(code ErrorCode, message string, err error) *DomainError {
 
 // ErrorResponse es un tipo de dato que representa la respuesta de error estandarizada para la API.
 type ErrorResponse struct {
-	Error   bool      `json:"error"`
-	Message string    `json:"message"`
-	Code    ErrorCode `json:"code"`
+	Error   bool         `json:"error"`
+	Message string       `json:"message"`
+	Code    ErrorCode    `json:"code"`
+	Details []FieldError `json:"details,omitempty"`
 }
 
 // ToErrorResponse convierte un ErrorDomain en un ErrorResponse.
@@ -58,6 +76,7 @@ func (e *DomainError) ToErrorResponse() ErrorResponse {
 		Error:   true,
 		Message: e.Message,
 		Code:    e.Code,
+		Details: e.Details,
 	}
 }
 
@@ -72,6 +91,22 @@ func (e *DomainError) HTTPStatus() int {
 		return http.StatusUnprocessableEntity
 	case ErrorCodeTooManyRequests:
 		return http.StatusTooManyRequests
+	case ErrorCodeUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case ErrorCodeNotAcceptable:
+		return http.StatusNotAcceptable
+	case ErrorCodeConflict:
+		return http.StatusConflict
+	case ErrorCodePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case ErrorCodePreconditionRequired:
+		return http.StatusPreconditionRequired
+	case ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case ErrorCodeForbidden:
+		return http.StatusForbidden
+	case ErrorCodeServiceUnavailable:
+		return http.StatusServiceUnavailable
 	case ErrorCodeInternalServer:
 		return http.StatusInternalServerError
 	default:
@@ -98,6 +133,54 @@ func NewValidationError(message string, err error) *DomainError {
 	return NewDomainError(ErrorCodeValidation, message, err)
 }
 
+// NewFieldValidationError crea un error de dominio de tipo "validación" con el
+// detalle de los campos inválidos.
+func NewFieldValidationError(message string, details []FieldError) *DomainError {
+	domainErr := NewDomainError(ErrorCodeValidation, message, nil)
+	domainErr.Details = details
+	return domainErr
+}
+
+// NewUnsupportedMediaTypeError crea un error de dominio de tipo "formato no soportado"
+func NewUnsupportedMediaTypeError(message string) *DomainError {
+	return NewDomainError(ErrorCodeUnsupportedMediaType, message, nil)
+}
+
+// NewNotAcceptableError crea un error de dominio de tipo "ningún formato de respuesta aceptable"
+func NewNotAcceptableError(message string) *DomainError {
+	return NewDomainError(ErrorCodeNotAcceptable, message, nil)
+}
+
+// NewConflictError crea un error de dominio de tipo "conflicto con el estado actual del recurso"
+func NewConflictError(message string, err error) *DomainError {
+	return NewDomainError(ErrorCodeConflict, message, err)
+}
+
+// NewPreconditionFailedError crea un error de dominio de tipo "la precondición de la petición no se cumple"
+func NewPreconditionFailedError(message string) *DomainError {
+	return NewDomainError(ErrorCodePreconditionFailed, message, nil)
+}
+
+// NewPreconditionRequiredError crea un error de dominio de tipo "la escritura debe ser condicional"
+func NewPreconditionRequiredError(message string) *DomainError {
+	return NewDomainError(ErrorCodePreconditionRequired, message, nil)
+}
+
+// NewUnauthorizedError crea un error de dominio de tipo "credenciales ausentes o inválidas"
+func NewUnauthorizedError(message string) *DomainError {
+	return NewDomainError(ErrorCodeUnauthorized, message, nil)
+}
+
+// NewForbiddenError crea un error de dominio de tipo "permisos insuficientes"
+func NewForbiddenError(message string) *DomainError {
+	return NewDomainError(ErrorCodeForbidden, message, nil)
+}
+
+// NewServiceUnavailableError crea un error de dominio de tipo "dependencia no disponible"
+func NewServiceUnavailableError(message string, err error) *DomainError {
+	return NewDomainError(ErrorCodeServiceUnavailable, message, err)
+}
+
 // NewInternalServerError crea un error de dominio de tipo "error interno del servidor"
 func NewInternalServerError(message string, err error) *DomainError {
 	return NewDomainError(ErrorCodeInternalServer, message, err)
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"project/internal/errors"
+	"project/internal/render"
+	"project/internal/services"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CategoryHandler maneja las peticiones HTTP de los endpoints de categorías.
+type CategoryHandler struct {
+	service services.CategoryService
+
+	// encoders codifica los listados de items en el formato que pide el cliente.
+	encoders *render.Registry
+}
+
+// NewCategoryHandler crea una nueva instancia del handler de categorías.
+func NewCategoryHandler(service services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service:  service,
+		encoders: render.Default(),
+	}
+}
+
+// GetCategories maneja GET /api/v1/categories
+// Devuelve el árbol completo de categorías.
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetCategoryTree(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tree)
+}
+
+// GetCategoryItems maneja GET /api/v1/categories/{id}/items
+// Devuelve una página de los items de la categoría y de sus subcategorías,
+// aceptando los mismos query params que el listado de items.
+func (h *CategoryHandler) GetCategoryItems(w http.ResponseWriter, r *http.Request) {
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	id, err := parseCategoryID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	query, err := parseItemQuery(r.URL.Query())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	page, err := h.service.GetCategoryItems(r.Context(), id, query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	setPaginationLinks(r, page)
+	writeEncoded(w, encoder, http.StatusOK, page)
+}
+
+// GetSpecSchema maneja GET /api/v1/categories/{id}/spec-schema
+// Devuelve los campos de especificación permitidos en la categoría, incluidos
+// los heredados, para que los clientes puedan generar formularios.
+func (h *CategoryHandler) GetSpecSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := parseCategoryID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	schema, err := h.service.GetSpecSchema(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// parseCategoryID extrae y valida el parámetro {id} de la ruta.
+func parseCategoryID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError("formato de id de categoría inválido", err)
+	}
+	return id, nil
+}
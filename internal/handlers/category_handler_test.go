@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/errors"
+	"project/internal/models"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryService es una implementación mock de CategoryService para pruebas
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) GetCategoryTree(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategoryItems(ctx context.Context, id int64, query models.ItemQuery) (*models.ItemPage, error) {
+	args := m.Called(ctx, id, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemPage), args.Error(1)
+}
+
+func (m *MockCategoryService) GetSpecSchema(ctx context.Context, id int64) (*models.SpecSchema, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SpecSchema), args.Error(1)
+}
+
+// setupCategoryRouter crea un router chi con las rutas de categorías
+func setupCategoryRouter(handler *CategoryHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/api/v1/categories", func(r chi.Router) {
+		r.Get("/", handler.GetCategories)
+		r.Get("/{id}/items", handler.GetCategoryItems)
+		r.Get("/{id}/spec-schema", handler.GetSpecSchema)
+	})
+	return r
+}
+
+// TestGetCategories_OK: Devuelve el árbol de categorías (200)
+func TestGetCategories_OK(t *testing.T) {
+	mockService := new(MockCategoryService)
+	router := setupCategoryRouter(NewCategoryHandler(mockService))
+
+	parentID := int64(1)
+	tree := []models.Category{
+		{ID: 1, Name: "Computers", Slug: "computers", Children: []models.Category{
+			{ID: 2, Name: "Laptops", Slug: "laptops", ParentID: &parentID},
+		}},
+	}
+	mockService.On("GetCategoryTree", mock.Anything).Return(tree, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/categories", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Category
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, tree, response)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetCategoryItems_OK: Pasa el ID y los query params al servicio y añade los enlaces de paginación
+func TestGetCategoryItems_OK(t *testing.T) {
+	mockService := new(MockCategoryService)
+	router := setupCategoryRouter(NewCategoryHandler(mockService))
+
+	page := &models.ItemPage{
+		Data:       []models.Item{{ID: 1, Name: "Laptop"}},
+		Pagination: models.Pagination{Total: 3, Limit: 1, Offset: 0},
+	}
+	mockService.On("GetCategoryItems", mock.Anything, int64(1), models.ItemQuery{Limit: 1}).Return(page, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/categories/1/items?limit=1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ItemPage
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Data, 1)
+	assert.NotNil(t, response.Pagination.Next)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetCategoryItems_InvalidID: Un ID no numérico devuelve 400 sin llamar al servicio
+func TestGetCategoryItems_InvalidID(t *testing.T) {
+	mockService := new(MockCategoryService)
+	router := setupCategoryRouter(NewCategoryHandler(mockService))
+
+	req := httptest.NewRequest("GET", "/api/v1/categories/abc/items", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeBadRequest, errorResp.Code)
+
+	mockService.AssertNotCalled(t, "GetCategoryItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetCategoryItems_NotFound: La categoría no existe (404)
+func TestGetCategoryItems_NotFound(t *testing.T) {
+	mockService := new(MockCategoryService)
+	router := setupCategoryRouter(NewCategoryHandler(mockService))
+
+	mockService.On("GetCategoryItems", mock.Anything, int64(99), models.ItemQuery{}).
+		Return(nil, errors.NewNotFoundError("Category", 99))
+
+	req := httptest.NewRequest("GET", "/api/v1/categories/99/items", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestGetSpecSchema_OK: Devuelve el esquema de especificaciones de la categoría
+func TestGetSpecSchema_OK(t *testing.T) {
+	mockService := new(MockCategoryService)
+	router := setupCategoryRouter(NewCategoryHandler(mockService))
+
+	schema := &models.SpecSchema{
+		CategoryID: 2,
+		Fields: []models.SpecField{
+			{Key: "memory", Label: "Memory", Type: models.SpecTypeQuantity, Dimension: models.DimensionDataSize, Unit: "GB", Required: true, CategoryID: 1, Inherited: true},
+		},
+	}
+	mockService.On("GetSpecSchema", mock.Anything, int64(2)).Return(schema, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/categories/2/spec-schema", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.SpecSchema
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, *schema, response)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/render"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// itemETag identifica la representación de un item por su ID, su versión y su
+// precio, que puede estar convertido a otra moneda.
+func itemETag(item *models.Item, encoder render.Encoder) string {
+	return strongETag(
+		"item", strconv.FormatInt(item.ID, 10), strconv.FormatInt(item.Version, 10),
+		formatPrice(item), encoder.ContentType(),
+	)
+}
+
+// itemListETag identifica una página del listado por la versión del catálogo y los
+// query params normalizados, de modo que cualquier escritura en el catálogo la invalida.
+func itemListETag(state *models.CatalogState, values url.Values, encoder render.Encoder) string {
+	return strongETag("items", strconv.FormatInt(state.Version, 10), values.Encode(), encoder.ContentType())
+}
+
+// convertedItemListETag identifica una página del listado con los precios convertidos
+// a otra moneda: además de lo que usa itemListETag, incluye el total y los precios de
+// los items, que cambian con los tipos de cambio.
+func convertedItemListETag(state *models.CatalogState, values url.Values, page *models.ItemPage, encoder render.Encoder) string {
+	parts := []string{"items", strconv.FormatInt(state.Version, 10), values.Encode(), strconv.Itoa(page.Pagination.Total)}
+	for i := range page.Data {
+		parts = append(parts, strconv.FormatInt(page.Data[i].ID, 10)+":"+formatPrice(&page.Data[i]))
+	}
+	return strongETag(append(parts, encoder.ContentType())...)
+}
+
+// formatPrice devuelve el precio del item con su moneda, como parte de un ETag.
+func formatPrice(item *models.Item) string {
+	return strconv.FormatFloat(item.Price, 'f', -1, 64) + " " + item.Currency
+}
+
+// comparisonETag identifica una comparación por la petición (los IDs en el orden
+// recibido, el modo estricto y la moneda) y las versiones y los precios convertidos
+// de los items comparados.
+func comparisonETag(req models.CompareRequest, response *models.CompareResponse, encoder render.Encoder) string {
+	parts := []string{"comparison", strconv.FormatBool(req.Strict), req.Currency}
+	for _, id := range req.ItemIDs {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	for i := range response.Items {
+		item := &response.Items[i]
+		parts = append(parts, strconv.FormatInt(item.ID, 10)+":"+strconv.FormatInt(item.Version, 10), formatPrice(item))
+		// El precio más bajo de los últimos 30 días cambia con el paso del tiempo,
+		// sin que cambie la versión del item.
+		if lowest, ok := response.Comparison.LowestPrices30d[item.ID]; ok {
+			parts = append(parts, strconv.FormatFloat(lowest, 'f', -1, 64))
+		}
+	}
+	return strongETag(append(parts, encoder.ContentType())...)
+}
+
+// strongETag devuelve un ETag fuerte calculado como hash de las partes que
+// identifican la representación: el recurso, su versión y el formato de la respuesta.
+func strongETag(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// checkNotModified escribe las cabeceras de validación de la respuesta (ETag,
+// Last-Modified y Cache-Control) y, si la petición condicional indica que el
+// cliente ya tiene esta representación, responde 304 y devuelve true.
+// Un lastModified cero omite Last-Modified.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if !notModified(r, etag, lastModified) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// notModified evalúa If-None-Match y, solo si no está presente, If-Modified-Since
+// (RFC 9110, sección 13.2.2). If-Modified-Since solo se aplica a GET y HEAD.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if values := r.Header.Values("If-None-Match"); len(values) > 0 {
+		return etagMatches(strings.Join(values, ","), etag)
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified tiene precisión de segundos.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches indica si la lista de ETags de If-None-Match incluye etag, usando
+// la comparación débil: los prefijos W/ se ignoran.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeVersion resuelve la versión a la que se condiciona una escritura sobre el item
+// id, para que la última escritura no gane sin saberlo:
+//
+//   - bodyVersion, si el body incluye "version".
+//   - La versión actual del item, si su ETag está en If-Match. Si no lo está, la
+//     escritura se rechaza con 412.
+//   - If-Match: * es una sobrescritura explícita: sin "version" en el body, la
+//     escritura no se condiciona y se devuelve 0.
+//
+// Sin "version" ni If-Match, la escritura se rechaza con 428.
+func (h *ItemHandler) writeVersion(r *http.Request, id int64, bodyVersion *int64, encoder render.Encoder) (int64, error) {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		if bodyVersion == nil {
+			return 0, errors.NewPreconditionRequiredError(
+				`la escritura debe indicar la versión del item en el campo "version" o su ETag en If-Match (If-Match: * para sobrescribirlo)`,
+			)
+		}
+		return *bodyVersion, nil
+	}
+
+	header := strings.Join(values, ",")
+	if strings.TrimSpace(header) == "*" {
+		if bodyVersion == nil {
+			return 0, nil
+		}
+		return *bodyVersion, nil
+	}
+
+	current, err := h.service.GetItemByID(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	if !strongETagMatches(header, itemETag(current, encoder)) {
+		return 0, errors.NewPreconditionFailedError(
+			fmt.Sprintf("el item %d fue modificado y ya no coincide con If-Match; obténgalo de nuevo y reintente", id),
+		)
+	}
+
+	if bodyVersion != nil {
+		return *bodyVersion, nil
+	}
+	return current.Version, nil
+}
+
+// strongETagMatches indica si la lista de ETags de If-Match incluye etag, usando la
+// comparación fuerte: un ETag débil (W/) nunca coincide.
+func strongETagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetItemByID_ETag: La respuesta incluye ETag y Last-Modified, y un GET
+// condicional con el mismo ETag recibe 304 sin body
+func TestGetItemByID_ETag(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	updatedAt := time.Date(2025, 3, 1, 10, 30, 15, 250e6, time.UTC)
+	item := &models.Item{ID: 1, Name: "Item 1", Specifications: models.Specifications{}, Version: 3, UpdatedAt: updatedAt}
+	mockService.On("GetItemByID", mock.Anything, int64(1)).Return(item, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "Sat, 01 Mar 2025 10:30:15 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest("GET", "/api/v1/items/1", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+
+	// Cada formato es una representación distinta con su propio ETag.
+	req = httptest.NewRequest("GET", "/api/v1/items/1", nil)
+	req.Header.Set("Accept", "application/xml")
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+// TestGetItemByID_IfModifiedSince: If-Modified-Since se compara con precisión de
+// segundos y se ignora si la petición incluye If-None-Match
+func TestGetItemByID_IfModifiedSince(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	updatedAt := time.Date(2025, 3, 1, 10, 30, 15, 250e6, time.UTC)
+	item := &models.Item{ID: 1, Name: "Item 1", Specifications: models.Specifications{}, Version: 3, UpdatedAt: updatedAt}
+	mockService.On("GetItemByID", mock.Anything, int64(1)).Return(item, nil)
+
+	tests := []struct {
+		name        string
+		since       string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "same second", since: "Sat, 01 Mar 2025 10:30:15 GMT", wantStatus: http.StatusNotModified},
+		{name: "later", since: "Sat, 01 Mar 2025 11:00:00 GMT", wantStatus: http.StatusNotModified},
+		{name: "earlier", since: "Sat, 01 Mar 2025 10:30:14 GMT", wantStatus: http.StatusOK},
+		{name: "invalid date", since: "yesterday", wantStatus: http.StatusOK},
+		{name: "if-none-match wins", since: "Sat, 01 Mar 2025 11:00:00 GMT", ifNoneMatch: `"stale"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/items/1", nil)
+			req.Header.Set("If-Modified-Since", tt.since)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// TestGetAllItems_NotModified: El ETag del listado depende de la versión del
+// catálogo y de los query params; si coincide no se consulta la página
+func TestGetAllItems_NotModified(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	state := &models.CatalogState{Version: 7, UpdatedAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	mockService.On("CatalogState", mock.Anything).Return(state, nil)
+	mockService.On("GetAllItems", mock.Anything, mock.Anything).
+		Return(&models.ItemPage{Data: []models.Item{}, Pagination: models.Pagination{Limit: 20}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/api/v1/items?sort=price&limit=20", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, "Sat, 01 Mar 2025 10:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	// El orden de los query params no cambia la representación.
+	req = httptest.NewRequest("GET", "/api/v1/items?limit=20&sort=price", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/items", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:00:00 GMT")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// Cualquier escritura en el catálogo invalida el ETag del listado.
+	state.Version = 8
+	req = httptest.NewRequest("GET", "/api/v1/items?limit=20&sort=price", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	mockService.On("GetAllItems", mock.Anything, mock.Anything).
+		Return(&models.ItemPage{Data: []models.Item{}, Pagination: models.Pagination{Limit: 20}}, nil).Once()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetAllItems_CurrencyETag: Con currency la página siempre se consulta y el
+// ETag cambia cuando cambian los precios convertidos
+func TestGetAllItems_CurrencyETag(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	state := &models.CatalogState{Version: 7, UpdatedAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	page := &models.ItemPage{
+		Data:       []models.Item{{ID: 1, Price: 92, Currency: "EUR", Specifications: models.Specifications{}}},
+		Pagination: models.Pagination{Total: 1, Limit: 20},
+	}
+	mockService.On("CatalogState", mock.Anything).Return(state, nil)
+	mockService.On("GetAllItems", mock.Anything, models.ItemQuery{Currency: "EUR"}).Return(page, nil)
+
+	list := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/items?currency=EUR", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:00:00 GMT")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := list(`"stale"`)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+
+	w = list(etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// Los tipos de cambio cambiaron sin que cambiara el catálogo.
+	page.Data[0].Price = 93
+	w = list(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	mockService.AssertNumberOfCalls(t, "GetAllItems", 3)
+}
+
+// TestCompareItems_ETag: El ETag de la comparación depende de las versiones de los
+// items comparados y If-None-Match responde 304
+func TestCompareItems_ETag(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	response := &models.CompareResponse{
+		Items: []models.Item{
+			{ID: 1, Name: "Item 1", Specifications: models.Specifications{}, Version: 2},
+			{ID: 2, Name: "Item 2", Specifications: models.Specifications{}, Version: 5},
+		},
+	}
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1, 2}}).Return(response, nil)
+
+	compare := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/items/compare", strings.NewReader(`{"item_ids":[1,2]}`))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := compare("")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+
+	w = compare(etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	response.Items[1].Version = 6
+	w = compare(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// El precio más bajo de los últimos 30 días cambia sin que cambien las versiones.
+	etag = w.Header().Get("ETag")
+	response.Comparison.LowestPrices30d = map[int64]float64{1: 9.99, 2: 19.99}
+	w = compare(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+// TestETagMatches: If-None-Match admite listas, "*" y ETags débiles
+func TestETagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"a"`, `"a"`))
+	assert.True(t, etagMatches(`"b", "a"`, `"a"`))
+	assert.True(t, etagMatches(`W/"a"`, `"a"`))
+	assert.True(t, etagMatches(`*`, `"a"`))
+	assert.False(t, etagMatches(`"b"`, `"a"`))
+	assert.False(t, etagMatches(`a`, `"a"`))
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/render"
+	"strings"
+	"time"
+)
+
+const (
+	// exportWriteTimeout reemplaza el WriteTimeout del servidor durante una
+	// exportación, que puede tardar bastante más que una petición normal.
+	exportWriteTimeout = 10 * time.Minute
+
+	// exportFlushEvery es la cantidad de filas que se envían al cliente en cada flush.
+	exportFlushEvery = 100
+)
+
+// ExportItems maneja GET /api/v1/items/export
+// Exporta los items que cumplen los filtros como CSV (por defecto) o NDJSON,
+// leyéndolos de la base de datos y enviándolos al cliente a medida que se leen.
+// Con excel=true el CSV se genera para abrirse directamente en Excel.
+func (h *ItemHandler) ExportItems(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	format := values.Get("format")
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if format != models.ExportFormatCSV && format != models.ExportFormatNDJSON {
+		handleError(w, errors.NewBadRequestError(fmt.Sprintf("formato de exportación no soportado: %q (use csv o ndjson)", format), nil))
+		return
+	}
+
+	excel, err := parseBoolParam(values, "excel")
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	query, err := parseExportQuery(values)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Si el ResponseWriter no permite cambiar el plazo, se mantiene el del servidor.
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+
+	var writer exportWriter
+	if format == models.ExportFormatCSV {
+		writer = &csvExportWriter{w: w, excel: excel}
+	} else {
+		writer = &ndjsonExportWriter{w: w}
+	}
+
+	if err := h.service.ExportItems(r.Context(), query, writer); err != nil {
+		if !writer.Started() {
+			handleError(w, err)
+			return
+		}
+		// El código de estado ya se envió: se corta la conexión para que el cliente
+		// no confunda un archivo incompleto con uno válido.
+		log.Printf("Exportación interrumpida: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := writer.Flush(); err != nil {
+		log.Printf("Exportación interrumpida: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// parseExportQuery construye los filtros de la exportación a partir de los query params:
+//
+//	min_price, max_price     filtros por precio
+//	min_rating               filtro por rating mínimo
+//	min_id, max_id           rango de IDs (ambos incluidos)
+//	category_id              categoría (incluye sus subcategorías)
+//	spec.<clave>[_op]=valor  filtros por especificación (ver parseSpecFilters)
+//	currency                 moneda a la que se convierten los precios
+func parseExportQuery(values url.Values) (models.ItemQuery, error) {
+	var query models.ItemQuery
+	var err error
+
+	if query.MinPrice, err = parseFloatParam(values, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = parseFloatParam(values, "max_price"); err != nil {
+		return query, err
+	}
+	if query.MinRating, err = parseFloatParam(values, "min_rating"); err != nil {
+		return query, err
+	}
+	if query.MinID, err = parseInt64Param(values, "min_id"); err != nil {
+		return query, err
+	}
+	if query.MaxID, err = parseInt64Param(values, "max_id"); err != nil {
+		return query, err
+	}
+	if query.CategoryID, err = parseInt64Param(values, "category_id"); err != nil {
+		return query, err
+	}
+
+	query.SpecFilters = parseSpecFilters(values)
+	query.Currency = values.Get("currency")
+	return query, nil
+}
+
+// exportWriter es un models.ExportWriter que escribe en la respuesta HTTP.
+// Started indica si ya se enviaron las cabeceras, después de lo cual no es
+// posible responder con un error JSON.
+type exportWriter interface {
+	models.ExportWriter
+	Started() bool
+	Flush() error
+}
+
+// csvExportWriter escribe un CSV con las mismas columnas que acepta la importación:
+// los campos del item y una columna spec.<clave> por cada clave de especificación.
+type csvExportWriter struct {
+	w        http.ResponseWriter
+	excel    bool
+	csv      *csv.Writer
+	specKeys []string
+	rows     int
+	started  bool
+}
+
+// WriteHeader envía las cabeceras HTTP y la fila de encabezado del CSV.
+// En modo Excel se antepone el BOM UTF-8, sin el cual Excel no reconoce la
+// codificación, y las filas terminan en CRLF.
+func (c *csvExportWriter) WriteHeader(specKeys []string) error {
+	c.specKeys = specKeys
+
+	header := c.w.Header()
+	header.Set("Content-Type", "text/csv; charset=utf-8")
+	header.Set("Content-Disposition", `attachment; filename="items.csv"`)
+	c.w.WriteHeader(http.StatusOK)
+	c.started = true
+
+	if c.excel {
+		if _, err := c.w.Write([]byte("\ufeff")); err != nil {
+			return err
+		}
+	}
+
+	c.csv = csv.NewWriter(c.w)
+	c.csv.UseCRLF = c.excel
+
+	return c.csv.Write(render.ItemColumns(specKeys))
+}
+
+// WriteItem escribe la fila de un item. Las especificaciones que el item no
+// tiene quedan vacías.
+func (c *csvExportWriter) WriteItem(item models.Item) error {
+	record := render.ItemRecord(item, c.specKeys)
+	if c.excel {
+		for i, value := range record {
+			record[i] = excelSafe(value)
+		}
+	}
+
+	if err := c.csv.Write(record); err != nil {
+		return err
+	}
+
+	c.rows++
+	if c.rows%exportFlushEvery == 0 {
+		return c.Flush()
+	}
+	return nil
+}
+
+// excelSafe prefija con un apóstrofo los valores que empiezan con un carácter que
+// Excel interpreta como fórmula. Los números exportados nunca son negativos, por
+// lo que el prefijo solo afecta a textos.
+func excelSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Started indica si ya se enviaron las cabeceras.
+func (c *csvExportWriter) Started() bool {
+	return c.started
+}
+
+// Flush envía al cliente las filas pendientes.
+func (c *csvExportWriter) Flush() error {
+	if c.csv == nil {
+		return nil
+	}
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return flushResponse(c.w)
+}
+
+// ndjsonExportWriter escribe un objeto JSON por línea, con la misma forma que
+// devuelve GET /api/v1/items/{id}. Las especificaciones no se aplanan.
+type ndjsonExportWriter struct {
+	w       http.ResponseWriter
+	encoder *json.Encoder
+	rows    int
+	started bool
+}
+
+// WriteHeader envía las cabeceras HTTP. NDJSON no tiene fila de encabezado.
+func (n *ndjsonExportWriter) WriteHeader(specKeys []string) error {
+	header := n.w.Header()
+	header.Set("Content-Type", "application/x-ndjson")
+	header.Set("Content-Disposition", `attachment; filename="items.ndjson"`)
+	n.w.WriteHeader(http.StatusOK)
+	n.started = true
+
+	n.encoder = json.NewEncoder(n.w)
+	return nil
+}
+
+// WriteItem escribe la línea de un item.
+func (n *ndjsonExportWriter) WriteItem(item models.Item) error {
+	if err := n.encoder.Encode(item); err != nil {
+		return err
+	}
+
+	n.rows++
+	if n.rows%exportFlushEvery == 0 {
+		return n.Flush()
+	}
+	return nil
+}
+
+// Started indica si ya se enviaron las cabeceras.
+func (n *ndjsonExportWriter) Started() bool {
+	return n.started
+}
+
+// Flush envía al cliente las líneas pendientes.
+func (n *ndjsonExportWriter) Flush() error {
+	if n.encoder == nil {
+		return nil
+	}
+	return flushResponse(n.w)
+}
+
+// flushResponse envía al cliente lo escrito hasta el momento. Si el
+// ResponseWriter no admite flush, los datos se envían al completar la respuesta.
+func flushResponse(w http.ResponseWriter) error {
+	err := http.NewResponseController(w).Flush()
+	if stdErrors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/errors"
+	"project/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportTestItems devuelve dos items con especificaciones distintas.
+func exportTestItems() []models.Item {
+	categoryID := int64(2)
+	return []models.Item{
+		{
+			ID: 1, Name: "Laptop, A", ImageURL: "https://example.com/a.jpg", Price: 999.5, Currency: "USD", Rating: 4.5, CategoryID: &categoryID,
+			Specifications: models.Specifications{"memory": "16GB", "touchscreen": true},
+		},
+		{
+			ID: 2, Name: "=Laptop B", ImageURL: "https://example.com/b.jpg", Description: "Ligera", Price: 10, Currency: "EUR", Rating: 4,
+			Specifications: models.Specifications{"weight": 1.2},
+		},
+	}
+}
+
+// TestExportItems_CSV: Las especificaciones se aplanan en columnas spec.* y los
+// filtros se pasan al servicio
+func TestExportItems_CSV(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	minPrice, minID, maxID := 5.0, int64(1), int64(10)
+	query := models.ItemQuery{MinPrice: &minPrice, MinID: &minID, MaxID: &maxID}
+	mockService.On("ExportItems", mock.Anything, query).
+		Return([]string{"memory", "touchscreen", "weight"}, exportTestItems(), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/export?min_price=5&min_id=1&max_id=10", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="items.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t,
+		"id,name,image_url,description,price,currency,rating,category_id,spec.memory,spec.touchscreen,spec.weight\n"+
+			"1,\"Laptop, A\",https://example.com/a.jpg,,999.5,USD,4.5,2,16GB,true,\n"+
+			"2,=Laptop B,https://example.com/b.jpg,Ligera,10,EUR,4,,,,1.2\n",
+		w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+// TestExportItems_CSVExcel: El modo Excel agrega el BOM, usa CRLF y neutraliza
+// las celdas que Excel interpretaría como fórmulas
+func TestExportItems_CSVExcel(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	mockService.On("ExportItems", mock.Anything, models.ItemQuery{}).
+		Return([]string{}, exportTestItems()[1:], nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/export?format=csv&excel=true", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		"\ufeffid,name,image_url,description,price,currency,rating,category_id\r\n"+
+			"2,'=Laptop B,https://example.com/b.jpg,Ligera,10,EUR,4,\r\n",
+		w.Body.String())
+}
+
+// TestExportItems_NDJSON: Cada item se escribe en una línea con sus especificaciones anidadas
+func TestExportItems_NDJSON(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	mockService.On("ExportItems", mock.Anything, models.ItemQuery{}).
+		Return([]string{"memory"}, exportTestItems(), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/export?format=ndjson", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var item models.Item
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &item))
+	assert.Equal(t, "Laptop, A", item.Name)
+	assert.Equal(t, "16GB", item.Specifications["memory"])
+}
+
+// TestExportItems_InvalidRequests: Los errores anteriores al primer byte se
+// responden con el formato de error habitual
+func TestExportItems_InvalidRequests(t *testing.T) {
+	t.Run("formato no soportado", func(t *testing.T) {
+		router := setupChiRouter(t, NewItemHandler(new(MockItemService)))
+
+		req := httptest.NewRequest("GET", "/api/v1/items/export?format=xml", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rango de IDs mal formado", func(t *testing.T) {
+		router := setupChiRouter(t, NewItemHandler(new(MockItemService)))
+
+		req := httptest.NewRequest("GET", "/api/v1/items/export?min_id=abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("error de validación del servicio", func(t *testing.T) {
+		mockService := new(MockItemService)
+		router := setupChiRouter(t, NewItemHandler(mockService))
+
+		minID, maxID := int64(10), int64(1)
+		mockService.On("ExportItems", mock.Anything, models.ItemQuery{MinID: &minID, MaxID: &maxID}).
+			Return(nil, nil, errors.NewValidationError("parámetros de exportación inválidos: min_id no puede ser mayor que max_id", nil))
+
+		req := httptest.NewRequest("GET", "/api/v1/items/export?min_id=10&max_id=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+}
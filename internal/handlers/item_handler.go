@@ -1,12 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"project/internal/errors"
 	"project/internal/models"
+	"project/internal/render"
 	"project/internal/services"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -14,94 +15,312 @@ import (
 // ItemHandler maneja las peticiones HTTP para los endpoints relacionados con items.
 type ItemHandler struct {
 	service services.ItemService
+
+	// encoders codifica los items y las comparaciones en el formato que pide el cliente.
+	encoders *render.Registry
 }
 
 // NewItemHandler crea una nueva instancia del handler de items.
 func NewItemHandler(service services.ItemService) *ItemHandler {
 	return &ItemHandler{
-		service: service,
+		service:  service,
+		encoders: render.Default(),
 	}
 }
 
 // GetAllItems maneja GET /api/v1/items
-// Devuelve todos los items en el sistema.
+// Devuelve una página de items filtrada y ordenada según los query params,
+// dentro de un sobre con el total y los enlaces a la página siguiente y anterior.
+// El ETag deriva de la versión del catálogo, que se lee antes de la consulta: si
+// el catálogo cambia entre ambas, el siguiente GET condicional recibe un 200.
+// Con currency, los precios dependen además de los tipos de cambio, por lo que el
+// ETag se calcula con la página ya convertida.
 func (h *ItemHandler) GetAllItems(w http.ResponseWriter, r *http.Request) {
-	items, err := h.service.GetAllItems(r.Context())
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	values := r.URL.Query()
+	query, err := parseItemQuery(values)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	state, err := h.service.CatalogState(r.Context())
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, items)
+	if query.Currency == "" && checkNotModified(w, r, itemListETag(state, values, encoder), state.UpdatedAt) {
+		return
+	}
+
+	page, err := h.service.GetAllItems(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if query.Currency != "" && checkNotModified(w, r, convertedItemListETag(state, values, page, encoder), time.Time{}) {
+		return
+	}
+
+	setPaginationLinks(r, page)
+	writeEncoded(w, encoder, http.StatusOK, page)
 }
 
 // GetItemByID maneja GET /api/v1/items/{id}
-// Devuelve un único item por su ID.
+// Devuelve un único item por su ID, con el precio convertido a la moneda del
+// query param currency si se indica.
 func (h *ItemHandler) GetItemByID(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	encoder, err := negotiate(w, r, h.encoders)
 	if err != nil {
-		domainErr := errors.NewBadRequestError(
-			"formato de id de item inválido",
-			err,
-		)
-		h.handleError(w, domainErr)
+		handleError(w, err)
+		return
+	}
+
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
 		return
 	}
 
 	item, err := h.service.GetItemByID(r.Context(), id)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
+		return
+	}
+
+	// El precio convertido cambia con los tipos de cambio, no solo con el item.
+	lastModified := item.UpdatedAt
+	if currency := r.URL.Query().Get("currency"); currency != "" {
+		items := []models.Item{*item}
+		if err := h.service.ConvertPrices(r.Context(), items, currency); err != nil {
+			handleError(w, err)
+			return
+		}
+		item, lastModified = &items[0], time.Time{}
+	}
+
+	if checkNotModified(w, r, itemETag(item, encoder), lastModified) {
+		return
+	}
+
+	writeEncoded(w, encoder, http.StatusOK, item)
+}
+
+// SearchItems maneja GET /api/v1/items/search?q=
+// Devuelve los items que coinciden con el texto, ordenados por relevancia.
+func (h *ItemHandler) SearchItems(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	limit, err := parseIntParam(values, "limit")
+	if err != nil {
+		handleError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, item)
+	response, err := h.service.SearchItems(r.Context(), values.Get("q"), limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
 }
 
-// CompareItems maneja POST /api/v1/items/compare
-// Recibe IDs de items y devuelve detalles de comparación.
+// CompareItems maneja POST /api/v1/items/compare?currency=
+// Recibe IDs de items y devuelve detalles de comparación, con los precios en la
+// moneda indicada. La comparación es una consulta: responde con un ETag derivado
+// de las versiones y los precios de los items y, si If-None-Match coincide, con
+// 304 en lugar de la comparación.
 func (h *ItemHandler) CompareItems(w http.ResponseWriter, r *http.Request) {
-	const maxBodySize = 1024 * 1024
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
 
 	var req models.CompareRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		domainErr := errors.NewBadRequestError(
-			"cuerpo de la petición (body) inválido",
-			err,
-		)
-		h.handleError(w, domainErr)
+	if err := decodeJSON(w, r, &req); err != nil {
+		handleError(w, err)
 		return
 	}
+	req.Currency = r.URL.Query().Get("currency")
 
-	response, err := h.service.CompareItems(r.Context(), req.ItemIDs)
+	response, err := h.service.CompareItems(r.Context(), req)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	if checkNotModified(w, r, comparisonETag(req, response, encoder), time.Time{}) {
+		return
+	}
+
+	writeEncoded(w, encoder, http.StatusOK, response)
 }
 
-// handleError procesa errores de dominio y escribe la respuesta HTTP apropiada.
-func (h *ItemHandler) handleError(w http.ResponseWriter, err error) {
-	domainErr, ok := err.(*errors.DomainError)
-	if !ok {
-		domainErr = errors.NewInternalServerError(
-			"un error inesperado ha ocurrido",
-			err,
-		)
+// ScoreItems maneja POST /api/v1/items/compare/score
+// Recibe IDs de items y pesos por criterio y devuelve el ranking ponderado.
+func (h *ItemHandler) ScoreItems(w http.ResponseWriter, r *http.Request) {
+	var req models.ScoreRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		handleError(w, err)
+		return
 	}
 
-	statusCode := domainErr.HTTPStatus()
-	errorResponse := domainErr.ToErrorResponse()
+	response, err := h.service.ScoreItems(r.Context(), req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
 
-	h.writeJSON(w, statusCode, errorResponse)
+	writeJSON(w, http.StatusOK, response)
 }
 
-// writeJSON escribe una respuesta JSON con las cabeceras y código de estado correctos.
-func (h *ItemHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+// CreateItem maneja POST /api/v1/items
+// Crea un nuevo item y lo devuelve con su ID asignado.
+func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var item models.Item
+	if err := decodeJSON(w, r, &item); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	created, err := h.service.CreateItem(r.Context(), item)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEncoded(w, encoder, http.StatusCreated, created)
+}
+
+// UpdateItem maneja PUT /api/v1/items/{id}
+// Reemplaza por completo un item existente. El reemplazo debe condicionarse a la
+// versión actual del item con "version" en el body (409 si ya no es la actual) o con
+// su ETag en If-Match (412); sin ninguno responde 428 (ver writeVersion).
+func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var item models.Item
+	if err := decodeJSON(w, r, &item); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var bodyVersion *int64
+	if item.Version != 0 {
+		bodyVersion = &item.Version
+	}
+	version, err := h.writeVersion(r, id, bodyVersion, encoder)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var updated *models.Item
+	if version != 0 {
+		updated, err = h.service.UpdateItemVersioned(r.Context(), id, version, item)
+	} else {
+		updated, err = h.service.UpdateItem(r.Context(), id, item)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", itemETag(updated, encoder))
+	writeEncoded(w, encoder, http.StatusOK, updated)
+}
+
+// PatchItem maneja PATCH /api/v1/items/{id}
+// Actualiza únicamente los campos enviados en el body. Como en UpdateItem, la
+// escritura debe condicionarse con un campo "version" o con If-Match.
+func (h *ItemHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var patch models.ItemPatch
+	if err := decodeJSON(w, r, &patch); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	version, err := h.writeVersion(r, id, patch.Version, encoder)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if version != 0 {
+		patch.Version = &version
+	}
+
+	updated, err := h.service.PatchItem(r.Context(), id, patch)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", itemETag(updated, encoder))
+	writeEncoded(w, encoder, http.StatusOK, updated)
+}
+
+// DeleteItem maneja DELETE /api/v1/items/{id}
+// Elimina un item y responde 204 sin contenido.
+func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := h.service.DeleteItem(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseItemID extrae y valida el parámetro {id} de la ruta.
+func (h *ItemHandler) parseItemID(r *http.Request) (int64, error) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError(
+			"formato de id de item inválido",
+			err,
+		)
+	}
+	return id, nil
 }
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"project/internal/errors"
 	"project/internal/models"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockItemService es una implementación mock de ItemService para pruebas
@@ -21,12 +24,12 @@ type MockItemService struct {
 	mock.Mock
 }
 
-func (m *MockItemService) GetAllItems(ctx context.Context) ([]models.Item, error) {
-	args := m.Called(ctx)
+func (m *MockItemService) GetAllItems(ctx context.Context, query models.ItemQuery) (*models.ItemPage, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Item), args.Error(1)
+	return args.Get(0).(*models.ItemPage), args.Error(1)
 }
 
 func (m *MockItemService) GetItemByID(ctx context.Context, id int64) (*models.Item, error) {
@@ -37,22 +40,173 @@ func (m *MockItemService) GetItemByID(ctx context.Context, id int64) (*models.It
 	return args.Get(0).(*models.Item), args.Error(1)
 }
 
-func (m *MockItemService) CompareItems(ctx context.Context, itemIDs []int64) (*models.CompareResponse, error) {
-	args := m.Called(ctx, itemIDs)
+func (m *MockItemService) CatalogState(ctx context.Context) (*models.CatalogState, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CatalogState), args.Error(1)
+}
+
+func (m *MockItemService) ConvertPrices(ctx context.Context, items []models.Item, currency string) error {
+	args := m.Called(ctx, items, currency)
+	return args.Error(0)
+}
+
+func (m *MockItemService) CompareItems(ctx context.Context, req models.CompareRequest) (*models.CompareResponse, error) {
+	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.CompareResponse), args.Error(1)
 }
 
+func (m *MockItemService) ScoreItems(ctx context.Context, req models.ScoreRequest) (*models.ScoreResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScoreResponse), args.Error(1)
+}
+
+func (m *MockItemService) SearchItems(ctx context.Context, text string, limit int) (*models.SearchResponse, error) {
+	args := m.Called(ctx, text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SearchResponse), args.Error(1)
+}
+
+func (m *MockItemService) CreateItem(ctx context.Context, item models.Item) (*models.Item, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) UpdateItem(ctx context.Context, id int64, item models.Item) (*models.Item, error) {
+	args := m.Called(ctx, id, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) UpdateItemVersioned(ctx context.Context, id int64, expectedVersion int64, item models.Item) (*models.Item, error) {
+	args := m.Called(ctx, id, expectedVersion, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) ItemRevisions(ctx context.Context, id int64) (*models.ItemRevisionHistory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemRevisionHistory), args.Error(1)
+}
+
+func (m *MockItemService) DiffItemRevisions(ctx context.Context, id, from, to int64) (*models.RevisionDiff, error) {
+	args := m.Called(ctx, id, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RevisionDiff), args.Error(1)
+}
+
+func (m *MockItemService) RestoreItemRevision(ctx context.Context, id, revision int64) (*models.Item, error) {
+	args := m.Called(ctx, id, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) PriceHistory(ctx context.Context, id int64, query models.PriceHistoryQuery) (*models.PriceHistory, error) {
+	args := m.Called(ctx, id, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceHistory), args.Error(1)
+}
+
+func (m *MockItemService) PatchItem(ctx context.Context, id int64, patch models.ItemPatch) (*models.Item, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) DeleteItem(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ImportItems consume todas las filas del lector para que los tests puedan
+// comprobar la decodificación del archivo.
+func (m *MockItemService) ImportItems(ctx context.Context, rows models.ImportReader, dryRun bool) (*models.ImportReport, error) {
+	var decoded []models.ImportRow
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, row)
+	}
+
+	args := m.Called(ctx, decoded, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportReport), args.Error(1)
+}
+
+// ExportItems escribe en w las claves y los items configurados en el mock. Si las
+// claves son nil, devuelve el error sin escribir nada, como un error de validación.
+func (m *MockItemService) ExportItems(ctx context.Context, query models.ItemQuery, w models.ExportWriter) error {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return args.Error(2)
+	}
+
+	if err := w.WriteHeader(args.Get(0).([]string)); err != nil {
+		return err
+	}
+	for _, item := range args.Get(1).([]models.Item) {
+		if err := w.WriteItem(item); err != nil {
+			return err
+		}
+	}
+	return args.Error(2)
+}
+
 // setupChiRouter crea un router chi real con el handler inyectado para tests más robustos
 func setupChiRouter(t *testing.T, handler *ItemHandler) *chi.Mux {
 	r := chi.NewRouter()
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/items", func(r chi.Router) {
 			r.Get("/", handler.GetAllItems)
+			r.Post("/", handler.CreateItem)
+			r.Get("/search", handler.SearchItems)
+			r.Post("/import", handler.ImportItems)
+			r.Get("/export", handler.ExportItems)
 			r.Get("/{id}", handler.GetItemByID)
+			r.Put("/{id}", handler.UpdateItem)
+			r.Patch("/{id}", handler.PatchItem)
+			r.Delete("/{id}", handler.DeleteItem)
+			r.Get("/{id}/revisions", handler.ItemRevisions)
+			r.Get("/{id}/revisions/diff", handler.DiffItemRevisions)
+			r.Post("/{id}/revisions/{rev}/restore", handler.RestoreItemRevision)
+			r.Get("/{id}/price-history", handler.PriceHistory)
 			r.Post("/compare", handler.CompareItems)
+			r.Post("/compare/score", handler.ScoreItems)
 		})
 	})
 	return r
@@ -89,7 +243,12 @@ func TestGetAllItems_OK(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetAllItems", mock.Anything).Return(items, nil)
+	page := &models.ItemPage{
+		Data:       items,
+		Pagination: models.Pagination{Total: 2, Limit: 20, Offset: 0},
+	}
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, models.ItemQuery{}).Return(page, nil)
 
 	req := httptest.NewRequest("GET", "/api/v1/items", nil)
 	w := httptest.NewRecorder()
@@ -99,14 +258,17 @@ func TestGetAllItems_OK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 
-	var response []models.Item
+	var response models.ItemPage
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Len(t, response, 2)
-	assert.Equal(t, items[0].ID, response[0].ID)
-	assert.Equal(t, items[0].Name, response[0].Name)
-	assert.Equal(t, items[1].ID, response[1].ID)
-	assert.Equal(t, items[1].Name, response[1].Name)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, items[0].ID, response.Data[0].ID)
+	assert.Equal(t, items[0].Name, response.Data[0].Name)
+	assert.Equal(t, items[1].ID, response.Data[1].ID)
+	assert.Equal(t, items[1].Name, response.Data[1].Name)
+	assert.Equal(t, 2, response.Pagination.Total)
+	assert.Nil(t, response.Pagination.Next)
+	assert.Nil(t, response.Pagination.Prev)
 
 	mockService.AssertExpectations(t)
 }
@@ -117,7 +279,9 @@ func TestGetAllItems_EmptyList(t *testing.T) {
 	handler := NewItemHandler(mockService)
 	router := setupChiRouter(t, handler)
 
-	mockService.On("GetAllItems", mock.Anything).Return([]models.Item{}, nil)
+	page := &models.ItemPage{Data: []models.Item{}, Pagination: models.Pagination{Limit: 20}}
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, models.ItemQuery{}).Return(page, nil)
 
 	req := httptest.NewRequest("GET", "/api/v1/items", nil)
 	w := httptest.NewRecorder()
@@ -127,10 +291,11 @@ func TestGetAllItems_EmptyList(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 
-	var response []models.Item
+	var response models.ItemPage
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Empty(t, response)
+	assert.Empty(t, response.Data)
+	assert.Equal(t, 0, response.Pagination.Total)
 
 	mockService.AssertExpectations(t)
 }
@@ -142,7 +307,8 @@ func TestGetAllItems_InternalError(t *testing.T) {
 	router := setupChiRouter(t, handler)
 
 	domainErr := errors.NewInternalServerError("error al obtener los items", nil)
-	mockService.On("GetAllItems", mock.Anything).Return(nil, domainErr)
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, models.ItemQuery{}).Return(nil, domainErr)
 
 	req := httptest.NewRequest("GET", "/api/v1/items", nil)
 	w := httptest.NewRecorder()
@@ -161,6 +327,98 @@ func TestGetAllItems_InternalError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestGetAllItems_PaginationAndFilters: Los query params se traducen a ItemQuery
+// y el sobre incluye los enlaces next/prev conservando los filtros
+func TestGetAllItems_PaginationAndFilters(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	minPrice := 1000.0
+	minRating := 4.5
+	expectedQuery := models.ItemQuery{
+		Limit:     2,
+		Offset:    2,
+		Sort:      []models.SortField{{Field: "price"}, {Field: "rating", Desc: true}},
+		MinPrice:  &minPrice,
+		MinRating: &minRating,
+	}
+	page := &models.ItemPage{
+		Data:       []models.Item{{ID: 3}, {ID: 4}},
+		Pagination: models.Pagination{Total: 5, Limit: 2, Offset: 2},
+	}
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, expectedQuery).Return(page, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items?limit=2&offset=2&sort=price,-rating&min_price=1000&min_rating=4.5", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ItemPage
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, response.Pagination.Total)
+	if assert.NotNil(t, response.Pagination.Next) {
+		assert.Contains(t, *response.Pagination.Next, "offset=4")
+		assert.Contains(t, *response.Pagination.Next, "min_price=1000")
+		assert.Contains(t, *response.Pagination.Next, "sort=price%2C-rating")
+	}
+	if assert.NotNil(t, response.Pagination.Prev) {
+		assert.Contains(t, *response.Pagination.Prev, "offset=0")
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetAllItems_SpecFilters: Los parámetros spec.<clave>[_op] se traducen a SpecFilter
+func TestGetAllItems_SpecFilters(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	expectedQuery := models.ItemQuery{SpecFilters: []models.SpecFilter{
+		{Key: "memory", Operator: models.SpecOpEquals, Value: "32GB"},
+		{Key: "processor", Operator: models.SpecOpContains, Value: "M2"},
+		{Key: "weight", Operator: models.SpecOpLessEqual, Value: "2kg"},
+	}}
+	page := &models.ItemPage{Data: []models.Item{}, Pagination: models.Pagination{Limit: 20}}
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, expectedQuery).Return(page, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items?spec.weight_lte=2kg&spec.memory=32GB&spec.processor_contains=M2", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestGetAllItems_InvalidParam: Un parámetro no numérico debe devolver 400 sin llamar al servicio
+func TestGetAllItems_InvalidParam(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	req := httptest.NewRequest("GET", "/api/v1/items?limit=abc", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeBadRequest, errorResp.Code)
+	assert.Contains(t, errorResp.Message, "limit")
+
+	mockService.AssertExpectations(t)
+}
+
 // TestGetItemByID_OK: Happy path (200), valida el body del JSON
 func TestGetItemByID_OK(t *testing.T) {
 	mockService := new(MockItemService)
@@ -300,7 +558,7 @@ func TestCompareItems_OK(t *testing.T) {
 		},
 	}
 
-	mockService.On("CompareItems", mock.Anything, []int64{1, 2}).Return(expectedResponse, nil)
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1, 2}}).Return(expectedResponse, nil)
 
 	requestBody := models.CompareRequest{ItemIDs: []int64{1, 2}}
 	bodyBytes, _ := json.Marshal(requestBody)
@@ -358,7 +616,7 @@ func TestCompareItems_ValidationError(t *testing.T) {
 	router := setupChiRouter(t, handler)
 
 	domainErr := errors.NewValidationError("se requieren al menos 2 items para comparar", nil)
-	mockService.On("CompareItems", mock.Anything, []int64{1}).Return(nil, domainErr)
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1}}).Return(nil, domainErr)
 
 	requestBody := models.CompareRequest{ItemIDs: []int64{1}}
 	bodyBytes, _ := json.Marshal(requestBody)
@@ -389,7 +647,7 @@ func TestCompareItems_NotFound(t *testing.T) {
 	router := setupChiRouter(t, handler)
 
 	domainErr := errors.NewNotFoundError("Items con IDs [999]", nil)
-	mockService.On("CompareItems", mock.Anything, []int64{1, 999}).Return(nil, domainErr)
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1, 999}}).Return(nil, domainErr)
 
 	requestBody := models.CompareRequest{ItemIDs: []int64{1, 999}}
 	bodyBytes, _ := json.Marshal(requestBody)
@@ -441,3 +699,634 @@ func TestCompareItems_BodyTooLarge(t *testing.T) {
 	// Verificar que el servicio NO fue llamado
 	mockService.AssertExpectations(t)
 }
+
+// TestCreateItem_OK: Happy path (201), valida el item devuelto
+func TestCreateItem_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	input := models.Item{
+		Name:           "New Item",
+		ImageURL:       "https://example.com/new.jpg",
+		Description:    "New Description",
+		Price:          99.99,
+		Rating:         4.2,
+		Specifications: models.Specifications{"color": "black"},
+	}
+	created := input
+	created.ID = 6
+
+	mockService.On("CreateItem", mock.Anything, input).Return(&created, nil)
+
+	bodyBytes, _ := json.Marshal(input)
+	req := httptest.NewRequest("POST", "/api/v1/items", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response models.Item
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), response.ID)
+	assert.Equal(t, "New Item", response.Name)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestCreateItem_BadRequest_MalformedJSON: Envía un JSON inválido. Debe devolver 400 sin llamar al servicio
+func TestCreateItem_BadRequest_MalformedJSON(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	req := httptest.NewRequest("POST", "/api/v1/items", bytes.NewReader([]byte(`{"name": `)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeBadRequest, errorResp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateItem_ValidationError: El servicio devuelve ValidationError. Debe devolver 422
+func TestUpdateItem_ValidationError(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	input := models.Item{Name: "", Price: 10, Rating: 4, Version: 2}
+	domainErr := errors.NewValidationError("item inválido: el nombre es obligatorio", nil)
+	mockService.On("UpdateItemVersioned", mock.Anything, int64(1), int64(2), input).Return(nil, domainErr)
+
+	bodyBytes, _ := json.Marshal(input)
+	req := httptest.NewRequest("PUT", "/api/v1/items/1", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeValidation, errorResp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateItem_VersionConflict: Con "version" en el body se usa la actualización
+// versionada; si la versión no es la actual debe devolver 409
+func TestUpdateItem_VersionConflict(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	input := models.Item{Name: "Item 1", Price: 10, Rating: 4, Version: 3}
+	domainErr := errors.NewConflictError("el item 1 fue modificado y ya no está en la versión 3; obténgalo de nuevo y reintente", nil)
+	mockService.On("UpdateItemVersioned", mock.Anything, int64(1), int64(3), input).Return(nil, domainErr)
+
+	bodyBytes, _ := json.Marshal(input)
+	req := httptest.NewRequest("PUT", "/api/v1/items/1", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeConflict, errorResp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestCreateItem_ValidationDetails: Los errores por campo se incluyen en details
+func TestCreateItem_ValidationDetails(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	categoryID := int64(2)
+	input := models.Item{Name: "Laptop", Price: 10, Rating: 4, CategoryID: &categoryID}
+	details := []errors.FieldError{
+		{Field: "specifications.memory", Message: `especificación "memory": es obligatoria`},
+	}
+	domainErr := errors.NewFieldValidationError("item inválido: especificación \"memory\": es obligatoria", details)
+	mockService.On("CreateItem", mock.Anything, input).Return(nil, domainErr)
+
+	bodyBytes, _ := json.Marshal(input)
+	req := httptest.NewRequest("POST", "/api/v1/items", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeValidation, errorResp.Code)
+	assert.Equal(t, details, errorResp.Details)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestPatchItem_OK: Solo se envía el precio, condicionado con el ETag del item en
+// If-Match. Debe devolver 200 con el item actualizado y su nuevo ETag
+func TestPatchItem_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	current := &models.Item{ID: 1, Name: "Item 1", Price: 89.99, Rating: 4.5, Version: 4}
+	newPrice := 79.99
+	version := int64(4)
+	updated := &models.Item{ID: 1, Name: "Item 1", Price: newPrice, Rating: 4.5, Version: 5}
+	mockService.On("GetItemByID", mock.Anything, int64(1)).Return(current, nil)
+	mockService.On("PatchItem", mock.Anything, int64(1), models.ItemPatch{Price: &newPrice, Version: &version}).Return(updated, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/items/1", nil))
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/items/1", bytes.NewReader([]byte(`{"price": 79.99}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	var response models.Item
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, newPrice, response.Price)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateItem_Preconditions: Una escritura sin "version" ni If-Match se rechaza
+// con 428, un If-Match que no coincide con 412 e If-Match: * sobrescribe el item
+func TestUpdateItem_Preconditions(t *testing.T) {
+	current := &models.Item{ID: 1, Name: "Item 1", Price: 10, Rating: 4, Version: 4}
+	input := models.Item{Name: "Item 1", Price: 12, Rating: 4}
+
+	tests := []struct {
+		name    string
+		method  string
+		ifMatch string
+		status  int
+		code    errors.ErrorCode
+	}{
+		{"PUT without version", "PUT", "", http.StatusPreconditionRequired, errors.ErrorCodePreconditionRequired},
+		{"PATCH without version", "PATCH", "", http.StatusPreconditionRequired, errors.ErrorCodePreconditionRequired},
+		{"stale ETag", "PUT", `"0123456789abcdef0123456789abcdef"`, http.StatusPreconditionFailed, errors.ErrorCodePreconditionFailed},
+		{"weak ETag", "PATCH", `W/"0123456789abcdef0123456789abcdef"`, http.StatusPreconditionFailed, errors.ErrorCodePreconditionFailed},
+		{"explicit override", "PUT", "*", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockItemService)
+			router := setupChiRouter(t, NewItemHandler(mockService))
+			mockService.On("GetItemByID", mock.Anything, int64(1)).Return(current, nil).Maybe()
+			mockService.On("UpdateItem", mock.Anything, int64(1), input).Return(&models.Item{ID: 1, Version: 5}, nil).Maybe()
+
+			bodyBytes, _ := json.Marshal(input)
+			req := httptest.NewRequest(tt.method, "/api/v1/items/1", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.code != "" {
+				var errorResp errors.ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResp))
+				assert.Equal(t, tt.code, errorResp.Code)
+				mockService.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+				mockService.AssertNotCalled(t, "PatchItem", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestDeleteItem_NoContent: Happy path (204) sin body
+func TestDeleteItem_NoContent(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	mockService.On("DeleteItem", mock.Anything, int64(1)).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/v1/items/1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+// TestDeleteItem_NotFound: El servicio devuelve NotFound. Debe devolver 404
+func TestDeleteItem_NotFound(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	mockService.On("DeleteItem", mock.Anything, int64(999)).Return(errors.NewNotFoundError("Item", 999))
+
+	req := httptest.NewRequest("DELETE", "/api/v1/items/999", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestSearchItems_OK: Happy path (200), la ruta /search no debe confundirse con /{id}
+func TestSearchItems_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	expected := &models.SearchResponse{
+		Query: "oled",
+		Results: []models.SearchResult{
+			{Item: models.Item{ID: 3, Name: "HP Spectre x360"}, Score: 1.5, Snippet: "13.5-inch <mark>OLED</mark>"},
+		},
+	}
+	mockService.On("SearchItems", mock.Anything, "oled", 5).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/search?q=oled&limit=5", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.SearchResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "oled", response.Query)
+	assert.Len(t, response.Results, 1)
+	assert.Contains(t, response.Results[0].Snippet, "<mark>OLED</mark>")
+
+	mockService.AssertExpectations(t)
+}
+
+// TestScoreItems_OK: Happy path (200), el handler pasa los pesos al servicio
+func TestScoreItems_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	requestBody := models.ScoreRequest{
+		ItemIDs: []int64{1, 2},
+		Weights: map[string]float64{"price": 50, "battery_life": 30, "weight": 20},
+	}
+	expectedResponse := &models.ScoreResponse{
+		Weights: map[string]float64{"price": 0.5, "battery_life": 0.3, "weight": 0.2},
+		Ranking: []models.ItemScore{
+			{Rank: 1, ItemID: 2, Name: "Item 2", Score: 80},
+			{Rank: 2, ItemID: 1, Name: "Item 1", Score: 30},
+		},
+	}
+	mockService.On("ScoreItems", mock.Anything, requestBody).Return(expectedResponse, nil)
+
+	bodyBytes, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest("POST", "/api/v1/items/compare/score", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ScoreResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Ranking, 2)
+	assert.Equal(t, int64(2), response.Ranking[0].ItemID)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestScoreItems_UnknownCriterion: El servicio rechaza un criterio desconocido.
+// Debe devolver 422 (VALIDATION_ERROR)
+func TestScoreItems_UnknownCriterion(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	requestBody := models.ScoreRequest{
+		ItemIDs: []int64{1, 2},
+		Weights: map[string]float64{"colour": 1},
+	}
+	domainErr := errors.NewValidationError("criterios desconocidos o no cuantificables para estos items: colour", nil)
+	mockService.On("ScoreItems", mock.Anything, requestBody).Return(nil, domainErr)
+
+	bodyBytes, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest("POST", "/api/v1/items/compare/score", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var errorResp errors.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.ErrorCodeValidation, errorResp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetItemByID_AcceptXML: El item se codifica en el formato pedido en Accept
+func TestGetItemByID_AcceptXML(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	item := &models.Item{ID: 1, Name: "Test Item", Price: 100, Rating: 4.5, Specifications: models.Specifications{}}
+	mockService.On("GetItemByID", mock.Anything, int64(1)).Return(item, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/1", nil)
+	req.Header.Set("Accept", "application/json;q=0.5, application/xml")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.Contains(t, w.Body.String(), "<item><id>1</id><name>Test Item</name>")
+}
+
+// TestGetAllItems_AcceptMessagePack: El listado se codifica en MessagePack
+func TestGetAllItems_AcceptMessagePack(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	page := &models.ItemPage{Data: []models.Item{}, Pagination: models.Pagination{Limit: 20}}
+	mockService.On("CatalogState", mock.Anything).Return(&models.CatalogState{Version: 1}, nil)
+	mockService.On("GetAllItems", mock.Anything, models.ItemQuery{}).Return(page, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items", nil)
+	req.Header.Set("Accept", "application/x-msgpack")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+	// Mapa de 2 campos cuyo primero es "data", un array vacío.
+	assert.Equal(t, []byte{0x82, 0xa4, 'd', 'a', 't', 'a', 0x90}, w.Body.Bytes()[:7])
+}
+
+// TestCompareItems_AcceptCSV: La comparación se codifica como una matriz con una columna por item
+func TestCompareItems_AcceptCSV(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	response := &models.CompareResponse{
+		Items: []models.Item{
+			{ID: 1, Name: "Item 1", Price: 100, Currency: "EUR", Rating: 4.5, Specifications: models.Specifications{"color": "red"}},
+			{ID: 2, Name: "Item 2", Price: 200, Currency: "EUR", Rating: 4, Specifications: models.Specifications{"color": "blue"}},
+		},
+	}
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1, 2}}).Return(response, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/items/compare", strings.NewReader(`{"item_ids": [1, 2]}`))
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t,
+		"id,1,2\nname,Item 1,Item 2\nimage_url,,\ndescription,,\nprice,100,200\ncurrency,EUR,EUR\nrating,4.5,4\ncategory_id,,\nspec.color,red,blue\n",
+		w.Body.String())
+}
+
+// TestCreateItem_NotAcceptable: Si ningún formato es aceptable se responde 406 en JSON
+// sin crear el item
+func TestCreateItem_NotAcceptable(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService)
+	router := setupChiRouter(t, handler)
+
+	req := httptest.NewRequest("POST", "/api/v1/items", strings.NewReader(`{"name": "New Item"}`))
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response errors.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, errors.ErrorCodeNotAcceptable, response.Code)
+	assert.Contains(t, response.Message, "application/json, application/xml")
+
+	mockService.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
+
+// TestItemRevisions_OK: Devuelve el historial del item
+func TestItemRevisions_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	history := &models.ItemRevisionHistory{
+		ItemID: 1,
+		Revisions: []models.ItemRevision{
+			{ItemID: 1, Revision: 1, Operation: models.RevisionCreate, Actor: "system", Source: models.SourceSeed},
+		},
+	}
+	mockService.On("ItemRevisions", mock.Anything, int64(1)).Return(history, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/1/revisions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ItemRevisionHistory
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, history.Revisions[0].Source, response.Revisions[0].Source)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestDiffItemRevisions_Params: from es obligatorio y to es opcional
+func TestDiffItemRevisions_Params(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	mockService.On("DiffItemRevisions", mock.Anything, int64(1), int64(2), int64(0)).
+		Return(&models.RevisionDiff{ItemID: 1, From: 2, To: 5, Changes: []models.FieldChange{}}, nil)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "latest", query: "from=2", wantStatus: http.StatusOK},
+		{name: "missing from", query: "to=3", wantStatus: http.StatusBadRequest},
+		{name: "invalid to", query: "from=2&to=abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/items/1/revisions/diff?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+// TestRestoreItemRevision_OK: Devuelve el item restaurado
+func TestRestoreItemRevision_OK(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	restored := &models.Item{ID: 1, Name: "Item 1", Price: 100, Specifications: models.Specifications{}, Version: 4}
+	mockService.On("RestoreItemRevision", mock.Anything, int64(1), int64(2)).Return(restored, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/items/1/revisions/2/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Item
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(4), response.Version)
+
+	req = httptest.NewRequest("POST", "/api/v1/items/1/revisions/abc/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestPriceHistory_Params: days e interval se pasan al servicio
+func TestPriceHistory_Params(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	query := models.PriceHistoryQuery{Days: 90, Interval: models.PriceIntervalWeekly, Currency: "eur"}
+	history := &models.PriceHistory{ItemID: 1, CurrentPrice: 80, Interval: models.PriceIntervalWeekly}
+	mockService.On("PriceHistory", mock.Anything, int64(1), query).Return(history, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/items/1/price-history?days=90&interval=weekly&currency=eur", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.PriceHistory
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 80.0, response.CurrentPrice)
+
+	req = httptest.NewRequest("GET", "/api/v1/items/1/price-history?days=many", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetItemByID_Currency: Con currency el precio se convierte antes de responder y
+// el ETag cambia con el precio convertido
+func TestGetItemByID_Currency(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	item := &models.Item{ID: 1, Name: "Item 1", Price: 100, Currency: "USD", Specifications: models.Specifications{}, Version: 1}
+	mockService.On("GetItemByID", mock.Anything, int64(1)).Return(item, nil)
+	mockService.On("ConvertPrices", mock.Anything, mock.Anything, "eur").Return(nil).Run(func(args mock.Arguments) {
+		items := args.Get(1).([]models.Item)
+		items[0].Price, items[0].Currency = 92, "EUR"
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/items/1?currency=eur", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.Item
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 92.0, response.Price)
+	assert.Equal(t, "EUR", response.Currency)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+	convertedETag := w.Header().Get("ETag")
+
+	req = httptest.NewRequest("GET", "/api/v1/items/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.NotEqual(t, convertedETag, w.Header().Get("ETag"))
+	assert.Equal(t, 100.0, item.Price)
+
+	mockService.On("ConvertPrices", mock.Anything, mock.Anything, "GBP").
+		Return(errors.NewValidationError("moneda no soportada", nil))
+	req = httptest.NewRequest("GET", "/api/v1/items/1?currency=GBP", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+// TestCompareItems_Currency: La moneda de la comparación llega como query param
+func TestCompareItems_Currency(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	response := &models.CompareResponse{Comparison: models.ComparisonDetails{Currency: "COP"}}
+	mockService.On("CompareItems", mock.Anything, models.CompareRequest{ItemIDs: []int64{1, 2}, Currency: "COP"}).
+		Return(response, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/items/compare?currency=COP", strings.NewReader(`{"item_ids": [1, 2]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"currency":"COP"`)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"project/internal/errors"
+	"project/internal/models"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxImportBodySize limita el tamaño del archivo de importación. El cuerpo se
+	// copia a un archivo temporal y después se lee en streaming, fila a fila, por lo
+	// que el límite no implica cargarlo en memoria.
+	maxImportBodySize = 64 << 20
+
+	// maxNDJSONLineSize limita el tamaño de cada línea de un archivo NDJSON.
+	maxNDJSONLineSize = 1 << 20
+)
+
+// importMediaTypes asocia los Content-Type aceptados con su formato de importación.
+var importMediaTypes = map[string]string{
+	"text/csv":             models.ImportFormatCSV,
+	"application/csv":      models.ImportFormatCSV,
+	"application/x-ndjson": models.ImportFormatNDJSON,
+	"application/ndjson":   models.ImportFormatNDJSON,
+	"application/jsonl":    models.ImportFormatNDJSON,
+}
+
+// ImportItems maneja POST /api/v1/items/import
+// Importa items desde un archivo CSV o NDJSON enviado como cuerpo de la petición.
+// El formato se toma del parámetro format o, si no se indica, del Content-Type.
+// Con dry_run=true las filas se validan y aplican, pero no se guarda ningún cambio.
+func (h *ItemHandler) ImportItems(w http.ResponseWriter, r *http.Request) {
+	format, err := importFormat(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	dryRun, err := parseBoolParam(r.URL.Query(), "dry_run")
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	body, err := spoolImportBody(http.MaxBytesReader(w, r.Body, maxImportBodySize))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	defer func() {
+		body.Close()
+		os.Remove(body.Name())
+	}()
+
+	var rows models.ImportReader
+	if format == models.ImportFormatCSV {
+		rows, err = newCSVImportReader(body)
+	} else {
+		rows = newNDJSONImportReader(body)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	report, err := h.service.ImportItems(r.Context(), rows, dryRun)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// importFormat determina el formato del archivo de importación.
+func importFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format != models.ImportFormatCSV && format != models.ImportFormatNDJSON {
+			return "", errors.NewBadRequestError(fmt.Sprintf("formato de importación no soportado: %q (use csv o ndjson)", format), nil)
+		}
+		return format, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if format, ok := importMediaTypes[mediaType]; ok {
+		return format, nil
+	}
+
+	return "", errors.NewUnsupportedMediaTypeError(
+		"formato de importación no soportado: use Content-Type text/csv o application/x-ndjson, o el parámetro format=csv|ndjson",
+	)
+}
+
+// parseBoolParam lee un query param booleano opcional; ausente equivale a false.
+func parseBoolParam(values url.Values, name string) (bool, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.NewBadRequestError(fmt.Sprintf("el parámetro %s debe ser true o false", name), err)
+	}
+	return value, nil
+}
+
+// importReadError traduce un error de lectura del cuerpo a un error de dominio.
+// spoolImportBody copia el cuerpo de la petición a un archivo temporal y lo deja
+// listo para leerse desde el principio. Así la importación se aplica después de
+// recibir el archivo completo y no retiene la transacción mientras el cliente lo
+// envía, sin cargarlo en memoria. El llamador debe cerrar y eliminar el archivo.
+func spoolImportBody(body io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "items-import-*")
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al crear el archivo temporal de importación", err)
+	}
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, importReadError(err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, errors.NewInternalServerError("error al leer el archivo temporal de importación", err)
+	}
+	return file, nil
+}
+
+func importReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if stdErrors.As(err, &maxBytesErr) {
+		return errors.NewBadRequestError(fmt.Sprintf("el archivo supera el tamaño máximo de %d MB", maxImportBodySize>>20), err)
+	}
+	return errors.NewBadRequestError("error al leer el archivo de importación", err)
+}
+
+// csvColumns enumera las columnas de item admitidas en un CSV, además de las
+// columnas de especificación con prefijo "spec.".
+var csvColumns = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"image_url":   true,
+	"description": true,
+	"price":       true,
+	"currency":    true,
+	"rating":      true,
+	"category_id": true,
+}
+
+// csvRequiredColumns son las columnas que debe incluir el encabezado del CSV.
+var csvRequiredColumns = []string{"name", "image_url", "price", "rating"}
+
+// csvImportReader decodifica un CSV con encabezado, una fila por item.
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCSVImportReader lee y valida el encabezado del CSV. Un encabezado con
+// columnas desconocidas, repetidas o sin las obligatorias impide la importación.
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewBadRequestError("el archivo CSV está vacío", nil)
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if stdErrors.As(err, &parseErr) {
+			return nil, errors.NewBadRequestError("encabezado CSV inválido", err)
+		}
+		return nil, importReadError(err)
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	var problems []string
+
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if i == 0 {
+			// Las hojas de cálculo suelen guardar el CSV con BOM UTF-8.
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		columns[i] = column
+
+		switch {
+		case seen[column]:
+			problems = append(problems, fmt.Sprintf("columna repetida: %q", column))
+		case strings.HasPrefix(column, specParamPrefix):
+			if strings.TrimPrefix(column, specParamPrefix) == "" {
+				problems = append(problems, fmt.Sprintf("columna de especificación sin clave: %q", column))
+			}
+		case !csvColumns[column]:
+			problems = append(problems, fmt.Sprintf("columna desconocida: %q", column))
+		}
+		seen[column] = true
+	}
+
+	for _, column := range csvRequiredColumns {
+		if !seen[column] {
+			problems = append(problems, fmt.Sprintf("falta la columna %q", column))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.NewBadRequestError("encabezado CSV inválido: "+strings.Join(problems, "; "), nil)
+	}
+
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+// Next decodifica la siguiente fila del CSV. Las filas mal formadas se devuelven
+// con sus problemas para que se rechacen sin interrumpir la importación.
+func (c *csvImportReader) Next() (models.ImportRow, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return models.ImportRow{}, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if stdErrors.As(err, &parseErr) {
+		return models.ImportRow{
+			Line:     parseErr.StartLine,
+			Problems: []errors.FieldError{{Message: "fila CSV inválida: " + parseErr.Err.Error()}},
+		}, nil
+	}
+	if err != nil {
+		return models.ImportRow{}, importReadError(err)
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	row := models.ImportRow{Line: line, Item: models.Item{Specifications: models.Specifications{}}}
+
+	if len(record) != len(c.columns) {
+		row.Problems = append(row.Problems, errors.FieldError{
+			Message: fmt.Sprintf("la fila tiene %d columnas y el encabezado %d", len(record), len(c.columns)),
+		})
+		return row, nil
+	}
+
+	for i, column := range c.columns {
+		row.Problems = append(row.Problems, setCSVField(&row.Item, column, strings.TrimSpace(record[i]))...)
+	}
+
+	return row, nil
+}
+
+// setCSVField asigna el valor de una celda al campo correspondiente del item.
+// Las celdas vacías de campos opcionales y de especificaciones se ignoran.
+func setCSVField(item *models.Item, column, value string) []errors.FieldError {
+	if key := strings.TrimPrefix(column, specParamPrefix); key != column {
+		if value != "" {
+			item.Specifications[key] = value
+		}
+		return nil
+	}
+
+	var err error
+	switch column {
+	case "name":
+		item.Name = value
+	case "image_url":
+		item.ImageURL = value
+	case "description":
+		item.Description = value
+	case "id":
+		if value != "" {
+			if item.ID, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return []errors.FieldError{{Field: "id", Message: "el id debe ser un número entero"}}
+			}
+		}
+	case "price":
+		if value != "" {
+			if item.Price, err = strconv.ParseFloat(value, 64); err != nil {
+				return []errors.FieldError{{Field: "price", Message: "el precio debe ser un número"}}
+			}
+		}
+	case "currency":
+		item.Currency = value
+	case "rating":
+		if value != "" {
+			if item.Rating, err = strconv.ParseFloat(value, 64); err != nil {
+				return []errors.FieldError{{Field: "rating", Message: "el rating debe ser un número"}}
+			}
+		}
+	case "category_id":
+		if value != "" {
+			categoryID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return []errors.FieldError{{Field: "category_id", Message: "category_id debe ser un número entero"}}
+			}
+			item.CategoryID = &categoryID
+		}
+	}
+	return nil
+}
+
+// ndjsonImportReader decodifica un archivo NDJSON: un objeto item JSON por línea,
+// con los mismos campos que el cuerpo de POST /items (y opcionalmente id).
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// newNDJSONImportReader crea un lector NDJSON sobre el cuerpo de la petición.
+func newNDJSONImportReader(body io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+// Next decodifica la siguiente línea no vacía. Una línea con JSON inválido o con
+// campos desconocidos se devuelve con sus problemas para que se rechace.
+func (n *ndjsonImportReader) Next() (models.ImportRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+		data := bytes.TrimSpace(n.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := models.ImportRow{Line: n.line}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.Item); err != nil {
+			row.Problems = []errors.FieldError{{Message: "JSON inválido: " + err.Error()}}
+		}
+		return row, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		if stdErrors.Is(err, bufio.ErrTooLong) {
+			return models.ImportRow{}, errors.NewBadRequestError(
+				fmt.Sprintf("la línea %d supera el tamaño máximo de %d KB", n.line+1, maxNDJSONLineSize>>10), err,
+			)
+		}
+		return models.ImportRow{}, importReadError(err)
+	}
+
+	return models.ImportRow{}, io.EOF
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/errors"
+	"project/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportItems_CSV: Las columnas spec.* se convierten en especificaciones y los
+// valores mal formados se marcan como problemas de la fila
+func TestImportItems_CSV(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	body := "\ufeffname,image_url,price,rating,category_id,spec.memory,spec.storage\n" +
+		"Laptop A,https://example.com/a.jpg,999.5,4.5,2,16GB,512GB SSD\n" +
+		"\"Laptop, B\",https://example.com/b.jpg,cheap,4,,,\n" +
+		"Laptop C,https://example.com/c.jpg\n"
+
+	categoryID := int64(2)
+	expected := []models.ImportRow{
+		{Line: 2, Item: models.Item{
+			Name: "Laptop A", ImageURL: "https://example.com/a.jpg", Price: 999.5, Rating: 4.5, CategoryID: &categoryID,
+			Specifications: models.Specifications{"memory": "16GB", "storage": "512GB SSD"},
+		}},
+		{Line: 3, Item: models.Item{
+			Name: "Laptop, B", ImageURL: "https://example.com/b.jpg", Rating: 4,
+			Specifications: models.Specifications{},
+		}, Problems: []errors.FieldError{{Field: "price", Message: "el precio debe ser un número"}}},
+		{Line: 4, Item: models.Item{Specifications: models.Specifications{}}, Problems: []errors.FieldError{
+			{Message: "la fila tiene 2 columnas y el encabezado 7"},
+		}},
+	}
+	report := &models.ImportReport{Total: 3, Accepted: []models.ImportAccepted{}, Rejected: []models.ImportRejected{}}
+	mockService.On("ImportItems", mock.Anything, expected, true).Return(report, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/items/import?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestImportItems_NDJSON: Cada línea es un item; las líneas vacías se ignoran y el
+// JSON inválido se marca como problema de la fila
+func TestImportItems_NDJSON(t *testing.T) {
+	mockService := new(MockItemService)
+	router := setupChiRouter(t, NewItemHandler(mockService))
+
+	body := `{"id": 3, "name": "Laptop A", "image_url": "https://example.com/a.jpg", "price": 10, "rating": 4}` + "\n" +
+		"\n" +
+		`{"name": "Laptop B", "colour": "red"}` + "\n"
+
+	mockService.On("ImportItems", mock.Anything, mock.MatchedBy(func(rows []models.ImportRow) bool {
+		return len(rows) == 2 &&
+			rows[0].Line == 1 && rows[0].Item.ID == 3 && rows[0].Problems == nil &&
+			rows[1].Line == 3 && len(rows[1].Problems) == 1 && strings.Contains(rows[1].Problems[0].Message, "colour")
+	}), false).Return(&models.ImportReport{Total: 2}, nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/items/import?format=ndjson", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestImportItems_InvalidRequests: Formato no soportado, encabezado inválido o
+// dry_run mal formado se rechazan sin llamar al servicio
+func TestImportItems_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"sin formato", "/api/v1/items/import", "application/json", "{}", http.StatusUnsupportedMediaType, "formato de importación no soportado"},
+		{"formato desconocido", "/api/v1/items/import?format=xml", "", "", http.StatusBadRequest, `"xml"`},
+		{"dry_run inválido", "/api/v1/items/import?dry_run=maybe", "text/csv", "", http.StatusBadRequest, "dry_run"},
+		{"CSV vacío", "/api/v1/items/import", "text/csv", "", http.StatusBadRequest, "vacío"},
+		{
+			"columnas inválidas", "/api/v1/items/import", "text/csv", "name,colour,spec.\n",
+			http.StatusBadRequest, `columna desconocida: "colour"; columna de especificación sin clave: "spec."; falta la columna "image_url"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockItemService)
+			router := setupChiRouter(t, NewItemHandler(mockService))
+
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+
+			var errorResp errors.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResp))
+			assert.Contains(t, errorResp.Message, tt.message)
+
+			mockService.AssertNotCalled(t, "ImportItems", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
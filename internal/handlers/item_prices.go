@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"project/internal/models"
+)
+
+// PriceHistory maneja GET /api/v1/items/{id}/price-history?days=&interval=&currency=
+// Devuelve los precios del item en los últimos days días (30 por defecto) con su
+// mínimo, máximo y promedio, agrupados por día o semana si se indica interval y
+// convertidos a currency si se indica una moneda.
+func (h *ItemHandler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	values := r.URL.Query()
+	days, err := parseIntParam(values, "days")
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	history, err := h.service.PriceHistory(r.Context(), id, models.PriceHistoryQuery{
+		Days:     days,
+		Interval: values.Get("interval"),
+		Currency: values.Get("currency"),
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"project/internal/errors"
+	"project/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// specParamPrefix es el prefijo de los query params que filtran por especificación.
+const specParamPrefix = "spec."
+
+// specOperatorSuffixes asocia el sufijo de la clave con su operador.
+// El orden importa: "_lte" debe evaluarse antes que "_lt".
+var specOperatorSuffixes = []struct {
+	suffix   string
+	operator models.SpecOperator
+}{
+	{"_contains", models.SpecOpContains},
+	{"_lte", models.SpecOpLessEqual},
+	{"_lt", models.SpecOpLess},
+	{"_gte", models.SpecOpGreaterEqual},
+	{"_gt", models.SpecOpGreater},
+}
+
+// parseItemQuery construye un models.ItemQuery a partir de los query params:
+//
+//	limit, offset            paginación
+//	sort=price,-rating,name  ordenamiento (prefijo "-" para descendente)
+//	min_price, max_price     filtros por precio
+//	min_rating               filtro por rating mínimo
+//	category_id              categoría (incluye sus subcategorías)
+//	spec.<clave>[_op]=valor  filtros por especificación (ver parseSpecFilters)
+//	currency                 moneda a la que se convierten los precios
+//
+// Los valores con formato incorrecto producen un error BAD_REQUEST; las reglas
+// de negocio (rangos, campos permitidos) se validan en la capa de servicio.
+func parseItemQuery(values url.Values) (models.ItemQuery, error) {
+	var query models.ItemQuery
+	var err error
+
+	if query.Limit, err = parseIntParam(values, "limit"); err != nil {
+		return query, err
+	}
+	if query.Offset, err = parseIntParam(values, "offset"); err != nil {
+		return query, err
+	}
+	if query.MinPrice, err = parseFloatParam(values, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = parseFloatParam(values, "max_price"); err != nil {
+		return query, err
+	}
+	if query.MinRating, err = parseFloatParam(values, "min_rating"); err != nil {
+		return query, err
+	}
+	if query.CategoryID, err = parseInt64Param(values, "category_id"); err != nil {
+		return query, err
+	}
+
+	query.SpecFilters = parseSpecFilters(values)
+	query.Currency = values.Get("currency")
+
+	if raw := values.Get("sort"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			field := models.SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+			query.Sort = append(query.Sort, field)
+		}
+	}
+
+	return query, nil
+}
+
+// parseSpecFilters extrae los filtros por especificación de los query params
+// con la forma spec.<clave>[_op]=valor, donde _op es uno de _contains, _lt,
+// _lte, _gt o _gte; sin sufijo se compara por igualdad. Por ejemplo:
+//
+//	spec.memory=32GB
+//	spec.processor_contains=M2
+//	spec.weight_lt=2kg
+//
+// Los filtros se devuelven ordenados por nombre de parámetro para que el
+// resultado sea determinista. La validez de las claves se comprueba en el servicio.
+func parseSpecFilters(values url.Values) []models.SpecFilter {
+	names := make([]string, 0)
+	for name := range values {
+		if strings.HasPrefix(name, specParamPrefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var filters []models.SpecFilter
+	for _, name := range names {
+		key := strings.TrimPrefix(name, specParamPrefix)
+		operator := models.SpecOpEquals
+
+		for _, candidate := range specOperatorSuffixes {
+			if strings.HasSuffix(key, candidate.suffix) {
+				key = strings.TrimSuffix(key, candidate.suffix)
+				operator = candidate.operator
+				break
+			}
+		}
+
+		for _, value := range values[name] {
+			filters = append(filters, models.SpecFilter{Key: key, Operator: operator, Value: value})
+		}
+	}
+
+	return filters
+}
+
+// parseIntParam lee un parámetro entero opcional. Devuelve 0 si no está presente.
+func parseIntParam(values url.Values, name string) (int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errors.NewBadRequestError("parámetro "+name+" inválido", err)
+	}
+	return value, nil
+}
+
+// parseInt64Param lee un parámetro entero opcional de 64 bits. Devuelve nil si no está presente.
+func parseInt64Param(values url.Values, name string) (*int64, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, errors.NewBadRequestError("parámetro "+name+" inválido", err)
+	}
+	return &value, nil
+}
+
+// parseFloatParam lee un parámetro decimal opcional. Devuelve nil si no está presente.
+func parseFloatParam(values url.Values, name string) (*float64, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, errors.NewBadRequestError("parámetro "+name+" inválido", err)
+	}
+	return &value, nil
+}
+
+// setPaginationLinks completa los enlaces next/prev de la página a partir de la
+// URL de la petición, conservando los filtros y el ordenamiento originales.
+func setPaginationLinks(r *http.Request, page *models.ItemPage) {
+	p := &page.Pagination
+
+	if p.Offset+p.Limit < p.Total {
+		next := pageLink(r, p.Offset+p.Limit, p.Limit)
+		p.Next = &next
+	}
+
+	if p.Offset > 0 {
+		prevOffset := p.Offset - p.Limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		prev := pageLink(r, prevOffset, p.Limit)
+		p.Prev = &prev
+	}
+}
+
+// pageLink devuelve la ruta relativa de la petición con offset y limit reemplazados.
+func pageLink(r *http.Request, offset, limit int) string {
+	values := r.URL.Query()
+	values.Set("offset", strconv.Itoa(offset))
+	values.Set("limit", strconv.Itoa(limit))
+
+	link := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+	return link.String()
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"project/internal/errors"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ItemRevisions maneja GET /api/v1/items/{id}/revisions
+// Devuelve el historial de cambios del item, con el autor y los valores anteriores
+// y nuevos de cada revisión.
+func (h *ItemHandler) ItemRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	history, err := h.service.ItemRevisions(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// DiffItemRevisions maneja GET /api/v1/items/{id}/revisions/diff?from=&to=
+// Devuelve los campos que cambian entre dos revisiones. Sin to, compara con la última.
+func (h *ItemHandler) DiffItemRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	values := r.URL.Query()
+	from, err := parseInt64Param(values, "from")
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if from == nil {
+		handleError(w, errors.NewBadRequestError("el parámetro from es obligatorio", nil))
+		return
+	}
+	to, err := parseInt64Param(values, "to")
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var toRevision int64
+	if to != nil {
+		toRevision = *to
+	}
+
+	diff, err := h.service.DiffItemRevisions(r.Context(), id, *from, toRevision)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// RestoreItemRevision maneja POST /api/v1/items/{id}/revisions/{rev}/restore
+// Vuelve el item a los valores que tenía después de la revisión indicada y lo devuelve.
+func (h *ItemHandler) RestoreItemRevision(w http.ResponseWriter, r *http.Request) {
+	encoder, err := negotiate(w, r, h.encoders)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	id, err := h.parseItemID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	revision, err := strconv.ParseInt(chi.URLParam(r, "rev"), 10, 64)
+	if err != nil {
+		handleError(w, errors.NewBadRequestError("formato de revisión inválido", err))
+		return
+	}
+
+	restored, err := h.service.RestoreItemRevision(r.Context(), id, revision)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEncoded(w, encoder, http.StatusOK, restored)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"project/internal/errors"
+	"project/internal/render"
+	"strings"
+)
+
+// decodeJSON decodifica el body de la petición limitando su tamaño a 1MB.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	const maxBodySize = 1024 * 1024
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return errors.NewBadRequestError(
+			"cuerpo de la petición (body) inválido",
+			err,
+		)
+	}
+	return nil
+}
+
+// handleError procesa errores de dominio y escribe la respuesta HTTP apropiada.
+func handleError(w http.ResponseWriter, err error) {
+	domainErr, ok := err.(*errors.DomainError)
+	if !ok {
+		domainErr = errors.NewInternalServerError(
+			"un error inesperado ha ocurrido",
+			err,
+		)
+	}
+
+	statusCode := domainErr.HTTPStatus()
+	errorResponse := domainErr.ToErrorResponse()
+
+	writeJSON(w, statusCode, errorResponse)
+}
+
+// writeJSON escribe una respuesta JSON con las cabeceras y código de estado correctos.
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// negotiate elige el encoder de la respuesta según la cabecera Accept. Se llama
+// antes de procesar la petición, para no aplicar una escritura cuyo resultado no
+// se podrá devolver. Si ningún formato es aceptable devuelve un error 406; los
+// errores se responden siempre en JSON.
+func negotiate(w http.ResponseWriter, r *http.Request, encoders *render.Registry) (render.Encoder, error) {
+	w.Header().Add("Vary", "Accept")
+
+	encoder, ok := encoders.Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		return nil, errors.NewNotAcceptableError(
+			"ninguno de los formatos aceptados está disponible; use " + strings.Join(encoders.MediaTypes(), ", "),
+		)
+	}
+	return encoder, nil
+}
+
+// writeEncoded escribe la respuesta con el encoder elegido por negotiate. La
+// respuesta se codifica completa antes de enviarse para poder responder con un
+// error si la codificación falla.
+func writeEncoded(w http.ResponseWriter, encoder render.Encoder, statusCode int, data interface{}) {
+	var body bytes.Buffer
+	if err := encoder.Encode(&body, data); err != nil {
+		w.Header().Del("ETag")
+		w.Header().Del("Last-Modified")
+		handleError(w, errors.NewInternalServerError("error al codificar la respuesta", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.WriteHeader(statusCode)
+	w.Write(body.Bytes())
+}
@@ -0,0 +1,179 @@
+package jwtauth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultKeysTTL es el tiempo durante el que RemoteKeySet reutiliza el JWKS
+	// obtenido si no se indica otro.
+	DefaultKeysTTL = time.Hour
+
+	// keysRefreshInterval es el tiempo mínimo entre dos consultas del JWKS fuera del
+	// TTL: al reintentar una actualización fallida y al buscar un kid desconocido.
+	keysRefreshInterval = time.Minute
+
+	// maxJWKSResponseSize limita el tamaño de la respuesta del proveedor de identidad.
+	maxJWKSResponseSize = 1 << 20
+)
+
+// RemoteKeySet obtiene el JWKS de una URL del proveedor de identidad y lo guarda en
+// caché durante el TTL; al vencer, lo actualiza en segundo plano. Un token firmado
+// con un kid que no está en caché puede usar una clave recién rotada, así que el
+// JWKS se vuelve a pedir antes de rechazarlo, como mucho una vez por minuto. Si una
+// actualización falla, se siguen usando las últimas claves obtenidas; si todavía no
+// hay ninguna, se devuelve el error y, hasta que pase un minuto, las peticiones
+// fallan con ese mismo error sin volver a consultar al proveedor.
+type RemoteKeySet struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	// now devuelve el momento actual; los tests lo reemplazan.
+	now func() time.Time
+
+	// refreshes agrupa las consultas concurrentes del JWKS en una sola, que se hace
+	// sin mantener mu para no bloquear las claves en caché.
+	refreshes singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]PublicKey
+	refreshAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+}
+
+// NewRemoteKeySet crea un KeySet que consulta url. Un ttl no positivo usa
+// DefaultKeysTTL.
+func NewRemoteKeySet(url string, ttl time.Duration) *RemoteKeySet {
+	if ttl <= 0 {
+		ttl = DefaultKeysTTL
+	}
+	return &RemoteKeySet{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// Key implementa KeySet. Con el TTL vencido, si hay claves en caché se usan mientras
+// el JWKS se actualiza en segundo plano; solo se espera a la actualización si todavía
+// no hay claves o si el kid es desconocido. Las peticiones concurrentes esperan a la
+// misma actualización en lugar de repetirla.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (PublicKey, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	hasKeys, refreshAt, lastErr := s.keys != nil, s.refreshAt, s.lastErr
+	s.mu.Unlock()
+
+	switch {
+	case now.Before(refreshAt) && !hasKeys:
+		return PublicKey{}, lastErr
+	case now.Before(refreshAt):
+	case hasKeys:
+		s.refresh(ctx, now)
+	default:
+		if err := s.wait(ctx, now); err != nil {
+			return PublicKey{}, err
+		}
+	}
+
+	key, ok, attemptedAt := s.lookup(kid)
+	if ok {
+		return key, nil
+	}
+	if now.Sub(attemptedAt) >= keysRefreshInterval {
+		if err := s.wait(ctx, now); err != nil {
+			return PublicKey{}, err
+		}
+		if key, ok, _ := s.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return PublicKey{}, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+// lookup busca kid en las claves en caché y devuelve también el momento de la
+// última consulta del JWKS.
+func (s *RemoteKeySet) lookup(kid string) (PublicKey, bool, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[kid]
+	return key, ok, s.attemptedAt
+}
+
+// wait espera a la actualización del JWKS, o a que se cancele ctx.
+func (s *RemoteKeySet) wait(ctx context.Context, now time.Time) error {
+	select {
+	case result := <-s.refresh(ctx, now):
+		return result.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refresh consulta el JWKS una sola vez para todas las peticiones concurrentes y
+// devuelve el canal por el que llega el resultado, sin esperarlo. La consulta no se
+// cancela con el contexto de la petición que la inicia, porque otras pueden estar
+// esperándola; la limita el timeout del cliente HTTP. Si falla y hay claves
+// anteriores, las conserva; si no las hay, devuelve el error. En ambos casos el
+// reintento se programa para dentro de keysRefreshInterval.
+func (s *RemoteKeySet) refresh(ctx context.Context, now time.Time) <-chan singleflight.Result {
+	return s.refreshes.DoChan(s.url, func() (interface{}, error) {
+		s.mu.Lock()
+		s.attemptedAt = now
+		s.mu.Unlock()
+
+		keys, err := s.fetch(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			s.lastErr = err
+			s.refreshAt = now.Add(keysRefreshInterval)
+			if s.keys == nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+
+		s.keys, s.lastErr = keys, nil
+		s.refreshAt = now.Add(s.ttl)
+		return nil, nil
+	})
+}
+
+// fetch consulta el JWKS del proveedor de identidad.
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return parseJWKS(data)
+}
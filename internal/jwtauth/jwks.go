@@ -0,0 +1,149 @@
+// Package jwtauth verifica los JWT (RS256 y ES256) que emite un proveedor de
+// identidad con las claves públicas de su JWKS y traduce sus roles a scopes de la API.
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrUnknownKey indica que el JWKS no tiene la clave con la que se firmó un token.
+var ErrUnknownKey = stdErrors.New("jwtauth: unknown key")
+
+// Algoritmos de firma admitidos.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+// PublicKey es una clave de verificación del JWKS junto con el único algoritmo de
+// firma que se acepta con ella.
+type PublicKey struct {
+	Key       crypto.PublicKey
+	Algorithm string
+}
+
+// KeySet devuelve las claves de verificación de un JWKS por su identificador (kid).
+type KeySet interface {
+	// Key devuelve la clave kid. Si el JWKS no la tiene, devuelve un error que
+	// envuelve ErrUnknownKey.
+	Key(ctx context.Context, kid string) (PublicKey, error)
+}
+
+// jsonWebKey es una clave de un JWKS (RFC 7517). Solo se leen los campos de las
+// claves RSA y EC.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS lee un JWKS y devuelve sus claves de firma por kid. Se ignoran las
+// claves sin kid, las de cifrado y las de tipos o algoritmos no admitidos, que un
+// JWKS puede publicar para otros clientes; el JWKS debe tener al menos una válida.
+func parseJWKS(data []byte) (map[string]PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		var key PublicKey
+		var err error
+		switch {
+		case jwk.Kty == "RSA" && (jwk.Alg == "" || jwk.Alg == AlgorithmRS256):
+			key, err = parseRSAKey(jwk)
+		case jwk.Kty == "EC" && jwk.Crv == "P-256" && (jwk.Alg == "" || jwk.Alg == AlgorithmES256):
+			key, err = parseECKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid JWKS: no RS256 or ES256 signing keys")
+	}
+	return keys, nil
+}
+
+// parseRSAKey construye una clave RSA a partir del módulo y el exponente.
+func parseRSAKey(jwk jsonWebKey) (PublicKey, error) {
+	n, err := decodeBigInt(jwk.N)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("n: %w", err)
+	}
+	e, err := decodeBigInt(jwk.E)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("e: %w", err)
+	}
+	if n.BitLen() < 2048 {
+		return PublicKey{}, fmt.Errorf("RSA keys must have at least 2048 bits")
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return PublicKey{}, fmt.Errorf("invalid RSA exponent")
+	}
+	return PublicKey{Key: &rsa.PublicKey{N: n, E: int(e.Int64())}, Algorithm: AlgorithmRS256}, nil
+}
+
+// parseECKey construye una clave P-256 y comprueba que el punto esté en la curva.
+func parseECKey(jwk jsonWebKey) (PublicKey, error) {
+	x, err := decodeBigInt(jwk.X)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(jwk.Y)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("y: %w", err)
+	}
+
+	// La forma no comprimida del punto (0x04 || x || y) se valida al decodificarla.
+	point := make([]byte, 65)
+	point[0] = 4
+	if len(x.Bytes()) > 32 || len(y.Bytes()) > 32 {
+		return PublicKey{}, fmt.Errorf("point is not on the P-256 curve")
+	}
+	x.FillBytes(point[1:33])
+	y.FillBytes(point[33:])
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return PublicKey{}, fmt.Errorf("point is not on the P-256 curve")
+	}
+	return PublicKey{Key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, Algorithm: AlgorithmES256}, nil
+}
+
+// decodeBigInt decodifica un entero sin signo en base64url sin relleno.
+func decodeBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url: %w", err)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
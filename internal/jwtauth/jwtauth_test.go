@@ -0,0 +1,461 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	stdErrors "errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project/internal/errors"
+	"project/internal/middleware"
+	"project/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://id.example.com"
+	testAudience = "item-comparison-api"
+)
+
+// testNow es el momento de referencia de los tokens de los tests.
+var testNow = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// signingKey es una clave privada de prueba con su kid.
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+func newRSAKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return signingKey{kid: kid, method: jwt.SigningMethodRS256, key: key}
+}
+
+func newECKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return signingKey{kid: kid, method: jwt.SigningMethodES256, key: key}
+}
+
+// jwks devuelve el JWKS con las claves públicas de keys.
+func jwks(keys ...signingKey) []byte {
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+
+	set := map[string][]map[string]string{"keys": {}}
+	for _, k := range keys {
+		switch key := k.key.(type) {
+		case *rsa.PrivateKey:
+			set["keys"] = append(set["keys"], map[string]string{
+				"kty": "RSA", "kid": k.kid, "use": "sig", "alg": "RS256",
+				"n": encode(key.N), "e": encode(big.NewInt(int64(key.E))),
+			})
+		case *ecdsa.PrivateKey:
+			set["keys"] = append(set["keys"], map[string]string{
+				"kty": "EC", "kid": k.kid, "crv": "P-256",
+				"x": encode(key.X), "y": encode(key.Y),
+			})
+		}
+	}
+	data, _ := json.Marshal(set)
+	return data
+}
+
+// validClaims devuelve los claims de un token vigente emitido para la API.
+func validClaims(roles ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   []string{testAudience},
+		"sub":   "user-42",
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"iat":   testNow.Unix(),
+		"roles": roles,
+	}
+}
+
+// sign firma los claims con la clave y su kid.
+func sign(t *testing.T, key signingKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	signed, err := token.SignedString(key.key)
+	require.NoError(t, err)
+	return signed
+}
+
+// jwksServer es un proveedor de identidad en proceso que publica un JWKS
+// reemplazable y cuenta las consultas.
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	body     []byte
+	status   int
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, keys ...signingKey) *jwksServer {
+	t.Helper()
+	s := &jwksServer{body: jwks(keys...), status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.WriteHeader(s.status)
+		w.Write(s.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// publish reemplaza el JWKS publicado.
+func (s *jwksServer) publish(status int, keys ...signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, jwks(keys...)
+}
+
+// newTestVerifier crea un verificador con las claves de keys y el reloj de los tests.
+func newTestVerifier(t *testing.T, keys KeySet, config Config) *Verifier {
+	t.Helper()
+	config.Issuer, config.Audience = testIssuer, testAudience
+	verifier, err := NewVerifier(keys, config)
+	require.NoError(t, err)
+	verifier.now = func() time.Time { return testNow }
+	return verifier
+}
+
+// assertErrorCode comprueba que err sea un DomainError con el código indicado
+func assertErrorCode(t *testing.T, err error, code errors.ErrorCode) {
+	t.Helper()
+	var domainErr *errors.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, code, domainErr.Code)
+}
+
+// TestVerifier_AcceptsRS256AndES256: Los tokens firmados con las claves del JWKS se
+// aceptan y, sin traducción de roles, los roles con nombre de scope son sus scopes
+func TestVerifier_AcceptsRS256AndES256(t *testing.T) {
+	rsaKey, ecKey := newRSAKey(t, "rsa-1"), newECKey(t, "ec-1")
+	server := newJWKSServer(t, rsaKey, ecKey)
+	verifier := newTestVerifier(t, NewRemoteKeySet(server.URL, time.Hour), Config{})
+
+	for _, key := range []signingKey{rsaKey, ecKey} {
+		principal, err := verifier.Verify(context.Background(), sign(t, key, validClaims("items:read", "items:read", "viewer")))
+		require.NoError(t, err, key.kid)
+		assert.Equal(t, models.Principal{Name: "jwt:user-42", Scopes: []string{models.ScopeItemsRead}, Tier: models.TierStandard}, *principal)
+	}
+	assert.Equal(t, int32(1), server.requests.Load(), "el JWKS se reutiliza durante el TTL")
+}
+
+// TestVerifier_Tier: El tier se lee del claim configurado y, si no es un tier
+// asignable, el usuario tiene el tier estándar
+func TestVerifier_Tier(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	keys, err := NewStaticKeySet(jwks(key))
+	require.NoError(t, err)
+	verifier := newTestVerifier(t, keys, Config{TierClaim: "plan.tier"})
+
+	for _, tc := range []struct {
+		name string
+		plan interface{}
+		tier string
+	}{
+		{"premium", map[string]interface{}{"tier": "premium"}, models.TierPremium},
+		{"anonymous is not assignable", map[string]interface{}{"tier": "anonymous"}, models.TierStandard},
+		{"missing claim", nil, models.TierStandard},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := validClaims()
+			if tc.plan != nil {
+				claims["plan"] = tc.plan
+			}
+			principal, err := verifier.Verify(context.Background(), sign(t, key, claims))
+			require.NoError(t, err)
+			assert.Equal(t, tc.tier, principal.Tier)
+		})
+	}
+}
+
+// TestVerifier_RejectsInvalidTokens: La firma, el algoritmo, iss, aud, exp y sub se
+// validan y cualquier fallo es un error Unauthorized
+func TestVerifier_RejectsInvalidTokens(t *testing.T) {
+	rsaKey, ecKey := newRSAKey(t, "rsa-1"), newECKey(t, "ec-1")
+	keys, err := NewStaticKeySet(jwks(rsaKey, ecKey))
+	require.NoError(t, err)
+	verifier := newTestVerifier(t, keys, Config{})
+
+	with := func(name string, value interface{}) jwt.MapClaims {
+		claims := validClaims(models.ScopeAdmin)
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	otherKey := newRSAKey(t, "rsa-1")
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"wrong issuer":        sign(t, rsaKey, with("iss", "https://evil.example.com")),
+		"wrong audience":      sign(t, rsaKey, with("aud", "other-api")),
+		"expired":             sign(t, rsaKey, with("exp", testNow.Add(-time.Minute).Unix())),
+		"without exp":         sign(t, rsaKey, with("exp", nil)),
+		"without sub":         sign(t, rsaKey, with("sub", nil)),
+		"unknown kid":         sign(t, signingKey{kid: "rsa-2", method: rsaKey.method, key: rsaKey.key}, validClaims()),
+		"kid of another alg":  sign(t, signingKey{kid: "ec-1", method: rsaKey.method, key: rsaKey.key}, validClaims()),
+		"forged signature":    sign(t, otherKey, validClaims()),
+		"symmetric algorithm": hmacToken,
+		"malformed":           "not.a.token",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), token)
+			assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+		})
+	}
+
+	// La tolerancia de reloj acepta un token vencido hace menos de 30 segundos.
+	_, err = verifier.Verify(context.Background(), sign(t, ecKey, with("exp", testNow.Add(-10*time.Second).Unix())))
+	assert.NoError(t, err)
+}
+
+// TestVerifier_RoleMapping: Los roles se leen de claims anidados o separados por
+// espacios y se traducen a scopes con el archivo de roles
+func TestVerifier_RoleMapping(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	keys, err := NewStaticKeySet(jwks(key))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "roles.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"catalog-viewer": ["items:read", "items:compare"],
+		"catalog-editor": ["items:read", "items:write"]
+	}`), 0o644))
+	roles, err := LoadRoleMapping(path)
+	require.NoError(t, err)
+
+	verifier := newTestVerifier(t, keys, Config{RolesClaim: "realm_access.roles", Roles: roles})
+	claims := validClaims()
+	claims["realm_access"] = map[string]interface{}{"roles": []string{"catalog-viewer", "catalog-editor", "admin"}}
+
+	principal, err := verifier.Verify(context.Background(), sign(t, key, claims))
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.ScopeItemsRead, models.ScopeItemsCompare, models.ScopeItemsWrite}, principal.Scopes)
+
+	verifier = newTestVerifier(t, keys, Config{RolesClaim: "scope", Roles: roles})
+	claims = validClaims()
+	claims["scope"] = "openid catalog-viewer"
+	principal, err = verifier.Verify(context.Background(), sign(t, key, claims))
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.ScopeItemsRead, models.ScopeItemsCompare}, principal.Scopes)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"catalog-viewer": ["items:delete"]}`), 0o644))
+	_, err = LoadRoleMapping(path)
+	assert.ErrorContains(t, err, `unknown scope "items:delete"`)
+}
+
+// TestRemoteKeySet_KeyRotation: Un kid desconocido vuelve a pedir el JWKS, como mucho
+// una vez por minuto, y las claves retiradas dejan de aceptarse al actualizarlo
+func TestRemoteKeySet_KeyRotation(t *testing.T) {
+	oldKey, newKey := newRSAKey(t, "2025-02"), newECKey(t, "2025-03")
+	server := newJWKSServer(t, oldKey)
+
+	now := testNow
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	verifier := newTestVerifier(t, keys, Config{})
+	ctx := context.Background()
+
+	_, err := verifier.Verify(ctx, sign(t, oldKey, validClaims()))
+	require.NoError(t, err)
+
+	// El proveedor rota sus claves: el nuevo kid se encuentra al volver a pedir el JWKS.
+	server.publish(http.StatusOK, newKey)
+	now = now.Add(2 * time.Minute)
+	_, err = verifier.Verify(ctx, sign(t, newKey, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load())
+
+	// Los kid desconocidos no repiten la consulta dentro del mismo minuto.
+	_, err = verifier.Verify(ctx, sign(t, oldKey, validClaims()))
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+	_, err = verifier.Verify(ctx, sign(t, signingKey{kid: "random", method: newKey.method, key: newKey.key}, validClaims()))
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+// TestRemoteKeySet_Unavailable: Si el JWKS no está disponible se siguen usando las
+// últimas claves; sin ninguna, la verificación es un error interno que se repite sin
+// volver a consultar al proveedor hasta que pasa un minuto
+func TestRemoteKeySet_Unavailable(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	server := newJWKSServer(t, key)
+	server.publish(http.StatusServiceUnavailable)
+
+	now := testNow
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	verifier := newTestVerifier(t, keys, Config{})
+	token := sign(t, key, validClaims())
+
+	_, err := verifier.Verify(context.Background(), token)
+	assertErrorCode(t, err, errors.ErrorCodeInternalServer)
+
+	server.publish(http.StatusOK, key)
+	now = now.Add(30 * time.Second)
+	_, err = verifier.Verify(context.Background(), token)
+	assertErrorCode(t, err, errors.ErrorCodeInternalServer)
+	assert.ErrorContains(t, err, "unexpected status 503")
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	now = now.Add(30 * time.Second)
+	_, err = verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+
+	server.publish(http.StatusServiceUnavailable)
+	now = now.Add(2 * time.Hour)
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return server.requests.Load() == 3 }, time.Second, time.Millisecond)
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+// TestRemoteKeySet_CachedKeysDuringRefresh: Mientras se consulta el JWKS, las claves
+// en caché se siguen devolviendo sin esperar a la consulta
+func TestRemoteKeySet_CachedKeysDuringRefresh(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	server := newJWKSServer(t, key)
+
+	now := testNow
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := keys.Key(ctx, "ec-1")
+	require.NoError(t, err)
+
+	// El servidor no responde mientras el test mantiene su mutex.
+	server.mu.Lock()
+	now = now.Add(2 * time.Minute)
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(ctx, "ec-2")
+		refreshed <- err
+	}()
+	require.Eventually(t, func() bool { return server.requests.Load() == 2 }, time.Second, time.Millisecond)
+
+	cached := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(ctx, "ec-1")
+		cached <- err
+	}()
+	select {
+	case err := <-cached:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("the cached key waited for the JWKS refresh")
+	}
+
+	server.mu.Unlock()
+	assert.ErrorIs(t, <-refreshed, ErrUnknownKey)
+}
+
+// TestRemoteKeySet_ExpiredTTL: Al vencer el TTL, las claves en caché se siguen
+// devolviendo mientras el JWKS se actualiza en segundo plano, y las retiradas dejan
+// de aceptarse al terminar la actualización
+func TestRemoteKeySet_ExpiredTTL(t *testing.T) {
+	oldKey, newKey := newECKey(t, "ec-1"), newECKey(t, "ec-2")
+	server := newJWKSServer(t, oldKey)
+
+	now := testNow
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := keys.Key(ctx, "ec-1")
+	require.NoError(t, err)
+
+	// El servidor no responde mientras el test mantiene su mutex.
+	server.mu.Lock()
+	server.body = jwks(newKey)
+	now = now.Add(2 * time.Hour)
+	cached := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(ctx, "ec-1")
+		cached <- err
+	}()
+	select {
+	case err := <-cached:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("the cached key waited for the JWKS refresh")
+	}
+	require.Eventually(t, func() bool { return server.requests.Load() == 2 }, time.Second, time.Millisecond)
+	server.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		_, err := keys.Key(ctx, "ec-1")
+		return stdErrors.Is(err, ErrUnknownKey)
+	}, time.Second, time.Millisecond)
+	_, err = keys.Key(ctx, "ec-2")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+// TestAuthenticate_BearerTokens: El middleware responde 401 sin un token válido y
+// 403 si sus roles no conceden el scope de la ruta
+func TestAuthenticate_BearerTokens(t *testing.T) {
+	key := newRSAKey(t, "rsa-1")
+	server := newJWKSServer(t, key)
+	verifier := newTestVerifier(t, NewRemoteKeySet(server.URL, time.Hour), Config{})
+
+	handler := middleware.Authenticate(middleware.Authenticators{Tokens: verifier})(
+		middleware.RequireScope(models.ScopeItemsWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(models.ActorFromContext(r.Context()).Name))
+		})),
+	)
+	request := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Contains(t, rec.Body.String(), `"code":"UNAUTHORIZED"`)
+
+	rec = request("Bearer " + sign(t, key, validClaims(models.ScopeItemsRead)))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"FORBIDDEN"`)
+
+	rec = request("Bearer " + sign(t, key, validClaims(models.ScopeItemsWrite)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jwt:user-42", rec.Body.String())
+}
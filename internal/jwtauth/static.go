@@ -0,0 +1,45 @@
+package jwtauth
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// StaticKeySet devuelve las claves de un JWKS fijo, normalmente leído de un archivo
+// al iniciar el servidor. Para rotar las claves hay que reiniciar el servidor.
+type StaticKeySet struct {
+	keys map[string]PublicKey
+}
+
+// NewStaticKeySet crea un KeySet con las claves del JWKS data.
+func NewStaticKeySet(data []byte) (*StaticKeySet, error) {
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticKeySet{keys: keys}, nil
+}
+
+// LoadStaticKeySet lee un archivo JWKS.
+func LoadStaticKeySet(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	set, err := NewStaticKeySet(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
+
+// Key implementa KeySet.
+func (s *StaticKeySet) Key(_ context.Context, kid string) (PublicKey, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return PublicKey{}, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
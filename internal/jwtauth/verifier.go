@@ -0,0 +1,224 @@
+package jwtauth
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"project/internal/errors"
+	"project/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultRolesClaim es el claim con los roles del usuario si no se indica otro.
+	DefaultRolesClaim = "roles"
+
+	// DefaultTierClaim es el claim con el tier del usuario si no se indica otro.
+	DefaultTierClaim = "tier"
+
+	// clockLeeway tolera la diferencia de reloj con el proveedor de identidad al
+	// comprobar exp y nbf.
+	clockLeeway = 30 * time.Second
+)
+
+// RoleMapping traduce cada rol del proveedor de identidad a los scopes de la API que
+// concede. Es el formato del archivo de roles:
+//
+//	{"catalog-admin": ["admin"], "catalog-viewer": ["items:read", "items:compare"]}
+type RoleMapping map[string][]string
+
+// LoadRoleMapping lee un archivo JSON con el formato de RoleMapping y comprueba que
+// todos los scopes existan.
+func LoadRoleMapping(path string) (RoleMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read role mapping: %w", err)
+	}
+
+	var mapping RoleMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("%s: invalid role mapping: %w", path, err)
+	}
+	for role, scopes := range mapping {
+		for _, scope := range scopes {
+			if !models.IsValidScope(scope) {
+				return nil, fmt.Errorf("%s: role %q: unknown scope %q", path, role, scope)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// Config son los requisitos de los tokens que acepta un Verifier.
+type Config struct {
+	// Issuer es el valor exigido en el claim iss.
+	Issuer string
+
+	// Audience es el valor que debe incluir el claim aud.
+	Audience string
+
+	// RolesClaim es el claim con los roles del usuario: un array de strings o un
+	// string con los roles separados por espacios. Los claims anidados se indican con
+	// puntos, por ejemplo "realm_access.roles". Por defecto, DefaultRolesClaim.
+	RolesClaim string
+
+	// Roles traduce los roles a scopes. Sin ella, los roles con el nombre de un scope
+	// (como "items:read" o "admin") conceden ese scope y el resto se ignoran.
+	Roles RoleMapping
+
+	// TierClaim es el claim con el tier del usuario, que determina sus límites de
+	// tasa; admite puntos como RolesClaim. Si falta o no es un tier asignable, el
+	// usuario tiene models.TierStandard. Por defecto, DefaultTierClaim.
+	TierClaim string
+}
+
+// Verifier valida JWT firmados con RS256 o ES256 por las claves de un KeySet y
+// obtiene el cliente que identifican.
+type Verifier struct {
+	keys   KeySet
+	config Config
+
+	// now devuelve el momento actual; los tests lo reemplazan.
+	now func() time.Time
+}
+
+// NewVerifier crea un verificador con las claves de keys. El emisor y la audiencia
+// son obligatorios: sin ellos se aceptarían tokens emitidos para otras aplicaciones.
+func NewVerifier(keys KeySet, config Config) (*Verifier, error) {
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, fmt.Errorf("JWT issuer and audience are required")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = DefaultRolesClaim
+	}
+	if config.TierClaim == "" {
+		config.TierClaim = DefaultTierClaim
+	}
+	return &Verifier{keys: keys, config: config, now: time.Now}, nil
+}
+
+// Verify valida la firma, el algoritmo, iss, aud, exp y nbf del token y devuelve el
+// cliente que identifica su claim sub, con los scopes de sus roles y su tier. Un token inválido
+// es un error Unauthorized; no poder obtener las claves, un error interno.
+func (v *Verifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	var keyErr error
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("%w: token without kid", ErrUnknownKey)
+		}
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			keyErr = err
+			return nil, err
+		}
+		// Cada clave solo se acepta con su algoritmo, para que un token no pueda
+		// elegir cómo se interpreta la clave.
+		if t.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %q does not accept %s", kid, t.Method.Alg())
+		}
+		return key.Key, nil
+	},
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmES256}),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithAudience(v.config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockLeeway),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		if keyErr != nil && !stdErrors.Is(keyErr, ErrUnknownKey) {
+			return nil, errors.NewInternalServerError("no se pudieron obtener las claves de verificación de tokens", keyErr)
+		}
+		if stdErrors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.NewUnauthorizedError("el token expiró")
+		}
+		return nil, errors.NewUnauthorizedError("token inválido")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.NewUnauthorizedError("token inválido: falta el claim sub")
+	}
+
+	return &models.Principal{
+		Name:   "jwt:" + subject,
+		Scopes: v.scopes(claimRoles(claims, v.config.RolesClaim)),
+		Tier:   claimTier(claims, v.config.TierClaim),
+	}, nil
+}
+
+// scopes devuelve, sin repetir, los scopes que conceden los roles.
+func (v *Verifier) scopes(roles []string) []string {
+	scopes := []string{}
+	seen := make(map[string]bool)
+	add := func(scope string) {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	for _, role := range roles {
+		if v.config.Roles == nil {
+			if models.IsValidScope(role) {
+				add(role)
+			}
+			continue
+		}
+		for _, scope := range v.config.Roles[role] {
+			add(scope)
+		}
+	}
+	return scopes
+}
+
+// claimRoles lee los roles del claim indicado por path. Un claim ausente o de otro
+// tipo no concede ningún rol.
+func claimRoles(claims jwt.MapClaims, path string) []string {
+	switch roles := claimValue(claims, path).(type) {
+	case string:
+		return strings.Fields(roles)
+	case []interface{}:
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// claimTier lee el tier del claim indicado por path. Un claim ausente o que no es un
+// tier asignable es models.TierStandard.
+func claimTier(claims jwt.MapClaims, path string) string {
+	tier, _ := claimValue(claims, path).(string)
+	if !models.IsValidTier(tier) {
+		return models.TierStandard
+	}
+	return tier
+}
+
+// claimValue devuelve el valor del claim indicado por path, con los claims anidados
+// separados por puntos, o nil si no existe.
+func claimValue(claims jwt.MapClaims, path string) interface{} {
+	var value interface{} = map[string]interface{}(claims)
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+	"project/internal/models"
+)
+
+// Actor asocia a cada petición el autor de las escrituras que realice, que el
+// historial de revisiones de items registra. Por defecto el autor es anónimo y se
+// identifica por la IP del cliente que resuelve ClientIP; Authenticate lo reemplaza
+// por el cliente autenticado.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := models.WithActor(r.Context(), models.Actor{
+			Name:   "anonymous@" + clientIP(r),
+			Source: models.SourceAPI,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"net/http"
+	"strings"
+
+	"project/internal/errors"
+	"project/internal/models"
+)
+
+// APIKeyHeader es la cabecera con la que los clientes envían su API key.
+const APIKeyHeader = "X-API-Key"
+
+// KeyAuthenticator valida una API key y devuelve el cliente que identifica.
+// Lo implementa services.APIKeyService.
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.Principal, error)
+}
+
+// TokenVerifier valida un bearer token y devuelve el cliente que identifica.
+// Lo implementa jwtauth.Verifier.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*models.Principal, error)
+}
+
+// Authenticators son los mecanismos de autenticación que acepta la API. Si no hay
+// ninguno, la API es anónima.
+type Authenticators struct {
+	APIKeys KeyAuthenticator
+	Tokens  TokenVerifier
+}
+
+// Enabled indica si la API exige autenticación.
+func (a Authenticators) Enabled() bool {
+	return a.APIKeys != nil || a.Tokens != nil
+}
+
+// Authenticate exige en cada petición una credencial válida: una API key en la
+// cabecera X-API-Key o un bearer token en Authorization, según los mecanismos
+// configurados. El cliente autenticado se guarda en el contexto y pasa a ser el autor
+// de las escrituras en lugar del autor anónimo de Actor.
+func Authenticate(auth Authenticators) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var principal *models.Principal
+			var err error
+
+			key := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+			token, hasToken := bearerToken(r)
+			switch {
+			case key != "" && auth.APIKeys != nil:
+				principal, err = auth.APIKeys.Authenticate(r.Context(), key)
+			case hasToken && auth.Tokens != nil:
+				principal, err = auth.Tokens.Verify(r.Context(), token)
+			default:
+				err = errors.NewUnauthorizedError("se requiere autenticación: " + credentialsHint(auth))
+			}
+			if err != nil {
+				writeAuthError(w, auth, err)
+				return
+			}
+
+			ctx := models.WithPrincipal(r.Context(), *principal)
+			ctx = models.WithActor(ctx, models.Actor{Name: principal.Name, Source: models.SourceAPI})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rechaza con 403 las peticiones cuyo cliente no tiene el scope
+// indicado (o admin), y con 401 las que no están autenticadas.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := models.PrincipalFromContext(r.Context())
+			if !ok {
+				writeError(w, errors.NewUnauthorizedError("se requiere autenticación"))
+				return
+			}
+			if !principal.HasScope(scope) {
+				writeError(w, errors.NewForbiddenError("el cliente no tiene el scope "+scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken extrae el token de la cabecera "Authorization: Bearer <token>".
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// credentialsHint describe las credenciales que acepta la API.
+func credentialsHint(auth Authenticators) string {
+	var hints []string
+	if auth.APIKeys != nil {
+		hints = append(hints, "una API key en la cabecera "+APIKeyHeader)
+	}
+	if auth.Tokens != nil {
+		hints = append(hints, "un bearer token en la cabecera Authorization")
+	}
+	return strings.Join(hints, " o ")
+}
+
+// writeAuthError escribe un error de autenticación con el desafío WWW-Authenticate
+// de cada mecanismo aceptado.
+func writeAuthError(w http.ResponseWriter, auth Authenticators, err error) {
+	var domainErr *errors.DomainError
+	if stdErrors.As(err, &domainErr) && domainErr.Code == errors.ErrorCodeUnauthorized {
+		if auth.APIKeys != nil {
+			w.Header().Add("WWW-Authenticate", `APIKey header="`+APIKeyHeader+`"`)
+		}
+		if auth.Tokens != nil {
+			w.Header().Add("WWW-Authenticate", `Bearer realm="api"`)
+		}
+	}
+	writeError(w, err)
+}
+
+// writeError escribe la respuesta de error estandarizada de err. Los errores que no
+// son de dominio se responden como un error interno sin exponer su detalle.
+func writeError(w http.ResponseWriter, err error) {
+	var domainErr *errors.DomainError
+	if !stdErrors.As(err, &domainErr) {
+		domainErr = errors.NewInternalServerError("un error inesperado ha ocurrido", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(domainErr.HTTPStatus())
+	json.NewEncoder(w).Encode(domainErr.ToErrorResponse())
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Cabeceras con las que los proxies de confianza pueden indicar la IP del cliente.
+const (
+	ProxyHeaderXForwardedFor = "x-forwarded-for"
+	ProxyHeaderForwarded     = "forwarded"
+)
+
+// ClientIPResolver resuelve la IP del cliente de cada petición. Solo confía en la
+// cabecera configurada, X-Forwarded-For o Forwarded (RFC 7239), si la conexión llega
+// de un proxy de confianza; sin proxies configurados, la IP es la dirección remota de
+// la conexión.
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+	header  string
+}
+
+// NewClientIPResolver crea un resolvedor que confía en los proxies de las redes
+// indicadas en notación CIDR ("10.0.0.0/8") o como direcciones sueltas ("10.0.0.1")
+// y lee solo la cabecera header (ProxyHeaderXForwardedFor si está vacía). La otra
+// cabecera se ignora: los proxies suelen reenviar sin cambios la que no escriben, así
+// que su contenido lo controla el cliente.
+func NewClientIPResolver(trustedProxies []string, header string) (*ClientIPResolver, error) {
+	header = strings.ToLower(strings.TrimSpace(header))
+	switch header {
+	case "":
+		header = ProxyHeaderXForwardedFor
+	case ProxyHeaderXForwardedFor, ProxyHeaderForwarded:
+	default:
+		return nil, fmt.Errorf("invalid trusted proxy header %q: must be %s or %s", header, ProxyHeaderXForwardedFor, ProxyHeaderForwarded)
+	}
+
+	resolver := &ClientIPResolver{header: header}
+	for _, value := range trustedProxies {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: must be an IP address or CIDR", value)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		resolver.trusted = append(resolver.trusted, prefix.Masked())
+	}
+	return resolver, nil
+}
+
+// ClientIP es el middleware que resuelve la IP del cliente y la guarda en el
+// contexto, para que el registro de peticiones, el rate limiting y el autor de las
+// escrituras usen el mismo valor. Debe ir antes que todos ellos.
+func (c *ClientIPResolver) ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), clientIPKey{}, c.Resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Resolve devuelve la IP del cliente. Si la conexión llega de un proxy de confianza,
+// recorre la cadena de la cabecera configurada desde la derecha
+// (la entrada que añadió el último proxy) y devuelve la primera dirección que no es
+// de un proxy de confianza. Las entradas de la izquierda las escribe el cliente y
+// no se tienen en cuenta; una entrada inválida detiene el recorrido en el proxy que
+// la añadió.
+func (c *ClientIPResolver) Resolve(r *http.Request) string {
+	peer, ok := parseNode(r.RemoteAddr)
+	if !ok {
+		return remoteHost(r)
+	}
+	if !c.isTrusted(peer) {
+		return peer.String()
+	}
+
+	chain := c.forwardedChain(r.Header)
+	if len(chain) == 0 {
+		return peer.String()
+	}
+
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseNode(chain[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !c.isTrusted(addr) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// isTrusted indica si addr pertenece a una de las redes de confianza.
+func (c *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range c.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedChain devuelve las direcciones de la cabecera configurada (los parámetros
+// for de Forwarded) en el orden en que las añadieron los proxies. Varias cabeceras
+// del mismo nombre forman una sola lista.
+func (c *ClientIPResolver) forwardedChain(header http.Header) []string {
+	if c.header == ProxyHeaderForwarded {
+		values := header.Values("Forwarded")
+		if len(values) == 0 {
+			return nil
+		}
+		var chain []string
+		for _, element := range splitQuoted(strings.Join(values, ","), ',') {
+			chain = append(chain, forwardedFor(element))
+		}
+		return chain
+	}
+
+	values := header.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return nil
+	}
+	chain := strings.Split(strings.Join(values, ","), ",")
+	for i := range chain {
+		chain[i] = strings.TrimSpace(chain[i])
+	}
+	return chain
+}
+
+// forwardedFor devuelve el valor del parámetro for de un elemento de Forwarded
+// (por ejemplo `for="[2001:db8::1]:4711";proto=https`), sin comillas, o "" si no lo
+// tiene.
+func forwardedFor(element string) string {
+	for _, pair := range splitQuoted(element, ';') {
+		name, value, ok := strings.Cut(pair, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "for") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// splitQuoted separa s por sep fuera de las cadenas entre comillas dobles y quita
+// los espacios alrededor de cada parte.
+func splitQuoted(s string, sep byte) []string {
+	var parts []string
+	quoted, start := false, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// parseNode interpreta una dirección con o sin puerto: "192.0.2.1", "192.0.2.1:80",
+// "2001:db8::1" o "[2001:db8::1]:80". Los identificadores "unknown" y ofuscados de
+// Forwarded no son direcciones válidas.
+func parseNode(node string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(node); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(node, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// remoteHost devuelve el host de la dirección remota de la conexión.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientIPKey es la clave de la IP del cliente en el contexto.
+type clientIPKey struct{}
+
+// ClientIPFromContext devuelve la IP del cliente que ClientIP guardó en ctx.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	return ip, ok
+}
+
+// clientIP devuelve la IP del cliente resuelta por ClientIP o, si la petición no
+// pasó por ese middleware, la dirección remota de la conexión.
+func clientIP(r *http.Request) string {
+	if ip, ok := ClientIPFromContext(r.Context()); ok {
+		return ip
+	}
+	return remoteHost(r)
+}
+
+// Logger registra cada petición con el formato del Logger de Chi, pero con la IP
+// del cliente en lugar de la dirección remota de la conexión.
+func Logger(next http.Handler) http.Handler {
+	formatter := &chiMiddleware.DefaultLogFormatter{Logger: log.New(os.Stderr, "", log.LstdFlags), NoColor: true}
+	return chiMiddleware.RequestLogger(clientIPLogFormatter{formatter})(next)
+}
+
+// clientIPLogFormatter pasa al formateador de Chi una copia de la petición con la IP
+// del cliente como dirección remota.
+type clientIPLogFormatter struct {
+	chiMiddleware.LogFormatter
+}
+
+// NewLogEntry implementa chiMiddleware.LogFormatter.
+func (f clientIPLogFormatter) NewLogEntry(r *http.Request) chiMiddleware.LogEntry {
+	logged := *r
+	logged.RemoteAddr = clientIP(r)
+	return f.LogFormatter.NewLogEntry(&logged)
+}
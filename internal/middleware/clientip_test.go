@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientIPResolver_Resolve: La cabecera de reenvío configurada solo se acepta de
+// proxies de confianza y se recorre desde la derecha; la otra se ignora
+func TestClientIPResolver_Resolve(t *testing.T) {
+	trusted := []string{"10.0.0.0/8", "2001:db8:ffff::/48", "192.0.2.10"}
+
+	tests := []struct {
+		name       string
+		header     string
+		remoteAddr string
+		headers    map[string][]string
+		expected   string
+	}{
+		{
+			name:       "direct client spoofing X-Forwarded-For",
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4"}},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.2:5000",
+			expected:   "10.0.0.2",
+		},
+		{
+			name:       "spoofed entry left of the client",
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.9"}},
+			expected:   "198.51.100.9",
+		},
+		{
+			name:       "trusted proxy chain across headers",
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.9", "192.0.2.10, 10.1.1.1"}},
+			expected:   "198.51.100.9",
+		},
+		{
+			name:       "invalid entry stops at the proxy that added it",
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, garbage, 10.1.1.1"}},
+			expected:   "10.1.1.1",
+		},
+		{
+			name:       "all entries are trusted proxies",
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.3.3.3, 10.1.1.1"}},
+			expected:   "10.3.3.3",
+		},
+		{
+			name:       "client Forwarded ignored when reading X-Forwarded-For",
+			remoteAddr: "10.0.0.5:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"203.0.113.7"},
+				"Forwarded":       {"for=198.51.100.99"},
+			},
+			expected: "203.0.113.7",
+		},
+		{
+			name:       "client X-Forwarded-For ignored when reading Forwarded",
+			header:     ProxyHeaderForwarded,
+			remoteAddr: "10.0.0.5:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"198.51.100.99"},
+				"Forwarded":       {"for=203.0.113.7"},
+			},
+			expected: "203.0.113.7",
+		},
+		{
+			name:       "Forwarded with IPv6, ports and quotes",
+			header:     ProxyHeaderForwarded,
+			remoteAddr: "[2001:db8:ffff::1]:443",
+			headers: map[string][]string{
+				"Forwarded":       {`for=1.2.3.4, for="[2001:db8:cafe::17]:4711";proto=https;by="a,b"`, `For="192.0.2.10:80"`},
+				"X-Forwarded-For": {"5.6.7.8"},
+			},
+			expected: "2001:db8:cafe::17",
+		},
+		{
+			name:       "Forwarded with an unknown node",
+			header:     ProxyHeaderForwarded,
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string][]string{"Forwarded": {"for=198.51.100.9, for=unknown"}},
+			expected:   "10.0.0.2",
+		},
+		{
+			name:       "IPv4-mapped IPv6",
+			remoteAddr: "[::ffff:10.0.0.2]:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"::ffff:198.51.100.9"}},
+			expected:   "198.51.100.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewClientIPResolver(trusted, tt.header)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.headers {
+				for _, value := range values {
+					req.Header.Add(name, value)
+				}
+			}
+			assert.Equal(t, tt.expected, resolver.Resolve(req))
+		})
+	}
+}
+
+// TestClientIPResolver_NoTrustedProxies: Sin proxies de confianza las cabeceras se
+// ignoran y las redes y cabeceras inválidas son un error
+func TestClientIPResolver_NoTrustedProxies(t *testing.T) {
+	resolver, err := NewClientIPResolver(nil, "")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("Forwarded", "for=1.2.3.4")
+
+	var resolved string
+	resolver.ClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resolved, _ = ClientIPFromContext(r.Context())
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "10.0.0.2", resolved)
+
+	_, err = NewClientIPResolver([]string{"10.0.0.0/33"}, "")
+	assert.ErrorContains(t, err, `invalid trusted proxy "10.0.0.0/33"`)
+
+	_, err = NewClientIPResolver([]string{"10.0.0.0/8"}, "x-real-ip")
+	assert.ErrorContains(t, err, `invalid trusted proxy header "x-real-ip"`)
+}
@@ -2,191 +2,177 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net"
+	"log"
+	"math"
 	"net/http"
-	"strings"
-	"sync"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/ratelimit"
+)
+
+const (
+	// storeTimeout es el tiempo máximo que una petición espera al store de límites.
+	storeTimeout = 200 * time.Millisecond
 
-	"golang.org/x/time/rate"
+	// storeErrorLogInterval es cada cuánto se registra, como mucho, que el store de
+	// límites falla, para no llenar el log con un error por petición.
+	storeErrorLogInterval = time.Minute
 )
 
-// RateLimiter gestiona el límite de tasa por IP de cliente
+// RateLimiter aplica las políticas de límite de tasa: la global por IP de cliente y
+// las de cada grupo de rutas por cliente autenticado y tier. La IP es la que resuelve
+// el middleware ClientIP, que solo confía en X-Forwarded-For y Forwarded si la
+// petición llega de un proxy de confianza.
+//
+// El estado de los límites se guarda en un ratelimit.Store: en memoria por defecto o,
+// con varias réplicas, en uno compartido como Redis.
+//
+// Las respuestas llevan las cabeceras RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset y RateLimit-Policy de la política más cercana a agotarse.
 type RateLimiter struct {
-	// clients almacena los limitadores de tasa por IP de cliente
-	clients            map[string]*clientLimiter
-	mu                 sync.RWMutex
-	rateLimit          int
-	timeWindow         time.Duration
-	cleanupInterval    time.Duration
-	limiterEvictionAge time.Duration
-	stopCleanup        context.CancelFunc
-}
+	policies   RateLimitPolicies
+	store      ratelimit.Store
+	failClosed bool
 
-// clientLimiter envuelve un rate.Limiter junto con la hora del último acceso
-type clientLimiter struct {
-	limiter    *rate.Limiter
-	lastAccess time.Time
+	// lastStoreError es la hora, en nanosegundos Unix, del último error del store
+	// registrado.
+	lastStoreError atomic.Int64
 }
 
-// NewRateLimiter crea una nueva instancia de RateLimiter.
-// rateLimit: número de peticiones permitidas
-// timeWindow: ventana de tiempo para el límite de tasa (ej. 1*time.Minute)
-func NewRateLimiter(rateLimit int, timeWindow time.Duration) *RateLimiter {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	rl := &RateLimiter{
-		clients:            make(map[string]*clientLimiter),
-		rateLimit:          rateLimit,
-		timeWindow:         timeWindow,
-		cleanupInterval:    5 * time.Minute,
-		limiterEvictionAge: 10 * time.Minute,
-		stopCleanup:        cancel,
+// RateLimiterOption configura un RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimitStore guarda el estado de los límites en store en lugar de en memoria.
+func WithRateLimitStore(store ratelimit.Store) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.store = store
 	}
+}
 
-	go rl.cleanup(ctx)
+// WithFailClosed indica qué hacer con las peticiones si el store no responde: con
+// closed, se rechazan con 503; si no, se permiten sin límite (por defecto), para que
+// una caída del store no deje la API sin servicio.
+func WithFailClosed(closed bool) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.failClosed = closed
+	}
+}
 
+// NewRateLimiter crea una nueva instancia de RateLimiter con las políticas indicadas.
+func NewRateLimiter(policies RateLimitPolicies, opts ...RateLimiterOption) *RateLimiter {
+	rl := &RateLimiter{policies: policies}
+	for _, opt := range opts {
+		opt(rl)
+	}
+	if rl.store == nil {
+		rl.store = ratelimit.NewMemoryStore()
+	}
 	return rl
 }
 
-// RateLimit es el middleware HTTP que aplica el límite de tasa
+// RateLimit es el middleware HTTP que aplica la política global por IP de cliente.
+// Protege la API de abusos antes de que Authenticate consulte las credenciales.
 func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		clientIP := rl.getClientIP(r)
-
-		limiter := rl.getLimiter(clientIP)
-
-		if !limiter.Allow() {
-			rl.writeRateLimitError(w)
-			return
+		if rl.apply(w, r, "global|"+clientIP(r), rl.policies.Global) {
+			next.ServeHTTP(w, r)
 		}
-
-		next.ServeHTTP(w, r)
 	})
 }
 
-// getClientIP extrae la IP del cliente desde la petición.
-// Comprueba primero el header X-Forwarded-For (para reverse proxies), luego X-Real-IP, y finalmente RemoteAddr.
-func (rl *RateLimiter) getClientIP(r *http.Request) string {
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		ips := strings.Split(forwarded, ",")
-		if len(ips) > 0 {
-			ip := strings.TrimSpace(ips[0])
-			if rl.isValidIP(ip) {
-				return ip
+// Limit devuelve el middleware que aplica la política del grupo de rutas indicado
+// según el tier del cliente autenticado (models.TierAnonymous sin autenticación).
+// Debe ir después de Authenticate. Un tier sin política para el grupo no se limita.
+func (rl *RateLimiter) Limit(group string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tier, client := models.TierAnonymous, "ip:"+clientIP(r)
+			if principal, ok := models.PrincipalFromContext(r.Context()); ok {
+				tier, client = principal.Tier, principal.Name
+				if tier == "" {
+					tier = models.TierStandard
+				}
 			}
-		}
-	}
 
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" && rl.isValidIP(realIP) {
-		return realIP
-	}
-
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
+			policy, ok := rl.policies.Tiers[tier][group]
+			if !ok || rl.apply(w, r, group+"|"+tier+"|"+client, policy) {
+				next.ServeHTTP(w, r)
+			}
+		})
 	}
-
-	return ip
-}
-
-// isValidIP comprueba si la dirección IP es válida
-func (rl *RateLimiter) isValidIP(ip string) bool {
-	parsed := net.ParseIP(strings.TrimSpace(ip))
-	return parsed != nil
 }
 
-// getLimiter obtiene o crea un limitador de tasa para la IP de cliente dada.
-// Esta función adquiere un bloqueo de escritura (write lock) porque puede crear una
-// nueva entrada y siempre actualiza la hora de último acceso.
-func (rl *RateLimiter) getLimiter(clientIP string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// apply consume una petición del límite de key, escribe las cabeceras RateLimit-*
+// y, si se excede el límite, la respuesta 429. Devuelve si la petición puede seguir.
+func (rl *RateLimiter) apply(w http.ResponseWriter, r *http.Request, key string, policy ratelimit.Policy) bool {
+	ctx, cancel := context.WithTimeout(r.Context(), storeTimeout)
+	defer cancel()
 
-	now := time.Now()
-
-	if cl, exists := rl.clients[clientIP]; exists {
-		cl.lastAccess = now
-		return cl.limiter
+	result, err := rl.store.Allow(ctx, key, policy)
+	if err != nil {
+		rl.logStoreError(err)
+		if !rl.failClosed {
+			return true
+		}
+		writeError(w, errors.NewServiceUnavailableError("el límite de tasa no está disponible", err))
+		return false
 	}
 
-	ratePerSecond := float64(rl.rateLimit) / rl.timeWindow.Seconds()
-	burst := rl.rateLimit
-
-	limiter := rate.NewLimiter(rate.Limit(ratePerSecond), burst)
-
-	rl.clients[clientIP] = &clientLimiter{
-		limiter:    limiter,
-		lastAccess: now,
+	setRateLimitHeaders(w, policy, result)
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+		writeError(w, errors.NewDomainError(
+			errors.ErrorCodeTooManyRequests,
+			"Límite de tasa excedido. Por favor, inténtelo de nuevo más tarde",
+			nil,
+		))
+		return false
 	}
-
-	return limiter
+	return true
 }
 
-// cleanup elimina periódicamente los limitadores antiguos que no se han usado recientemente.
-// Esto previene fugas de memoria por acumular limitadores de clientes inactivos.
-func (rl *RateLimiter) cleanup(ctx context.Context) {
-	ticker := time.NewTicker(rl.cleanupInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			rl.performCleanup()
-		}
+// logStoreError registra un error del store, como mucho una vez por storeErrorLogInterval.
+func (rl *RateLimiter) logStoreError(err error) {
+	now := time.Now().UnixNano()
+	last := rl.lastStoreError.Load()
+	if now-last < int64(storeErrorLogInterval) || !rl.lastStoreError.CompareAndSwap(last, now) {
+		return
 	}
-}
-
-// performCleanup elimina los limitadores que no han sido accedidos recientemente
-func (rl *RateLimiter) performCleanup() {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
 
-	cutoff := time.Now().Add(-rl.limiterEvictionAge)
-
-	for ip, cl := range rl.clients {
-		if cl.lastAccess.Before(cutoff) {
-			delete(rl.clients, ip)
-		}
+	action := "se permiten las peticiones sin límite"
+	if rl.failClosed {
+		action = "se rechazan las peticiones"
 	}
+	log.Printf("Error del store de límites de tasa (%s): %v", action, err)
 }
 
-// writeRateLimitError escribe una respuesta de error de límite de tasa estandarizada
-func (rl *RateLimiter) writeRateLimitError(w http.ResponseWriter) {
-	domainErr := errors.NewDomainError(
-		errors.ErrorCodeTooManyRequests,
-		"Límite de tasa excedido. Por favor, inténtelo de nuevo más tarde",
-		nil,
-	)
-
-	statusCode := domainErr.HTTPStatus()
-	errorResponse := domainErr.ToErrorResponse()
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Retry-After", rl.calculateRetryAfter())
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(errorResponse)
+// setRateLimitHeaders escribe las cabeceras RateLimit-* de la política salvo que la
+// respuesta ya tenga las de otra con menos peticiones disponibles.
+func setRateLimitHeaders(w http.ResponseWriter, policy ratelimit.Policy, result ratelimit.Result) {
+	header := w.Header()
+	if current, err := strconv.Atoi(header.Get("RateLimit-Remaining")); err == nil && current < result.Remaining {
+		return
+	}
+
+	window := ceilSeconds(policy.Window)
+	header.Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	header.Set("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+strconv.Itoa(window))
 }
 
-// calculateRetryAfter calcula el valor del header Retry-After.
-// Devuelve la duración de la ventana de tiempo en segundos como string.
-func (rl *RateLimiter) calculateRetryAfter() string {
-	seconds := int(rl.timeWindow.Seconds())
-	if seconds < 1 {
-		seconds = 1
-	}
-	return fmt.Sprintf("%d", seconds)
+// ceilSeconds redondea d hacia arriba a segundos enteros.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
 
-// Stop detiene la gorutina de limpieza y debe llamarse durante el apagado (shutdown)
+// Stop cierra el store de límites y debe llamarse durante el apagado (shutdown)
 func (rl *RateLimiter) Stop() {
-	rl.stopCleanup()
+	if err := rl.store.Close(); err != nil {
+		log.Printf("Error al cerrar el store de límites de tasa: %v", err)
+	}
 }
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"project/internal/models"
+	"project/internal/ratelimit"
+)
+
+// Grupos de rutas con políticas de límite de tasa propias: las comparaciones son más
+// costosas que las lecturas y las escrituras.
+const (
+	RouteGroupRead    = "read"
+	RouteGroupWrite   = "write"
+	RouteGroupCompare = "compare"
+)
+
+// RouteGroups enumera los grupos de rutas.
+var RouteGroups = []string{RouteGroupRead, RouteGroupWrite, RouteGroupCompare}
+
+// RateLimitPolicies son las políticas del RateLimiter.
+type RateLimitPolicies struct {
+	// Global se aplica por IP de cliente a todas las peticiones, antes de autenticarlas.
+	Global ratelimit.Policy
+
+	// Tiers son las políticas de cada tier de cliente por grupo de rutas. Se aplican
+	// por cliente autenticado o, para las peticiones anónimas, por IP.
+	Tiers map[string]map[string]ratelimit.Policy
+}
+
+// DefaultRateLimitPolicies devuelve las políticas que se usan si no se configuran otras.
+func DefaultRateLimitPolicies() RateLimitPolicies {
+	perMinute := func(read, write, compare int) map[string]ratelimit.Policy {
+		return map[string]ratelimit.Policy{
+			RouteGroupRead:    {Limit: read, Window: time.Minute},
+			RouteGroupWrite:   {Limit: write, Window: time.Minute},
+			RouteGroupCompare: {Limit: compare, Window: time.Minute},
+		}
+	}
+
+	return RateLimitPolicies{
+		Global: ratelimit.Policy{Limit: 1000, Window: time.Minute},
+		Tiers: map[string]map[string]ratelimit.Policy{
+			models.TierAnonymous: perMinute(100, 30, 20),
+			models.TierStandard:  perMinute(300, 60, 60),
+			models.TierPremium:   perMinute(1200, 300, 300),
+		},
+	}
+}
+
+// policyFile es el formato de una política en el archivo de políticas, con la
+// ventana como duración de Go ("1m", "30s").
+type policyFile struct {
+	Limit  int    `json:"limit"`
+	Window string `json:"window"`
+}
+
+// LoadRateLimitPolicies lee un archivo JSON con políticas que reemplazan a las de
+// DefaultRateLimitPolicies del mismo tier y grupo de rutas:
+//
+//	{
+//	  "global": {"limit": 2000, "window": "1m"},
+//	  "tiers": {"premium": {"compare": {"limit": 600, "window": "1m"}}}
+//	}
+func LoadRateLimitPolicies(path string) (RateLimitPolicies, error) {
+	policies := DefaultRateLimitPolicies()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policies, fmt.Errorf("failed to read rate limit policies: %w", err)
+	}
+
+	var file struct {
+		Global *policyFile                      `json:"global"`
+		Tiers  map[string]map[string]policyFile `json:"tiers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return policies, fmt.Errorf("%s: invalid rate limit policies: %w", path, err)
+	}
+
+	if file.Global != nil {
+		if policies.Global, err = file.Global.policy(); err != nil {
+			return policies, fmt.Errorf("%s: global: %w", path, err)
+		}
+	}
+	for tier, groups := range file.Tiers {
+		if _, ok := policies.Tiers[tier]; !ok {
+			return policies, fmt.Errorf("%s: unknown tier %q", path, tier)
+		}
+		for group, entry := range groups {
+			if _, ok := policies.Tiers[tier][group]; !ok {
+				return policies, fmt.Errorf("%s: tier %q: unknown route group %q", path, tier, group)
+			}
+			policy, err := entry.policy()
+			if err != nil {
+				return policies, fmt.Errorf("%s: tier %q: %s: %w", path, tier, group, err)
+			}
+			policies.Tiers[tier][group] = policy
+		}
+	}
+	return policies, nil
+}
+
+// policy valida la política del archivo y la convierte a ratelimit.Policy.
+func (p policyFile) policy() (ratelimit.Policy, error) {
+	if p.Limit <= 0 {
+		return ratelimit.Policy{}, fmt.Errorf("limit must be a positive number")
+	}
+	window, err := time.ParseDuration(p.Window)
+	if err != nil || window <= 0 {
+		return ratelimit.Policy{}, fmt.Errorf("window must be a positive duration such as \"1m\"")
+	}
+	return ratelimit.Policy{Limit: p.Limit, Window: window}, nil
+}
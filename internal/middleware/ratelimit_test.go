@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"project/internal/models"
+	"project/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStore es un ratelimit.Store que devuelve siempre el mismo resultado o error.
+type stubStore struct {
+	result ratelimit.Result
+	err    error
+}
+
+func (s *stubStore) Allow(ctx context.Context, key string, policy ratelimit.Policy) (ratelimit.Result, error) {
+	return s.result, s.err
+}
+
+func (s *stubStore) Close() error {
+	return nil
+}
+
+// serve hace una petición a handler, con el cliente autenticado indicado si no es nil.
+func serve(handler http.Handler, principal *models.Principal) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+	if principal != nil {
+		req = req.WithContext(models.WithPrincipal(req.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestRateLimiter_Headers: Las cabeceras RateLimit-* y Retry-After reflejan el
+// resultado del store
+func TestRateLimiter_Headers(t *testing.T) {
+	store := &stubStore{result: ratelimit.Result{Allowed: true, Remaining: 1, ResetAfter: 29500 * time.Millisecond}}
+	rl := NewRateLimiter(RateLimitPolicies{Global: ratelimit.Policy{Limit: 2, Window: time.Minute}}, WithRateLimitStore(store))
+	handler := rl.RateLimit(okHandler)
+
+	rec := serve(handler, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	store.result = ratelimit.Result{RetryAfter: 19200 * time.Millisecond, ResetAfter: 50 * time.Second}
+	rec = serve(handler, nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "20", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "50", rec.Header().Get("RateLimit-Reset"))
+	assert.Contains(t, rec.Body.String(), "TOO_MANY_REQUESTS")
+}
+
+// TestRateLimiter_StoreUnavailable: Si el store falla, las peticiones se permiten sin
+// cabeceras o, con WithFailClosed, se rechazan con 503
+func TestRateLimiter_StoreUnavailable(t *testing.T) {
+	store := &stubStore{err: stdErrors.New("connection refused")}
+	policies := RateLimitPolicies{Global: ratelimit.Policy{Limit: 2, Window: time.Minute}}
+
+	rec := serve(NewRateLimiter(policies, WithRateLimitStore(store)).RateLimit(okHandler), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+
+	rec = serve(NewRateLimiter(policies, WithRateLimitStore(store), WithFailClosed(true)).RateLimit(okHandler), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "SERVICE_UNAVAILABLE")
+}
+
+// TestRateLimiter_TiersAndGroups: Cada cliente tiene un límite por grupo de rutas
+// según su tier y las respuestas informan de la política más cercana a agotarse
+func TestRateLimiter_TiersAndGroups(t *testing.T) {
+	rl := NewRateLimiter(RateLimitPolicies{
+		Global: ratelimit.Policy{Limit: 100, Window: time.Minute},
+		Tiers: map[string]map[string]ratelimit.Policy{
+			models.TierAnonymous: {RouteGroupCompare: {Limit: 1, Window: time.Minute}},
+			models.TierStandard:  {RouteGroupCompare: {Limit: 2, Window: time.Minute}},
+			models.TierPremium:   {RouteGroupCompare: {Limit: 10, Window: time.Minute}},
+		},
+	})
+	defer rl.Stop()
+	compare := rl.RateLimit(rl.Limit(RouteGroupCompare)(okHandler))
+	read := rl.RateLimit(rl.Limit(RouteGroupRead)(okHandler))
+
+	rec := serve(compare, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(compare, nil).Code)
+
+	// Los clientes autenticados no comparten el límite de la IP y, sin tier, tienen
+	// el estándar.
+	ci := &models.Principal{Name: "apikey:ci"}
+	assert.Equal(t, http.StatusOK, serve(compare, ci).Code)
+	assert.Equal(t, http.StatusOK, serve(compare, ci).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(compare, ci).Code)
+
+	rec = serve(compare, &models.Principal{Name: "jwt:user-42", Tier: models.TierPremium})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", rec.Header().Get("RateLimit-Remaining"))
+
+	// Un grupo sin política solo tiene el límite global.
+	rec = serve(read, ci)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+}
+
+// TestLoadRateLimitPolicies: Las políticas del archivo reemplazan a las por defecto
+// del mismo tier y grupo, y los tiers, grupos y valores desconocidos son un error
+func TestLoadRateLimitPolicies(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "limits.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	policies, err := LoadRateLimitPolicies(write(`{
+		"global": {"limit": 2000, "window": "1m"},
+		"tiers": {"premium": {"compare": {"limit": 50, "window": "10s"}}}
+	}`))
+	require.NoError(t, err)
+	defaults := DefaultRateLimitPolicies()
+	assert.Equal(t, ratelimit.Policy{Limit: 2000, Window: time.Minute}, policies.Global)
+	assert.Equal(t, ratelimit.Policy{Limit: 50, Window: 10 * time.Second}, policies.Tiers[models.TierPremium][RouteGroupCompare])
+	assert.Equal(t, defaults.Tiers[models.TierPremium][RouteGroupRead], policies.Tiers[models.TierPremium][RouteGroupRead])
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"unknown tier", `{"tiers": {"gold": {}}}`, `unknown tier "gold"`},
+		{"unknown route group", `{"tiers": {"standard": {"delete": {"limit": 1, "window": "1m"}}}}`, `unknown route group "delete"`},
+		{"non-positive limit", `{"global": {"limit": 0, "window": "1m"}}`, "limit must be a positive number"},
+		{"invalid window", `{"global": {"limit": 10, "window": "60"}}`, "window must be a positive duration"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadRateLimitPolicies(write(tc.content))
+			assert.ErrorContains(t, err, tc.message)
+		})
+	}
+}
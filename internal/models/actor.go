@@ -0,0 +1,47 @@
+package models
+
+import "context"
+
+// Orígenes de una escritura registrados en el historial de revisiones.
+const (
+	SourceAPI     = "api"
+	SourceImport  = "import"
+	SourceSeed    = "seed"
+	SourceRestore = "restore"
+)
+
+// Actor identifica quién realiza una escritura: Name es el autor (un cliente de la
+// API o un proceso) y Source el origen de la escritura (ver las constantes Source*).
+type Actor struct {
+	Name   string
+	Source string
+}
+
+// SystemActor es el autor de las escrituras cuyo contexto no identifica a nadie,
+// por ejemplo las del seed al iniciar el servidor.
+const SystemActor = "system"
+
+// actorKey es la clave del Actor en el contexto.
+type actorKey struct{}
+
+// WithActor devuelve una copia de ctx con el autor de las escrituras.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithSource devuelve una copia de ctx con el mismo autor y otro origen.
+func WithSource(ctx context.Context, source string) context.Context {
+	actor := ActorFromContext(ctx)
+	actor.Source = source
+	return WithActor(ctx, actor)
+}
+
+// ActorFromContext devuelve el autor de las escrituras guardado en ctx. Sin autor,
+// devuelve SystemActor sin origen.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok || actor.Name == "" {
+		actor.Name = SystemActor
+	}
+	return actor
+}
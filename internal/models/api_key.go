@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Scopes de las API keys: cada uno habilita un grupo de endpoints. ScopeAdmin
+// concede todos los demás.
+const (
+	ScopeItemsRead    = "items:read"
+	ScopeItemsWrite   = "items:write"
+	ScopeItemsCompare = "items:compare"
+	ScopeAdmin        = "admin"
+)
+
+// Scopes enumera los scopes que se pueden asignar a una API key.
+var Scopes = []string{ScopeItemsRead, ScopeItemsWrite, ScopeItemsCompare, ScopeAdmin}
+
+// IsValidScope indica si scope es uno de Scopes.
+func IsValidScope(scope string) bool {
+	for _, valid := range Scopes {
+		if scope == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey es una clave de acceso a la API. La clave completa solo se muestra al
+// crearla o rotarla; se guarda su hash y Prefix, la parte pública que la identifica.
+type APIKey struct {
+	ID         int64
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []string
+	Tier       string
+	CreatedAt  time.Time
+	RotatedAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Revoked indica si la clave fue revocada.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,64 @@
+package models
+
+// Category representa una categoría de productos. Las categorías forman un
+// árbol: ParentID es nil en las categorías raíz.
+type Category struct {
+	ID       int64      `json:"id" db:"id"`
+	Name     string     `json:"name" db:"name"`
+	Slug     string     `json:"slug" db:"slug"`
+	ParentID *int64     `json:"parent_id" db:"parent_id"`
+	Children []Category `json:"children,omitempty"`
+}
+
+// BuildCategoryTree arma el árbol de categorías a partir de una lista plana.
+// Las categorías cuyo padre no está en la lista se tratan como raíces.
+// El orden de la lista se conserva en cada nivel.
+func BuildCategoryTree(categories []Category) []Category {
+	ids := make(map[int64]bool, len(categories))
+	children := make(map[int64][]Category)
+	for _, category := range categories {
+		ids[category.ID] = true
+	}
+
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentID != nil && ids[*category.ParentID] {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+			continue
+		}
+		roots = append(roots, category)
+	}
+
+	var attach func(nodes []Category) []Category
+	attach = func(nodes []Category) []Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := attach(roots)
+	if tree == nil {
+		return []Category{}
+	}
+	return tree
+}
+
+// RootCategoryID devuelve el ID de la categoría raíz del árbol al que pertenece
+// la categoría indicada. byID debe contener todas las categorías.
+// Se corta ante ciclos para no quedar en un bucle infinito.
+func RootCategoryID(id int64, byID map[int64]Category) int64 {
+	seen := make(map[int64]bool)
+	current := id
+
+	for !seen[current] {
+		seen[current] = true
+		category, ok := byID[current]
+		if !ok || category.ParentID == nil {
+			return current
+		}
+		current = *category.ParentID
+	}
+
+	return current
+}
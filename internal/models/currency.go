@@ -0,0 +1,26 @@
+package models
+
+import "strings"
+
+// DefaultCurrency es la moneda de los items que no indican una y de los creados
+// antes de que los items tuvieran moneda.
+const DefaultCurrency = "USD"
+
+// SupportedCurrencies son los códigos ISO 4217 de las monedas en las que se venden
+// los items.
+var SupportedCurrencies = []string{"USD", "EUR", "COP"}
+
+// NormalizeCurrency devuelve el código de moneda sin espacios y en mayúsculas.
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsSupportedCurrency indica si code (ya normalizado) es una de SupportedCurrencies.
+func IsSupportedCurrency(code string) bool {
+	for _, supported := range SupportedCurrencies {
+		if code == supported {
+			return true
+		}
+	}
+	return false
+}
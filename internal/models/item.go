@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // Item representa un producto dentro de la capa de dominio.
@@ -15,8 +16,25 @@ type Item struct {
 	ImageURL       string         `json:"image_url" db:"image_url"`
 	Description    string         `json:"description" db:"description"`
 	Price          float64        `json:"price" db:"price"`
+	Currency       string         `json:"currency" db:"currency"`
 	Rating         float64        `json:"rating" db:"rating"`
 	Specifications Specifications `json:"specifications" db:"specifications"`
+	CategoryID     *int64         `json:"category_id" db:"category_id"`
+
+	// Version se incrementa con cada modificación del item; CreatedAt y UpdatedAt
+	// registran el alta y la última modificación. Los asigna el repositorio. Al
+	// actualizar, una Version distinta de cero es la versión que el cliente espera
+	// modificar (ver ItemService.UpdateItemVersioned).
+	Version   int64     `json:"version,omitzero" db:"version"`
+	CreatedAt time.Time `json:"created_at,omitzero" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitzero" db:"updated_at"`
+}
+
+// CatalogState identifica el estado del catálogo completo: Version cambia con
+// cualquier alta, modificación o baja de items y UpdatedAt registra la última.
+type CatalogState struct {
+	Version   int64
+	UpdatedAt time.Time
 }
 
 // Specifications define un mapa genérico utilizado para almacenar características
@@ -57,8 +75,17 @@ func (s *Specifications) Scan(value interface{}) error {
 //   - Debe enviarse una lista de IDs.
 //   - Debe contener al menos 2 ítems.
 //   - No debe exceder los 10 ítems.
+//
+// Si Strict es true, la comparación se rechaza cuando los ítems pertenecen a
+// categorías no relacionadas; si es false, solo se devuelve una advertencia.
+//
+// Currency, que llega como query param, es la moneda a la que se convierten los
+// precios antes de compararlos. Vacía, se usa la moneda común de los ítems o, si
+// difieren, DefaultCurrency.
 type CompareRequest struct {
-	ItemIDs []int64 `json:"item_ids" validate:"required,min=2,max=10"`
+	ItemIDs  []int64 `json:"item_ids" validate:"required,min=2,max=10"`
+	Strict   bool    `json:"strict"`
+	Currency string  `json:"-"`
 }
 
 // CompareResponse representa la estructura enviada como respuesta al cliente
@@ -66,14 +93,58 @@ type CompareRequest struct {
 type CompareResponse struct {
 	Items      []Item            `json:"items"`
 	Comparison ComparisonDetails `json:"comparison"`
+	Warnings   []string          `json:"warnings,omitempty"`
 }
 
 // ComparisonDetails contiene el resultado del análisis comparativo entre ítems.
 type ComparisonDetails struct {
-	PriceRange  PriceRange         `json:"price_range"`
-	RatingRange RatingRange        `json:"rating_range"`
-	CommonSpecs []string           `json:"common_specs"`
-	UniqueSpecs map[int64][]string `json:"unique_specs"`
+	// Currency es la moneda de los precios de los ítems, de PriceRange y de LowestPrices30d.
+	Currency   string     `json:"currency"`
+	PriceRange PriceRange `json:"price_range"`
+
+	// LowestPrices30d contiene, por ítem, el precio más bajo que tuvo en los últimos
+	// 30 días, incluido el actual, convertido a Currency con el tipo de cambio actual.
+	// Solo está presente si hay historial de precios.
+	LowestPrices30d map[int64]float64 `json:"lowest_price_30d,omitempty"`
+
+	RatingRange RatingRange         `json:"rating_range"`
+	CommonSpecs []string            `json:"common_specs"`
+	UniqueSpecs map[int64][]string  `json:"unique_specs"`
+	SpecDiff    map[string]SpecDiff `json:"spec_diff"`
+
+	// SpecRanges contiene, para las especificaciones cuantificables, el rango de
+	// valores normalizados y el mejor valor, igual que PriceRange y RatingRange.
+	SpecRanges map[string]SpecRange `json:"spec_ranges"`
+
+	// Winners indica, para cada criterio (price, rating y cada especificación
+	// cuantificable), los ítems con el mejor valor. Los empates se incluyen todos.
+	Winners map[string][]int64 `json:"winners"`
+
+	// WinnerTally cuenta cuántos criterios gana cada ítem.
+	WinnerTally map[int64]int `json:"winner_tally"`
+}
+
+// SpecRange resume los valores normalizados de una especificación entre ítems.
+// Values contiene el valor canónico de cada ítem que pudo normalizarse; Best es el
+// mejor valor según Direction y BestItemIDs los ítems que lo alcanzan.
+type SpecRange struct {
+	Dimension   Dimension         `json:"dimension"`
+	Unit        string            `json:"unit"`
+	Direction   Direction         `json:"direction"`
+	Min         float64           `json:"min"`
+	Max         float64           `json:"max"`
+	Best        float64           `json:"best"`
+	BestItemIDs []int64           `json:"best_item_ids"`
+	Values      map[int64]float64 `json:"values"`
+}
+
+// SpecDiff compara el valor de una especificación entre todos los ítems.
+// Values contiene una entrada por ítem comparado; el valor es nil (null en JSON)
+// cuando el ítem no tiene la especificación. Identical es true solo si todos los
+// ítems tienen la especificación y sus valores coinciden.
+type SpecDiff struct {
+	Values    map[int64]interface{} `json:"values"`
+	Identical bool                  `json:"identical"`
 }
 
 // PriceRange representa el precio mínimo y máximo entre un conjunto de ítems.
@@ -87,3 +158,64 @@ type RatingRange struct {
 	Min float64 `json:"min"`
 	Max float64 `json:"max"`
 }
+
+// ItemPatch representa una actualización parcial de un ítem (PATCH).
+// Los campos nil no se modifican; solo se aplican los campos enviados por el cliente.
+type ItemPatch struct {
+	Name           *string         `json:"name"`
+	ImageURL       *string         `json:"image_url"`
+	Description    *string         `json:"description"`
+	Price          *float64        `json:"price"`
+	Currency       *string         `json:"currency"`
+	Rating         *float64        `json:"rating"`
+	Specifications *Specifications `json:"specifications"`
+	CategoryID     *int64          `json:"category_id"`
+
+	// Version, si se envía, es la versión que el cliente espera modificar: el patch
+	// solo se aplica si coincide con la versión actual del item.
+	Version *int64 `json:"version"`
+}
+
+// Apply aplica los campos presentes en el patch sobre el ítem recibido.
+func (p ItemPatch) Apply(item *Item) {
+	if p.Name != nil {
+		item.Name = *p.Name
+	}
+	if p.ImageURL != nil {
+		item.ImageURL = *p.ImageURL
+	}
+	if p.Description != nil {
+		item.Description = *p.Description
+	}
+	if p.Price != nil {
+		item.Price = *p.Price
+	}
+	if p.Currency != nil {
+		item.Currency = *p.Currency
+	}
+	if p.Rating != nil {
+		item.Rating = *p.Rating
+	}
+	if p.Specifications != nil {
+		item.Specifications = *p.Specifications
+	}
+	if p.CategoryID != nil {
+		item.CategoryID = p.CategoryID
+	}
+}
+
+// SearchResult representa un ítem encontrado por la búsqueda de texto completo.
+// Score es mayor cuanto más relevante es el resultado; Snippet contiene un
+// fragmento del texto con los términos encontrados resaltados con <mark>.
+type SearchResult struct {
+	Item            Item    `json:"item"`
+	Score           float64 `json:"score"`
+	HighlightedName string  `json:"highlighted_name"`
+	Snippet         string  `json:"snippet"`
+}
+
+// SearchResponse es la respuesta del endpoint de búsqueda.
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
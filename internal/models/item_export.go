@@ -0,0 +1,15 @@
+package models
+
+// Formatos admitidos por la exportación del catálogo.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// ExportWriter escribe los items exportados a medida que se leen de la base de datos,
+// sin acumular el catálogo en memoria. WriteHeader se llama una única vez, antes del
+// primer item, con las claves de especificación del catálogo ordenadas alfabéticamente.
+type ExportWriter interface {
+	WriteHeader(specKeys []string) error
+	WriteItem(item Item) error
+}
@@ -0,0 +1,57 @@
+package models
+
+import "project/internal/errors"
+
+// Formatos aceptados por la importación masiva de items.
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// ImportRow es una fila decodificada de un archivo de importación. Line es la línea
+// del archivo en la que empieza la fila. Problems contiene los errores de
+// decodificación de la fila (por ejemplo, un precio que no es un número); una fila
+// con problemas se rechaza sin validarse.
+type ImportRow struct {
+	Line     int
+	Item     Item
+	Problems []errors.FieldError
+}
+
+// ImportReader entrega las filas de un archivo de importación de a una, sin
+// cargar el archivo completo en memoria. Next devuelve io.EOF cuando no quedan
+// filas; cualquier otro error interrumpe la importación.
+type ImportReader interface {
+	Next() (ImportRow, error)
+}
+
+// Acciones aplicadas a una fila aceptada.
+const (
+	ImportActionCreated = "created"
+	ImportActionUpdated = "updated"
+)
+
+// ImportReport es el resultado de una importación. Con DryRun las filas se
+// validan y aplican dentro de una transacción que después se revierte.
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Accepted []ImportAccepted `json:"accepted"`
+	Rejected []ImportRejected `json:"rejected"`
+}
+
+// ImportAccepted describe una fila aplicada. En un dry run, ID es 0 para las filas
+// que se habrían creado.
+type ImportAccepted struct {
+	Line   int    `json:"line"`
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// ImportRejected describe una fila rechazada y los motivos, por campo.
+type ImportRejected struct {
+	Line    int                 `json:"line"`
+	Name    string              `json:"name,omitempty"`
+	Reasons []errors.FieldError `json:"reasons"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Intervalos de agrupación del historial de precios.
+const (
+	PriceIntervalDaily  = "daily"
+	PriceIntervalWeekly = "weekly"
+)
+
+// PricePoint es un precio del item, en su moneda, y el momento desde el que rige.
+type PricePoint struct {
+	Price      float64   `json:"price"`
+	Currency   string    `json:"currency"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// PriceHistoryQuery son los parámetros del historial de precios: la ventana, en
+// días hasta el momento actual, el intervalo de agrupación (vacío para obtener
+// los cambios de precio sin agrupar) y la moneda en la que se expresan los precios
+// (vacía para usar la moneda actual del item).
+type PriceHistoryQuery struct {
+	Days     int
+	Interval string
+	Currency string
+}
+
+// PriceStats resume los precios vigentes durante un período. Avg es el promedio
+// ponderado por el tiempo que rigió cada precio.
+type PriceStats struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// PriceBucket son las estadísticas de precio de un día o una semana (que empieza
+// el lunes), en UTC. Los períodos anteriores al alta del item no tienen bucket.
+type PriceBucket struct {
+	Start time.Time `json:"start"`
+	PriceStats
+}
+
+// PriceHistory es el historial de precios de un item en la ventana [From, To], con
+// todos los importes convertidos a Currency.
+// Points contiene los cambios de precio, sin agrupar; el primero puede ser anterior
+// a From, porque es el precio vigente al inicio de la ventana. Con Interval,
+// Buckets sustituye a Points.
+type PriceHistory struct {
+	ItemID       int64         `json:"item_id"`
+	Currency     string        `json:"currency"`
+	CurrentPrice float64       `json:"current_price"`
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	Interval     string        `json:"interval,omitempty"`
+	Stats        PriceStats    `json:"stats"`
+	Points       []PricePoint  `json:"points,omitempty"`
+	Buckets      []PriceBucket `json:"buckets,omitempty"`
+}
@@ -0,0 +1,120 @@
+package models
+
+// Valores por defecto y límites para la paginación del listado de ítems.
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ItemQuery agrupa los parámetros de paginación, ordenamiento y filtrado
+// utilizados para listar ítems. Los filtros nil no se aplican.
+type ItemQuery struct {
+	Limit     int
+	Offset    int
+	Sort      []SortField
+	MinPrice  *float64
+	MaxPrice  *float64
+	MinRating *float64
+
+	// MinID y MaxID limitan el rango de IDs (ambos extremos incluidos).
+	MinID *int64
+	MaxID *int64
+
+	// CategoryID limita el listado a la categoría indicada y a todas sus subcategorías.
+	CategoryID *int64
+
+	// SpecFilters filtra por valores dentro del JSON de especificaciones.
+	// Todos los filtros deben cumplirse (AND).
+	SpecFilters []SpecFilter
+
+	// Currency es la moneda a la que se convierten los precios de la respuesta.
+	// Vacía, cada item conserva la suya.
+	Currency string
+
+	// PriceRates contiene, por moneda, el factor que convierte los precios a la
+	// moneda de MinPrice, MaxPrice y del orden por precio. Lo completa el servicio;
+	// vacío, los precios se comparan sin convertir.
+	PriceRates map[string]float64
+}
+
+// Campos por los que se puede ordenar el listado de ítems (parámetro sort).
+const (
+	SortByID     = "id"
+	SortByName   = "name"
+	SortByPrice  = "price"
+	SortByRating = "rating"
+)
+
+// SortFields enumera los campos por los que se puede ordenar el listado de ítems.
+// El servicio rechaza cualquier otro y el repositorio traduce cada uno a SQL.
+var SortFields = []string{SortByID, SortByName, SortByPrice, SortByRating}
+
+// IsSortField indica si field es uno de SortFields.
+func IsSortField(field string) bool {
+	for _, valid := range SortFields {
+		if field == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// SortField representa un criterio de ordenamiento sobre un campo del ítem.
+// Desc indica orden descendente (prefijo "-" en el parámetro sort).
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ItemPage es el sobre (envelope) devuelto por el listado paginado de ítems.
+type ItemPage struct {
+	Data       []Item     `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination contiene los metadatos de la página devuelta.
+// Next y Prev son nil cuando no existe una página siguiente o anterior.
+type Pagination struct {
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+	Next   *string `json:"next"`
+	Prev   *string `json:"prev"`
+}
+
+// SpecOperator es el operador de comparación de un filtro de especificación.
+type SpecOperator string
+
+const (
+	SpecOpEquals       SpecOperator = "eq"
+	SpecOpContains     SpecOperator = "contains"
+	SpecOpLess         SpecOperator = "lt"
+	SpecOpLessEqual    SpecOperator = "lte"
+	SpecOpGreater      SpecOperator = "gt"
+	SpecOpGreaterEqual SpecOperator = "gte"
+)
+
+// IsNumeric indica si el operador compara el valor numérico de la especificación.
+func (op SpecOperator) IsNumeric() bool {
+	switch op {
+	case SpecOpLess, SpecOpLessEqual, SpecOpGreater, SpecOpGreaterEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// SpecFilter representa un filtro sobre una clave de las especificaciones,
+// por ejemplo spec.memory=32GB o spec.weight_lt=2kg.
+type SpecFilter struct {
+	Key      string
+	Operator SpecOperator
+	Value    string
+}
+
+// Quantity devuelve el valor del filtro normalizado a la unidad canónica de su
+// dimensión ("2kg" -> 2 kg, "1TB" -> 1024 GB). Un número sin unidad tiene
+// DimensionNumber. Devuelve false si el valor no es cuantificable.
+func (f SpecFilter) Quantity() (NormalizedValue, bool) {
+	return NormalizeSpecValue(f.Value)
+}
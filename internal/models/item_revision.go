@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Operaciones registradas en el historial de revisiones.
+const (
+	RevisionCreate = "create"
+	RevisionUpdate = "update"
+	RevisionDelete = "delete"
+)
+
+// ItemSnapshot contiene los valores de un item en una revisión: los campos que
+// edita el cliente, sin el ID, la versión ni las fechas.
+type ItemSnapshot struct {
+	Name           string         `json:"name"`
+	ImageURL       string         `json:"image_url"`
+	Description    string         `json:"description"`
+	Price          float64        `json:"price"`
+	Currency       string         `json:"currency"`
+	Rating         float64        `json:"rating"`
+	Specifications Specifications `json:"specifications"`
+	CategoryID     *int64         `json:"category_id"`
+}
+
+// Apply reemplaza los campos del item por los del snapshot.
+func (s ItemSnapshot) Apply(item *Item) {
+	item.Name = s.Name
+	item.ImageURL = s.ImageURL
+	item.Description = s.Description
+	item.Price = s.Price
+	item.Currency = s.Currency
+	item.Rating = s.Rating
+	item.Specifications = s.Specifications
+	item.CategoryID = s.CategoryID
+}
+
+// ItemRevision es un cambio de un item. Revision numera los cambios de cada item
+// desde 1. OldValues es nil en las altas y NewValues en las bajas. Actor y Source
+// están vacíos si la escritura no se hizo a través de la aplicación.
+type ItemRevision struct {
+	ItemID    int64         `json:"item_id"`
+	Revision  int64         `json:"revision"`
+	Operation string        `json:"operation"`
+	Actor     string        `json:"actor"`
+	Source    string        `json:"source"`
+	ChangedAt time.Time     `json:"changed_at"`
+	OldValues *ItemSnapshot `json:"old_values"`
+	NewValues *ItemSnapshot `json:"new_values"`
+}
+
+// ItemRevisionHistory es el historial de revisiones de un item, de la más antigua
+// a la más reciente.
+type ItemRevisionHistory struct {
+	ItemID    int64          `json:"item_id"`
+	Revisions []ItemRevision `json:"revisions"`
+}
+
+// RevisionDiff son las diferencias entre los valores del item en dos revisiones.
+// Cada especificación se compara por separado, como "specifications.<clave>".
+type RevisionDiff struct {
+	ItemID  int64         `json:"item_id"`
+	From    int64         `json:"from"`
+	To      int64         `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange es el cambio de un campo entre dos revisiones. From o To son nil si
+// el campo (una especificación) no existía en esa revisión.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
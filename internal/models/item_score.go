@@ -0,0 +1,42 @@
+package models
+
+// ScoreRequest es el cuerpo de POST /api/v1/items/compare/score.
+// Weights asocia cada criterio (price, rating o una especificación cuantificable)
+// con su peso relativo; los pesos se normalizan, por lo que pueden expresarse
+// como porcentajes (50, 30, 20) o fracciones (0.5, 0.3, 0.2).
+// Strict tiene el mismo significado que en CompareRequest.
+type ScoreRequest struct {
+	ItemIDs []int64            `json:"item_ids"`
+	Weights map[string]float64 `json:"weights"`
+	Strict  bool               `json:"strict"`
+}
+
+// ScoreResponse contiene el ranking de los items según los pesos indicados.
+type ScoreResponse struct {
+	// Weights son los pesos normalizados, que suman 1.
+	Weights  map[string]float64 `json:"weights"`
+	Ranking  []ItemScore        `json:"ranking"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// ItemScore es la puntuación de un item (0-100) con su desglose por criterio.
+type ItemScore struct {
+	Rank      int                       `json:"rank"`
+	ItemID    int64                     `json:"item_id"`
+	Name      string                    `json:"name"`
+	Score     float64                   `json:"score"`
+	Breakdown map[string]CriterionScore `json:"breakdown"`
+}
+
+// CriterionScore detalla la aportación de un criterio a la puntuación de un item.
+// Value es el valor normalizado del item (nil si no tiene la especificación),
+// Score su puntuación 0-100 dentro del criterio y Contribution la parte de la
+// puntuación total que aporta (Score multiplicado por el peso normalizado).
+type CriterionScore struct {
+	Value        *float64  `json:"value"`
+	Unit         string    `json:"unit,omitempty"`
+	Direction    Direction `json:"direction"`
+	Weight       float64   `json:"weight"`
+	Score        float64   `json:"score"`
+	Contribution float64   `json:"contribution"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "context"
+
+// Tiers de los clientes: cada uno tiene sus propios límites de tasa. TierAnonymous
+// es el de las peticiones sin cliente autenticado y no se puede asignar.
+const (
+	TierAnonymous = "anonymous"
+	TierStandard  = "standard"
+	TierPremium   = "premium"
+)
+
+// Tiers enumera los tiers que se pueden asignar a un cliente autenticado.
+var Tiers = []string{TierStandard, TierPremium}
+
+// IsValidTier indica si tier es uno de Tiers.
+func IsValidTier(tier string) bool {
+	for _, valid := range Tiers {
+		if tier == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal identifica al cliente autenticado de una petición: Name es su
+// identidad (por ejemplo "apikey:ci"), Scopes, los permisos que tiene y Tier, el
+// tier que determina sus límites de tasa.
+type Principal struct {
+	Name   string
+	Scopes []string
+	Tier   string
+}
+
+// HasScope indica si el cliente tiene el scope indicado o ScopeAdmin.
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey es la clave del Principal en el contexto.
+type principalKey struct{}
+
+// WithPrincipal devuelve una copia de ctx con el cliente autenticado.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext devuelve el cliente autenticado guardado en ctx y si hay uno.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
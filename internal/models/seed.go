@@ -0,0 +1,9 @@
+package models
+
+// SeedReport resume el resultado de cargar fixtures: cuántos items se insertaron,
+// cuántos se actualizaron y cuántos se omitieron por no tener cambios.
+type SeedReport struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SpecValueType es el tipo de valor admitido por un campo de especificación.
+type SpecValueType string
+
+const (
+	SpecTypeString   SpecValueType = "string"   // texto libre
+	SpecTypeNumber   SpecValueType = "number"   // número sin unidad
+	SpecTypeBoolean  SpecValueType = "boolean"  // true/false o "Yes"/"No"
+	SpecTypeQuantity SpecValueType = "quantity" // número con unidad de la dimensión indicada
+)
+
+// SpecField describe una clave de especificación permitida en una categoría.
+// Dimension solo se usa en los campos de tipo quantity; Unit es la unidad
+// canónica de esa dimensión. CategoryID es la categoría que declara el campo
+// e Inherited indica si proviene de una categoría ancestro.
+type SpecField struct {
+	Key        string        `json:"key"`
+	Label      string        `json:"label"`
+	Type       SpecValueType `json:"type"`
+	Dimension  Dimension     `json:"dimension,omitempty"`
+	Unit       string        `json:"unit,omitempty"`
+	Required   bool          `json:"required"`
+	CategoryID int64         `json:"category_id"`
+	Inherited  bool          `json:"inherited"`
+}
+
+// SpecSchema es el conjunto de campos de especificación de una categoría,
+// incluidos los heredados de sus categorías ancestro.
+type SpecSchema struct {
+	CategoryID int64       `json:"category_id"`
+	Fields     []SpecField `json:"fields"`
+}
+
+// SpecProblem es un problema de validación de una clave de especificación.
+type SpecProblem struct {
+	Key     string
+	Message string
+}
+
+// ResolveSpecSchema construye el esquema de una categoría a partir de los campos
+// de la categoría y de sus ancestros, ordenados desde la raíz. Si una clave se
+// declara en varios niveles, prevalece la definición más cercana a la categoría.
+func ResolveSpecSchema(categoryID int64, fields []SpecField) SpecSchema {
+	positions := make(map[string]int, len(fields))
+	resolved := make([]SpecField, 0, len(fields))
+
+	for _, field := range fields {
+		field.Inherited = field.CategoryID != categoryID
+		if field.Type == SpecTypeQuantity {
+			field.Unit = field.Dimension.CanonicalUnit()
+		}
+
+		if i, ok := positions[field.Key]; ok {
+			resolved[i] = field
+			continue
+		}
+		positions[field.Key] = len(resolved)
+		resolved = append(resolved, field)
+	}
+
+	return SpecSchema{CategoryID: categoryID, Fields: resolved}
+}
+
+// Enforced indica si el esquema restringe las especificaciones. Una categoría
+// sin campos declarados (ni heredados) acepta cualquier especificación.
+func (s SpecSchema) Enforced() bool {
+	return len(s.Fields) > 0
+}
+
+// Validate comprueba que las especificaciones usen solo claves del esquema, que
+// estén las obligatorias y que cada valor sea del tipo declarado.
+// Los problemas se devuelven ordenados por clave.
+func (s SpecSchema) Validate(specs Specifications) []SpecProblem {
+	if !s.Enforced() {
+		return nil
+	}
+
+	fields := make(map[string]SpecField, len(s.Fields))
+	for _, field := range s.Fields {
+		fields[field.Key] = field
+	}
+
+	var problems []SpecProblem
+
+	for key, value := range specs {
+		field, ok := fields[key]
+		if !ok {
+			message := "clave no permitida en esta categoría"
+			if suggestion, found := s.suggestKey(key); found {
+				message += fmt.Sprintf("; ¿quiso decir %q?", suggestion)
+			}
+			problems = append(problems, SpecProblem{Key: key, Message: message})
+			continue
+		}
+
+		if message, ok := field.checkValue(value); !ok {
+			problems = append(problems, SpecProblem{Key: key, Message: message})
+		}
+	}
+
+	for _, field := range s.Fields {
+		if _, ok := specs[field.Key]; field.Required && !ok {
+			problems = append(problems, SpecProblem{Key: field.Key, Message: "es obligatoria"})
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Key < problems[j].Key
+	})
+	return problems
+}
+
+// suggestKey busca una clave del esquema que coincida con la recibida tras
+// normalizarla ("Battery Life" o "battery-life" -> "battery_life").
+func (s SpecSchema) suggestKey(key string) (string, bool) {
+	normalized := normalizeSpecKey(key)
+	for _, field := range s.Fields {
+		if normalizeSpecKey(field.Key) == normalized {
+			return field.Key, true
+		}
+	}
+	return "", false
+}
+
+// normalizeSpecKey pasa la clave a minúsculas y unifica espacios y guiones como "_".
+func normalizeSpecKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	return strings.Join(strings.FieldsFunc(key, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+// checkValue valida un valor contra el tipo del campo. Devuelve el mensaje del
+// problema y false si el valor no es válido.
+func (f SpecField) checkValue(value interface{}) (string, bool) {
+	switch f.Type {
+	case SpecTypeString:
+		if text, ok := value.(string); !ok || strings.TrimSpace(text) == "" {
+			return "debe ser un texto no vacío", false
+		}
+	case SpecTypeNumber:
+		normalized, ok := NormalizeSpecValue(value)
+		if !ok || normalized.Dimension != DimensionNumber {
+			return "debe ser un número", false
+		}
+	case SpecTypeBoolean:
+		if !isSpecBoolean(value) {
+			return `debe ser un booleano (true/false o "Yes"/"No")`, false
+		}
+	case SpecTypeQuantity:
+		normalized, ok := NormalizeSpecValue(value)
+		if !ok || normalized.Dimension != f.Dimension {
+			return fmt.Sprintf("debe ser una cantidad de %s con unidad (p. ej. %s)", f.Dimension, f.Dimension.example()), false
+		}
+	default:
+		return fmt.Sprintf("tipo de campo desconocido: %q", f.Type), false
+	}
+	return "", true
+}
+
+// isSpecBoolean acepta booleanos JSON y los textos yes/no/true/false.
+func isSpecBoolean(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "yes", "no", "true", "false":
+			return true
+		}
+	}
+	return false
+}
+
+// dimensionExamples muestra un valor válido de cada dimensión en los mensajes de error.
+var dimensionExamples = map[Dimension]string{
+	DimensionDataSize:  "16GB",
+	DimensionWeight:    "1.5 kg",
+	DimensionDuration:  "10 hours",
+	DimensionLength:    "14-inch",
+	DimensionFrequency: "3.2 GHz",
+	DimensionNumber:    "42",
+}
+
+// example devuelve un valor de ejemplo de la dimensión.
+func (d Dimension) example() string {
+	return dimensionExamples[d]
+}
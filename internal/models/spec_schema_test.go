@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSchema devuelve el esquema de una categoría (ID 2) que hereda campos de su padre (ID 1)
+func testSchema() SpecSchema {
+	return ResolveSpecSchema(2, []SpecField{
+		{CategoryID: 1, Key: "memory", Type: SpecTypeQuantity, Dimension: DimensionDataSize, Required: true},
+		{CategoryID: 1, Key: "battery_life", Type: SpecTypeString},
+		{CategoryID: 2, Key: "battery_life", Type: SpecTypeQuantity, Dimension: DimensionDuration},
+		{CategoryID: 2, Key: "touchscreen", Type: SpecTypeBoolean},
+		{CategoryID: 2, Key: "cores", Type: SpecTypeNumber},
+	})
+}
+
+// TestResolveSpecSchema_InheritanceAndOverrides: Los campos heredados se conservan y
+// la categoría hija puede redefinirlos
+func TestResolveSpecSchema_InheritanceAndOverrides(t *testing.T) {
+	schema := testSchema()
+
+	assert.Len(t, schema.Fields, 4)
+	assert.Equal(t, "memory", schema.Fields[0].Key)
+	assert.True(t, schema.Fields[0].Inherited)
+	assert.Equal(t, "GB", schema.Fields[0].Unit)
+
+	// La categoría hija redefine battery_life en la misma posición
+	battery := schema.Fields[1]
+	assert.Equal(t, "battery_life", battery.Key)
+	assert.Equal(t, SpecTypeQuantity, battery.Type)
+	assert.Equal(t, "h", battery.Unit)
+	assert.False(t, battery.Inherited)
+}
+
+// TestSpecSchema_Validate: Claves permitidas, obligatorias y tipos de valor
+func TestSpecSchema_Validate(t *testing.T) {
+	schema := testSchema()
+
+	tests := []struct {
+		name     string
+		specs    Specifications
+		expected []SpecProblem
+	}{
+		{
+			name:  "valid",
+			specs: Specifications{"memory": "16GB", "battery_life": "Up to 10 hours", "touchscreen": "Yes", "cores": 8.0},
+		},
+		{
+			name:  "missing required",
+			specs: Specifications{"touchscreen": true},
+			expected: []SpecProblem{
+				{Key: "memory", Message: "es obligatoria"},
+			},
+		},
+		{
+			name:  "wrong types",
+			specs: Specifications{"memory": "2 kg", "touchscreen": "maybe", "cores": "many"},
+			expected: []SpecProblem{
+				{Key: "cores", Message: "debe ser un número"},
+				{Key: "memory", Message: "debe ser una cantidad de data_size con unidad (p. ej. 16GB)"},
+				{Key: "touchscreen", Message: `debe ser un booleano (true/false o "Yes"/"No")`},
+			},
+		},
+		{
+			name:  "unknown key with suggestion",
+			specs: Specifications{"memory": "16GB", "battery life": "10 hours", "colour": "red"},
+			expected: []SpecProblem{
+				{Key: "battery life", Message: `clave no permitida en esta categoría; ¿quiso decir "battery_life"?`},
+				{Key: "colour", Message: "clave no permitida en esta categoría"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schema.Validate(tt.specs))
+		})
+	}
+}
+
+// TestSpecSchema_NotEnforcedWithoutFields: Sin campos declarados se acepta cualquier especificación
+func TestSpecSchema_NotEnforcedWithoutFields(t *testing.T) {
+	schema := ResolveSpecSchema(3, nil)
+
+	assert.False(t, schema.Enforced())
+	assert.Empty(t, schema.Validate(Specifications{"anything": "goes"}))
+}
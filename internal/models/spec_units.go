@@ -0,0 +1,156 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dimension identifica la magnitud física de un valor de especificación normalizado.
+type Dimension string
+
+const (
+	DimensionDataSize  Dimension = "data_size" // almacenamiento y memoria, en GB
+	DimensionWeight    Dimension = "weight"    // peso, en kg
+	DimensionDuration  Dimension = "duration"  // autonomía de batería, en horas
+	DimensionLength    Dimension = "length"    // tamaño de pantalla, en pulgadas
+	DimensionFrequency Dimension = "frequency" // frecuencia, en Hz
+	DimensionNumber    Dimension = "number"    // número sin unidad
+)
+
+// Direction indica si, para una especificación, un valor mayor o menor es mejor.
+type Direction string
+
+const (
+	DirectionHigher Direction = "higher"
+	DirectionLower  Direction = "lower"
+)
+
+// canonicalUnits es la unidad en la que se expresa cada dimensión tras normalizar.
+var canonicalUnits = map[Dimension]string{
+	DimensionDataSize:  "GB",
+	DimensionWeight:    "kg",
+	DimensionDuration:  "h",
+	DimensionLength:    "in",
+	DimensionFrequency: "Hz",
+	DimensionNumber:    "",
+}
+
+// CanonicalUnit devuelve la unidad canónica de la dimensión.
+func (d Dimension) CanonicalUnit() string {
+	return canonicalUnits[d]
+}
+
+// DefaultDirection devuelve la dirección por defecto de la dimensión:
+// solo en el peso un valor menor es mejor.
+func (d Dimension) DefaultDirection() Direction {
+	if d == DimensionWeight {
+		return DirectionLower
+	}
+	return DirectionHigher
+}
+
+// unitFactor indica la dimensión de una unidad y el factor para convertirla a la unidad canónica.
+type unitFactor struct {
+	dimension Dimension
+	factor    float64
+}
+
+// units enumera las unidades reconocidas (en minúsculas).
+var units = map[string]unitFactor{
+	"tb":      {DimensionDataSize, 1024},
+	"gb":      {DimensionDataSize, 1},
+	"mb":      {DimensionDataSize, 1.0 / 1024},
+	"kb":      {DimensionDataSize, 1.0 / (1024 * 1024)},
+	"kg":      {DimensionWeight, 1},
+	"g":       {DimensionWeight, 0.001},
+	"lb":      {DimensionWeight, 0.45359237},
+	"lbs":     {DimensionWeight, 0.45359237},
+	"oz":      {DimensionWeight, 0.028349523125},
+	"hours":   {DimensionDuration, 1},
+	"hour":    {DimensionDuration, 1},
+	"hrs":     {DimensionDuration, 1},
+	"hr":      {DimensionDuration, 1},
+	"minutes": {DimensionDuration, 1.0 / 60},
+	"minute":  {DimensionDuration, 1.0 / 60},
+	"min":     {DimensionDuration, 1.0 / 60},
+	"inches":  {DimensionLength, 1},
+	"inch":    {DimensionLength, 1},
+	"in":      {DimensionLength, 1},
+	`"`:       {DimensionLength, 1},
+	"cm":      {DimensionLength, 1 / 2.54},
+	"mm":      {DimensionLength, 1 / 25.4},
+	"ghz":     {DimensionFrequency, 1e9},
+	"mhz":     {DimensionFrequency, 1e6},
+	"hz":      {DimensionFrequency, 1},
+}
+
+// quantityPattern encuentra un número seguido de una unidad conocida, p. ej. "512GB",
+// "2.15 kg", "16.2-inch" o "Up to 22 hours". El número no puede ir pegado a una letra
+// (evita "i7") y la unidad debe terminar en un límite de palabra (evita "13700H").
+var quantityPattern = regexp.MustCompile(
+	`(?i)(?:^|[^\p{L}\p{N}.])(\d+(?:[.,]\d+)?)\s*-?\s*(tb|gb|mb|kb|kg|lbs|lb|oz|g|hours|hour|hrs|hr|minutes|minute|min|inches|inch|in|ghz|mhz|hz|cm|mm|")(?:[^\p{L}\p{N}]|$)`,
+)
+
+// plainNumberPattern reconoce un texto que es únicamente un número.
+var plainNumberPattern = regexp.MustCompile(`^\s*-?\d+(?:[.,]\d+)?\s*$`)
+
+// NormalizedValue es el valor numérico de una especificación expresado en la unidad
+// canónica de su dimensión.
+type NormalizedValue struct {
+	Value     float64   `json:"value"`
+	Unit      string    `json:"unit"`
+	Dimension Dimension `json:"dimension"`
+}
+
+// NormalizeSpecValue convierte un valor de especificación a su valor numérico canónico.
+// Los números JSON y los textos que son solo un número se tratan como DimensionNumber.
+// En los textos con unidad se usa la primera cantidad reconocida: "1TB SSD" -> 1024 GB,
+// "Up to 22 hours" -> 22 h. Devuelve false si el valor no es cuantificable.
+func NormalizeSpecValue(value interface{}) (NormalizedValue, bool) {
+	switch v := value.(type) {
+	case float64:
+		return NormalizedValue{Value: v, Dimension: DimensionNumber}, true
+	case int:
+		return NormalizedValue{Value: float64(v), Dimension: DimensionNumber}, true
+	case int64:
+		return NormalizedValue{Value: float64(v), Dimension: DimensionNumber}, true
+	case string:
+		return normalizeText(v)
+	default:
+		return NormalizedValue{}, false
+	}
+}
+
+// normalizeText extrae la primera cantidad con unidad de un texto.
+func normalizeText(text string) (NormalizedValue, bool) {
+	if plainNumberPattern.MatchString(text) {
+		number, err := parseDecimal(strings.TrimSpace(text))
+		if err != nil {
+			return NormalizedValue{}, false
+		}
+		return NormalizedValue{Value: number, Dimension: DimensionNumber}, true
+	}
+
+	match := quantityPattern.FindStringSubmatch(text)
+	if match == nil {
+		return NormalizedValue{}, false
+	}
+
+	number, err := parseDecimal(match[1])
+	if err != nil {
+		return NormalizedValue{}, false
+	}
+
+	unit := units[strings.ToLower(match[2])]
+	return NormalizedValue{
+		Value:     number * unit.factor,
+		Unit:      unit.dimension.CanonicalUnit(),
+		Dimension: unit.dimension,
+	}, true
+}
+
+// parseDecimal interpreta un número aceptando coma o punto como separador decimal.
+func parseDecimal(raw string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(raw, ",", ".", 1), 64)
+}
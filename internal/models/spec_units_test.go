@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeSpecValue: Valores de especificación del catálogo y su valor canónico
+func TestNormalizeSpecValue(t *testing.T) {
+	tests := []struct {
+		input     interface{}
+		value     float64
+		dimension Dimension
+	}{
+		{"16GB", 16, DimensionDataSize},
+		{"512GB SSD", 512, DimensionDataSize},
+		{"1TB SSD", 1024, DimensionDataSize},
+		{"2.15 kg", 2.15, DimensionWeight},
+		{"1360 g", 1.36, DimensionWeight},
+		{"Up to 22 hours", 22, DimensionDuration},
+		{"90 min", 1.5, DimensionDuration},
+		{"16.2-inch Liquid Retina XDR", 16.2, DimensionLength},
+		{"14-inch QHD 165Hz", 14, DimensionLength},
+		{`13.3" FHD`, 13.3, DimensionLength},
+		{"3.2 GHz", 3.2e9, DimensionFrequency},
+		{"165Hz", 165, DimensionFrequency},
+		{"2,5 kg", 2.5, DimensionWeight},
+		{float64(8), 8, DimensionNumber},
+		{"12", 12, DimensionNumber},
+	}
+
+	for _, tt := range tests {
+		normalized, ok := NormalizeSpecValue(tt.input)
+		if assert.True(t, ok, "%v debería normalizarse", tt.input) {
+			assert.InDelta(t, tt.value, normalized.Value, 1e-9, "%v", tt.input)
+			assert.Equal(t, tt.dimension, normalized.Dimension, "%v", tt.input)
+			assert.Equal(t, tt.dimension.CanonicalUnit(), normalized.Unit, "%v", tt.input)
+		}
+	}
+}
+
+// TestNormalizeSpecValue_NotQuantifiable: Textos sin una cantidad con unidad reconocida
+func TestNormalizeSpecValue_NotQuantifiable(t *testing.T) {
+	inputs := []interface{}{
+		"Apple M2 Pro",
+		"Intel Core i7-13700H",
+		"AMD Ryzen 9 7940HS",
+		"19-core GPU",
+		"NVIDIA RTX 4050",
+		"Yes",
+		true,
+		nil,
+	}
+
+	for _, input := range inputs {
+		_, ok := NormalizeSpecValue(input)
+		assert.False(t, ok, "%v no debería normalizarse", input)
+	}
+}
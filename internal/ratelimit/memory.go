@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// MemoryStore guarda los límites de tasa en el proceso con un rate.Limiter por clave.
+// Cada réplica de la API tiene sus propios límites.
+type MemoryStore struct {
+	// clients almacena los limitadores de tasa por clave
+	clients         map[string]*clientLimiter
+	mu              sync.Mutex
+	cleanupInterval time.Duration
+	stopCleanup     context.CancelFunc
+
+	// now devuelve el momento actual; los tests lo reemplazan.
+	now func() time.Time
+}
+
+// clientLimiter envuelve un rate.Limiter junto con la ventana de su política y la
+// hora del último acceso
+type clientLimiter struct {
+	limiter    *rate.Limiter
+	window     time.Duration
+	lastAccess time.Time
+}
+
+// minEvictionAge es el tiempo mínimo sin usarse tras el que se elimina un limitador.
+const minEvictionAge = 10 * time.Minute
+
+// NewMemoryStore crea un MemoryStore y arranca la limpieza periódica de los
+// limitadores sin usar, que se detiene con Close.
+func NewMemoryStore() *MemoryStore {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &MemoryStore{
+		clients:         make(map[string]*clientLimiter),
+		cleanupInterval: 5 * time.Minute,
+		stopCleanup:     cancel,
+		now:             time.Now,
+	}
+
+	go s.cleanup(ctx)
+
+	return s
+}
+
+// Allow reserva una petición del limitador de key; si no hay ninguna disponible ya,
+// cancela la reserva para que la petición rechazada no consuma el límite.
+func (s *MemoryStore) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	now := s.now()
+	limiter := s.getLimiter(key, policy, now)
+
+	reservation := limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+	}
+
+	// Las peticiones se recuperan una cada policy.interval(): el límite vuelve a estar
+	// completo cuando se recuperan las que faltan.
+	tokens := limiter.TokensAt(now)
+	return Result{
+		Allowed:    delay == 0,
+		Remaining:  max(int(math.Floor(tokens)), 0),
+		RetryAfter: delay,
+		ResetAfter: time.Duration((float64(policy.Limit) - tokens) * float64(policy.interval())),
+	}, nil
+}
+
+// getLimiter obtiene o crea el limitador de tasa de key con la política indicada.
+// Esta función adquiere el bloqueo porque puede crear una nueva entrada y siempre
+// actualiza la hora de último acceso.
+func (s *MemoryStore) getLimiter(key string, policy Policy, now time.Time) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cl, exists := s.clients[key]; exists {
+		cl.lastAccess = now
+		return cl.limiter
+	}
+
+	ratePerSecond := float64(policy.Limit) / policy.Window.Seconds()
+	limiter := rate.NewLimiter(rate.Limit(ratePerSecond), policy.Limit)
+
+	s.clients[key] = &clientLimiter{
+		limiter:    limiter,
+		window:     policy.Window,
+		lastAccess: now,
+	}
+
+	return limiter
+}
+
+// cleanup elimina periódicamente los limitadores antiguos que no se han usado recientemente.
+// Esto previene fugas de memoria por acumular limitadores de clientes inactivos.
+func (s *MemoryStore) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.performCleanup()
+		}
+	}
+}
+
+// performCleanup elimina los limitadores que no han sido accedidos recientemente. Un
+// limitador sin usar durante la ventana de su política vuelve a estar lleno, así que
+// eliminarlo después no cambia el límite del cliente.
+func (s *MemoryStore) performCleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, cl := range s.clients {
+		if now.Sub(cl.lastAccess) > max(cl.window, minEvictionAge) {
+			delete(s.clients, key)
+		}
+	}
+}
+
+// Close detiene la gorutina de limpieza y debe llamarse durante el apagado (shutdown).
+func (s *MemoryStore) Close() error {
+	s.stopCleanup()
+	return nil
+}
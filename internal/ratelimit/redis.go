@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix antecede a las claves de los límites en Redis.
+const keyPrefix = "ratelimit:"
+
+// gcraScript aplica el algoritmo GCRA de forma atómica. El estado de cada clave es
+// su TAT (theoretical arrival time): el momento, en microsegundos según el reloj de
+// Redis, en que el límite vuelve a estar completo. Una petición se permite si, al
+// sumarle el intervalo de una petición, el TAT no supera la ventana a partir de ahora.
+// Es equivalente al token bucket de MemoryStore con una ráfaga de Limit peticiones.
+//
+// KEYS[1] es la clave; ARGV[1], el límite y ARGV[2], la ventana en microsegundos.
+// Devuelve {permitida, restantes, reintento en µs, reinicio en µs}.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local interval = window / limit
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local tat = math.max(tonumber(redis.call('GET', KEYS[1])) or now, now)
+local new_tat = tat + interval
+
+if new_tat - window > now then
+	return {0, 0, math.ceil(new_tat - window - now), math.ceil(tat - now)}
+end
+
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((window - (new_tat - now)) / interval), 0, math.ceil(new_tat - now)}
+`)
+
+// RedisStore guarda los límites de tasa en Redis, o en cualquier servidor compatible
+// con su protocolo y con scripts Lua, para compartirlos entre réplicas. Usa el reloj
+// de Redis, así que la hora de cada réplica no influye en los límites.
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore crea un RedisStore con un cliente ya configurado.
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// DialRedis crea un RedisStore conectado a la URL indicada
+// (redis://[usuario:contraseña@]host:puerto[/db], o rediss:// con TLS). Cada consulta
+// respeta el plazo de su contexto, para que un servidor lento no retenga las peticiones.
+func DialRedis(url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	options.ContextTimeoutEnabled = true
+	return NewRedisStore(redis.NewClient(options)), nil
+}
+
+// Allow ejecuta gcraScript sobre la clave.
+func (s *RedisStore) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	values, err := gcraScript.Run(ctx, s.client, []string{keyPrefix + key},
+		policy.Limit, policy.Window.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+// Close cierra la conexión con Redis.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
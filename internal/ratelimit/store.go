@@ -0,0 +1,49 @@
+// Package ratelimit guarda el estado de los límites de tasa. MemoryStore lo guarda
+// en el proceso; RedisStore, en un servidor Redis compartido por todas las réplicas
+// de la API, para que cada cliente tenga el mismo límite sin importar cuántas haya.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Policy permite Limit peticiones por Window. Las peticiones disponibles se recuperan
+// de forma continua, una cada Window/Limit, así que un cliente puede agotar Limit de
+// golpe y después hacer una petición cada Window/Limit.
+type Policy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// interval es el tiempo en que se recupera una petición.
+func (p Policy) interval() time.Duration {
+	return p.Window / time.Duration(p.Limit)
+}
+
+// Result es la decisión sobre una petición y el estado del límite tras ella.
+type Result struct {
+	// Allowed indica si la petición puede seguir. Una petición rechazada no consume
+	// el límite.
+	Allowed bool
+
+	// Remaining son las peticiones que quedan disponibles.
+	Remaining int
+
+	// RetryAfter es el tiempo hasta que haya una petición disponible; cero si la
+	// petición se permitió.
+	RetryAfter time.Duration
+
+	// ResetAfter es el tiempo hasta que el límite vuelve a estar completo.
+	ResetAfter time.Duration
+}
+
+// Store guarda el estado de los límites de tasa de cada clave.
+type Store interface {
+	// Allow consume una petición del límite de key con la política indicada, si hay
+	// alguna disponible. Una misma clave debe usarse siempre con la misma política.
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+
+	// Close libera los recursos del store.
+	Close() error
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// newMiniredisStore crea un RedisStore sobre un servidor Redis en proceso cuyo reloj
+// controla el test.
+func newMiniredisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	server.SetTime(testNow)
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { store.Close() })
+	return store, server
+}
+
+// assertResult compara dos resultados con una tolerancia de un milisegundo en los
+// tiempos, que MemoryStore calcula con números de punto flotante.
+func assertResult(t *testing.T, expected, actual Result, step string) {
+	t.Helper()
+	assert.Equal(t, expected.Allowed, actual.Allowed, step)
+	assert.Equal(t, expected.Remaining, actual.Remaining, step)
+	assert.InDelta(t, expected.RetryAfter.Seconds(), actual.RetryAfter.Seconds(), 1e-3, step)
+	assert.InDelta(t, expected.ResetAfter.Seconds(), actual.ResetAfter.Seconds(), 1e-3, step)
+}
+
+// TestStores_Allow: MemoryStore y RedisStore aplican el mismo token bucket y una
+// petición rechazada no consume el límite
+func TestStores_Allow(t *testing.T) {
+	stores := map[string]func(t *testing.T) (Store, func(time.Time)){
+		"memory": func(t *testing.T) (Store, func(time.Time)) {
+			store := NewMemoryStore()
+			t.Cleanup(func() { store.Close() })
+			now := testNow
+			store.now = func() time.Time { return now }
+			return store, func(at time.Time) { now = at }
+		},
+		"redis": func(t *testing.T) (Store, func(time.Time)) {
+			store, server := newMiniredisStore(t)
+			return store, server.SetTime
+		},
+	}
+
+	policy := Policy{Limit: 2, Window: time.Minute}
+	steps := []struct {
+		name     string
+		at       time.Duration
+		expected Result
+	}{
+		{"first request", 0, Result{Allowed: true, Remaining: 1, ResetAfter: 30 * time.Second}},
+		{"burst exhausted", 10 * time.Second, Result{Allowed: true, Remaining: 0, ResetAfter: 50 * time.Second}},
+		{"rejected", 10 * time.Second, Result{Allowed: false, Remaining: 0, RetryAfter: 20 * time.Second, ResetAfter: 50 * time.Second}},
+		{"one request recovered", 30 * time.Second, Result{Allowed: true, Remaining: 0, ResetAfter: time.Minute}},
+		{"fully recovered", 150 * time.Second, Result{Allowed: true, Remaining: 1, ResetAfter: 30 * time.Second}},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store, setTime := newStore(t)
+			for _, step := range steps {
+				setTime(testNow.Add(step.at))
+				result, err := store.Allow(context.Background(), "compare|standard|apikey:ci", policy)
+				require.NoError(t, err, step.name)
+				assertResult(t, step.expected, result, step.name)
+			}
+
+			// Otra clave tiene su propio límite.
+			result, err := store.Allow(context.Background(), "compare|standard|apikey:other", policy)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Remaining)
+		})
+	}
+}
+
+// TestRedisStore_SharedAcrossReplicas: Varias réplicas con su propio cliente comparten
+// el límite. Usa el servidor de REDIS_URL si está definida y, si no, uno en proceso
+func TestRedisStore_SharedAcrossReplicas(t *testing.T) {
+	options := &redis.Options{}
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		parsed, err := redis.ParseURL(url)
+		require.NoError(t, err)
+		options = parsed
+	} else {
+		options.Addr = miniredis.RunT(t).Addr()
+	}
+
+	replicas := make([]*RedisStore, 3)
+	for i := range replicas {
+		replicas[i] = NewRedisStore(redis.NewClient(options))
+		defer replicas[i].Close()
+	}
+
+	key := "test|" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	policy := Policy{Limit: 4, Window: time.Hour}
+	allowed := 0
+	for i := 0; i < 6; i++ {
+		result, err := replicas[i%len(replicas)].Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+		if result.Allowed {
+			allowed++
+		}
+	}
+	assert.Equal(t, policy.Limit, allowed)
+}
+
+// TestRedisStore_Unreachable: Si el servidor no responde, Allow devuelve un error
+func TestRedisStore_Unreachable(t *testing.T) {
+	store, server := newMiniredisStore(t)
+	server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := store.Allow(ctx, "global|192.0.2.1", Policy{Limit: 10, Window: time.Minute})
+	assert.ErrorContains(t, err, "failed to run rate limit script")
+
+	_, err = DialRedis("http://localhost:6379")
+	assert.ErrorContains(t, err, "invalid redis URL")
+}
+
+// TestMemoryStore_Cleanup: Los limitadores sin usar durante su ventana, y al menos
+// diez minutos, se eliminan
+func TestMemoryStore_Cleanup(t *testing.T) {
+	store := NewMemoryStore()
+	defer store.Close()
+	now := testNow
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	store.Allow(ctx, "short", Policy{Limit: 1, Window: time.Minute})
+	store.Allow(ctx, "long", Policy{Limit: 1, Window: time.Hour})
+
+	now = now.Add(11 * time.Minute)
+	store.performCleanup()
+	assert.NotContains(t, store.clients, "short")
+	assert.Contains(t, store.clients, "long")
+}
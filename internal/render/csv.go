@@ -0,0 +1,157 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"project/internal/models"
+	"sort"
+	"strconv"
+)
+
+// SpecColumnPrefix es el prefijo de las columnas de especificación en los CSV de items.
+const SpecColumnPrefix = "spec."
+
+// ErrUnsupportedValue indica que el encoder no sabe representar el tipo de respuesta.
+var ErrUnsupportedValue = stdErrors.New("render: unsupported response type")
+
+// CSV codifica items, listas de items y comparaciones como tablas CSV.
+//
+//   - Un item, una lista o una página de items: una fila por item con las columnas
+//     de ItemColumns. La paginación no se incluye.
+//   - Una comparación: una matriz con una columna por item y una fila por campo y
+//     por especificación, en la que cada celda es el valor del item para ese campo.
+//     La primera fila contiene los IDs de los items.
+type CSV struct{}
+
+// ContentType implementa Encoder.
+func (CSV) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+// Encode implementa Encoder. Devuelve ErrUnsupportedValue para otros tipos de respuesta.
+func (CSV) Encode(w io.Writer, v interface{}) error {
+	var records [][]string
+
+	switch value := v.(type) {
+	case models.Item:
+		records = itemTable([]models.Item{value})
+	case *models.Item:
+		records = itemTable([]models.Item{*value})
+	case []models.Item:
+		records = itemTable(value)
+	case *models.ItemPage:
+		records = itemTable(value.Data)
+	case *models.CompareResponse:
+		records = comparisonMatrix(value)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedValue, v)
+	}
+
+	writer := csv.NewWriter(w)
+	return writer.WriteAll(records)
+}
+
+// itemTable devuelve el encabezado y una fila por item.
+func itemTable(items []models.Item) [][]string {
+	specKeys := SpecKeys(items)
+
+	records := make([][]string, 0, len(items)+1)
+	records = append(records, ItemColumns(specKeys))
+	for _, item := range items {
+		records = append(records, ItemRecord(item, specKeys))
+	}
+	return records
+}
+
+// comparisonMatrix devuelve la matriz de la comparación: una fila por columna de
+// ItemColumns (la primera, con los IDs, hace de encabezado) y una columna por item.
+func comparisonMatrix(response *models.CompareResponse) [][]string {
+	specKeys := SpecKeys(response.Items)
+	columns := ItemColumns(specKeys)
+
+	rows := make([][]string, len(columns))
+	for i, column := range columns {
+		rows[i] = []string{column}
+	}
+	for _, item := range response.Items {
+		for i, value := range ItemRecord(item, specKeys) {
+			rows[i] = append(rows[i], value)
+		}
+	}
+	return rows
+}
+
+// SpecKeys devuelve las claves de especificación de los items, sin repetir y
+// ordenadas alfabéticamente.
+func SpecKeys(items []models.Item) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, item := range items {
+		for key := range item.Specifications {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ItemColumns devuelve las columnas de un CSV de items: los campos del item y una
+// columna spec.<clave> por cada clave de especificación. Son las mismas columnas
+// que acepta la importación.
+func ItemColumns(specKeys []string) []string {
+	columns := []string{"id", "name", "image_url", "description", "price", "currency", "rating", "category_id"}
+	for _, key := range specKeys {
+		columns = append(columns, SpecColumnPrefix+key)
+	}
+	return columns
+}
+
+// ItemRecord devuelve la fila de un item con las columnas de ItemColumns. Las
+// especificaciones que el item no tiene y la categoría ausente quedan vacías.
+func ItemRecord(item models.Item, specKeys []string) []string {
+	categoryID := ""
+	if item.CategoryID != nil {
+		categoryID = strconv.FormatInt(*item.CategoryID, 10)
+	}
+
+	record := []string{
+		strconv.FormatInt(item.ID, 10),
+		item.Name,
+		item.ImageURL,
+		item.Description,
+		strconv.FormatFloat(item.Price, 'f', -1, 64),
+		item.Currency,
+		strconv.FormatFloat(item.Rating, 'f', -1, 64),
+		categoryID,
+	}
+	for _, key := range specKeys {
+		record = append(record, specCellValue(item.Specifications[key]))
+	}
+	return record
+}
+
+// specCellValue convierte el valor de una especificación en el texto de una celda.
+func specCellValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,141 @@
+package render
+
+import (
+	"bytes"
+	"project/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testItems devuelve dos items con especificaciones distintas.
+func testItems() []models.Item {
+	categoryID := int64(2)
+	return []models.Item{
+		{
+			ID: 1, Name: "Laptop <A>", ImageURL: "https://example.com/a.jpg", Price: 999.5, Currency: "USD", Rating: 4.5, CategoryID: &categoryID,
+			Specifications: models.Specifications{"memory": "16GB", "battery life": "10 h"},
+		},
+		{
+			ID: 2, Name: "Laptop B", ImageURL: "https://example.com/b.jpg", Price: 10, Currency: "EUR", Rating: 4,
+			Specifications: models.Specifications{"memory": "32GB", "touchscreen": true},
+		},
+	}
+}
+
+// encode codifica v con el encoder indicado.
+func encode(t *testing.T, encoder Encoder, v interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, encoder.Encode(&buf, v))
+	return buf.String()
+}
+
+// TestXML_Item: Los campos siguen el orden de JSON, los nulos se omiten y las
+// claves que no son nombres XML usan <entry key>
+func TestXML_Item(t *testing.T) {
+	item := testItems()[1]
+	item.Specifications = models.Specifications{"memory": "32GB", "battery life": "10 h"}
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<item><id>2</id><name>Laptop B</name><image_url>https://example.com/b.jpg</image_url><description></description>`+
+		`<price>10</price><currency>EUR</currency><rating>4</rating><specifications><entry key="battery life">10 h</entry><memory>32GB</memory></specifications>`+
+		`</item>`+"\n",
+		encode(t, XML{}, &item))
+}
+
+// TestXML_Page: Los arrays se codifican como elementos <item> y el texto se escapa
+func TestXML_Page(t *testing.T) {
+	page := &models.ItemPage{Data: testItems()[:1], Pagination: models.Pagination{Total: 1, Limit: 20}}
+
+	out := encode(t, XML{}, page)
+
+	assert.Contains(t, out, "<item_page><data><item><id>1</id><name>Laptop &lt;A&gt;</name>")
+	assert.Contains(t, out, "<category_id>2</category_id>")
+	assert.Contains(t, out, "<pagination><total>1</total><limit>20</limit><offset>0</offset></pagination></item_page>")
+}
+
+// TestIsXMLName: Solo los nombres válidos se usan como elementos
+func TestIsXMLName(t *testing.T) {
+	for _, name := range []string{"memory", "battery_life", "spec-1", "_x", "año"} {
+		assert.True(t, isXMLName(name), name)
+	}
+	for _, name := range []string{"", "1", "battery life", "-a", ".a", "xmlns", "XMLfoo", "a:b"} {
+		assert.False(t, isXMLName(name), name)
+	}
+}
+
+// TestMessagePack: Cada tipo usa el formato más corto y los objetos conservan el orden de los campos
+func TestMessagePack(t *testing.T) {
+	type sample struct {
+		Small    int      `json:"s"`
+		Negative int      `json:"n"`
+		Byte     int      `json:"b"`
+		Large    int64    `json:"l"`
+		Float    float64  `json:"f"`
+		Flag     bool     `json:"t"`
+		Nothing  *int     `json:"z"`
+		List     []string `json:"a"`
+	}
+
+	out := encode(t, MessagePack{}, sample{
+		Small: 5, Negative: -200, Byte: 200, Large: 1 << 40, Float: 1.5, Flag: true, List: []string{"hi"},
+	})
+
+	assert.Equal(t, []byte{
+		0x88,
+		0xa1, 's', 0x05,
+		0xa1, 'n', 0xd1, 0xff, 0x38,
+		0xa1, 'b', 0xcc, 0xc8,
+		0xa1, 'l', 0xcf, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xa1, 'f', 0xcb, 0x3f, 0xf8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xa1, 't', 0xc3,
+		0xa1, 'z', 0xc0,
+		0xa1, 'a', 0x91, 0xa2, 'h', 'i',
+	}, []byte(out))
+}
+
+// TestMessagePack_LongValues: Los textos y colecciones largos usan los formatos de 8 y 16 bits
+func TestMessagePack_LongValues(t *testing.T) {
+	long := string(bytes.Repeat([]byte("a"), 40))
+	out := []byte(encode(t, MessagePack{}, long))
+	assert.Equal(t, []byte{0xd9, 40}, out[:2])
+	assert.Len(t, out, 42)
+
+	out = []byte(encode(t, MessagePack{}, make([]int, 20)))
+	assert.Equal(t, []byte{0xdc, 0x00, 20}, out[:3])
+	assert.Len(t, out, 23)
+}
+
+// TestCSV_Items: Una fila por item con las especificaciones de todos los items como columnas
+func TestCSV_Items(t *testing.T) {
+	assert.Equal(t,
+		"id,name,image_url,description,price,currency,rating,category_id,spec.battery life,spec.memory,spec.touchscreen\n"+
+			"1,Laptop <A>,https://example.com/a.jpg,,999.5,USD,4.5,2,10 h,16GB,\n"+
+			"2,Laptop B,https://example.com/b.jpg,,10,EUR,4,,,32GB,true\n",
+		encode(t, CSV{}, &models.ItemPage{Data: testItems()}))
+}
+
+// TestCSV_ComparisonMatrix: La comparación se codifica con una columna por item
+func TestCSV_ComparisonMatrix(t *testing.T) {
+	assert.Equal(t,
+		"id,1,2\n"+
+			"name,Laptop <A>,Laptop B\n"+
+			"image_url,https://example.com/a.jpg,https://example.com/b.jpg\n"+
+			"description,,\n"+
+			"price,999.5,10\n"+
+			"currency,USD,EUR\n"+
+			"rating,4.5,4\n"+
+			"category_id,2,\n"+
+			"spec.battery life,10 h,\n"+
+			"spec.memory,16GB,32GB\n"+
+			"spec.touchscreen,,true\n",
+		encode(t, CSV{}, &models.CompareResponse{Items: testItems()}))
+}
+
+// TestCSV_Unsupported: Los tipos sin representación tabular devuelven ErrUnsupportedValue
+func TestCSV_Unsupported(t *testing.T) {
+	err := CSV{}.Encode(&bytes.Buffer{}, models.ScoreResponse{})
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+}
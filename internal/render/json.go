@@ -0,0 +1,19 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSON codifica las respuestas en JSON. Es el formato por defecto.
+type JSON struct{}
+
+// ContentType implementa Encoder.
+func (JSON) ContentType() string {
+	return "application/json"
+}
+
+// Encode implementa Encoder.
+func (JSON) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,162 @@
+package render
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MessagePack codifica las respuestas en MessagePack (https://msgpack.org), con
+// los mismos campos que la respuesta JSON. Los objetos se codifican como mapas con
+// claves de texto y los números como enteros si no tienen parte decimal y como
+// float64 en otro caso. Siempre se usa la representación más corta posible.
+type MessagePack struct{}
+
+// ContentType implementa Encoder.
+func (MessagePack) ContentType() string {
+	return "application/msgpack"
+}
+
+// Encode implementa Encoder.
+func (MessagePack) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(w)
+	if err := encodeMsgpackNode(buf, tree); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// encodeMsgpackNode escribe n en formato MessagePack.
+func encodeMsgpackNode(w *bufio.Writer, n node) error {
+	switch n.kind {
+	case nodeNull:
+		return w.WriteByte(0xc0)
+	case nodeBool:
+		if n.bool {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case nodeNumber:
+		if i, err := strconv.ParseInt(n.text, 10, 64); err == nil {
+			return writeMsgpackInt(w, i)
+		}
+		f, err := strconv.ParseFloat(n.text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q: %w", n.text, err)
+		}
+		w.WriteByte(0xcb)
+		return writeBigEndian(w, math.Float64bits(f), 8)
+	case nodeString:
+		return writeMsgpackString(w, n.text)
+	case nodeArray:
+		if err := writeMsgpackLength(w, len(n.items), 0x90, 0xdc, 0xdd); err != nil {
+			return err
+		}
+		for _, item := range n.items {
+			if err := encodeMsgpackNode(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nodeObject:
+		if err := writeMsgpackLength(w, len(n.fields), 0x80, 0xde, 0xdf); err != nil {
+			return err
+		}
+		for _, f := range n.fields {
+			if err := writeMsgpackString(w, f.key); err != nil {
+				return err
+			}
+			if err := encodeMsgpackNode(w, f.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown node kind %d", n.kind)
+	}
+}
+
+// writeMsgpackInt escribe un entero con el formato más corto: fixint, uint8-64
+// para los positivos e int8-64 para los negativos.
+func writeMsgpackInt(w *bufio.Writer, i int64) error {
+	switch {
+	case i >= 0 && i < 128:
+		return w.WriteByte(byte(i))
+	case i >= -32 && i < 0:
+		return w.WriteByte(byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		w.WriteByte(0xcc)
+		return writeBigEndian(w, uint64(i), 1)
+	case i >= 0 && i <= math.MaxUint16:
+		w.WriteByte(0xcd)
+		return writeBigEndian(w, uint64(i), 2)
+	case i >= 0 && i <= math.MaxUint32:
+		w.WriteByte(0xce)
+		return writeBigEndian(w, uint64(i), 4)
+	case i >= 0:
+		w.WriteByte(0xcf)
+		return writeBigEndian(w, uint64(i), 8)
+	case i >= math.MinInt8:
+		w.WriteByte(0xd0)
+		return writeBigEndian(w, uint64(i), 1)
+	case i >= math.MinInt16:
+		w.WriteByte(0xd1)
+		return writeBigEndian(w, uint64(i), 2)
+	case i >= math.MinInt32:
+		w.WriteByte(0xd2)
+		return writeBigEndian(w, uint64(i), 4)
+	default:
+		w.WriteByte(0xd3)
+		return writeBigEndian(w, uint64(i), 8)
+	}
+}
+
+// writeMsgpackString escribe un texto UTF-8 con el formato fixstr o str8-32.
+func writeMsgpackString(w *bufio.Writer, s string) error {
+	switch n := len(s); {
+	case n < 32:
+		w.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		w.WriteByte(0xd9)
+		writeBigEndian(w, uint64(n), 1)
+	case n <= math.MaxUint16:
+		w.WriteByte(0xda)
+		writeBigEndian(w, uint64(n), 2)
+	default:
+		w.WriteByte(0xdb)
+		writeBigEndian(w, uint64(n), 4)
+	}
+	_, err := w.WriteString(s)
+	return err
+}
+
+// writeMsgpackLength escribe la cabecera de un array o un mapa: el formato fijo
+// (hasta 15 elementos) o el de 16 o 32 bits.
+func writeMsgpackLength(w *bufio.Writer, n int, fixed, prefix16, prefix32 byte) error {
+	switch {
+	case n < 16:
+		return w.WriteByte(fixed | byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(prefix16)
+		return writeBigEndian(w, uint64(n), 2)
+	default:
+		w.WriteByte(prefix32)
+		return writeBigEndian(w, uint64(n), 4)
+	}
+}
+
+// writeBigEndian escribe los size bytes menos significativos de v en orden big-endian.
+func writeBigEndian(w *bufio.Writer, v uint64, size int) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[8-size:])
+	return err
+}
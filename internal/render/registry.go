@@ -0,0 +1,158 @@
+// Package render codifica las respuestas de la API en el formato que pide el
+// cliente (JSON, XML, CSV o MessagePack) según la cabecera Accept.
+package render
+
+import (
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Encoder codifica un valor de respuesta en un formato concreto.
+type Encoder interface {
+	// ContentType es el valor de la cabecera Content-Type de la respuesta.
+	ContentType() string
+
+	// Encode escribe v en w. Los encoders que solo admiten algunos tipos de
+	// respuesta (como CSV) devuelven ErrUnsupportedValue para los demás.
+	Encode(w io.Writer, v interface{}) error
+}
+
+// registration asocia un tipo de medio con el encoder que lo produce.
+type registration struct {
+	mediaType string
+	encoder   Encoder
+}
+
+// Registry elige el encoder de una respuesta a partir de la cabecera Accept.
+// El orden de registro es la preferencia del servidor: ante un empate en la
+// calidad (q) pedida por el cliente gana el registrado primero, y sin cabecera
+// Accept se usa el primero.
+type Registry struct {
+	registrations []registration
+}
+
+// NewRegistry crea un registro vacío.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default devuelve el registro con los formatos de la API: JSON (preferido),
+// XML, CSV y MessagePack.
+func Default() *Registry {
+	registry := NewRegistry()
+	registry.Register(JSON{}, "application/json")
+	registry.Register(XML{}, "application/xml", "text/xml")
+	registry.Register(CSV{}, "text/csv")
+	registry.Register(MessagePack{}, "application/msgpack", "application/x-msgpack", "application/vnd.msgpack")
+	return registry
+}
+
+// Register asocia el encoder con uno o más tipos de medio (por ejemplo "text/xml").
+func (r *Registry) Register(encoder Encoder, mediaTypes ...string) {
+	for _, mediaType := range mediaTypes {
+		r.registrations = append(r.registrations, registration{
+			mediaType: strings.ToLower(mediaType),
+			encoder:   encoder,
+		})
+	}
+}
+
+// MediaTypes devuelve los tipos de medio registrados, en orden de preferencia.
+func (r *Registry) MediaTypes() []string {
+	mediaTypes := make([]string, len(r.registrations))
+	for i, reg := range r.registrations {
+		mediaTypes[i] = reg.mediaType
+	}
+	return mediaTypes
+}
+
+// Negotiate elige el encoder para la cabecera Accept indicada, siguiendo la RFC 9110:
+// cada tipo registrado toma la calidad del rango más específico que lo incluye
+// (tipo/subtipo, tipo/* o */*) y se elige el de mayor calidad. Devuelve false si
+// ningún tipo registrado es aceptable (todos tienen q=0 o no coinciden).
+func (r *Registry) Negotiate(accept string) (Encoder, bool) {
+	if len(r.registrations) == 0 {
+		return nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return r.registrations[0].encoder, true
+	}
+
+	ranges := parseAccept(accept)
+
+	var best Encoder
+	bestQuality := 0.0
+	for _, reg := range r.registrations {
+		if quality := matchQuality(ranges, reg.mediaType); quality > bestQuality {
+			best, bestQuality = reg.encoder, quality
+		}
+	}
+
+	return best, best != nil
+}
+
+// mediaRange es un elemento de la cabecera Accept, como "text/*;q=0.5".
+type mediaRange struct {
+	typ, subtype string
+	quality      float64
+}
+
+// parseAccept interpreta la cabecera Accept. Los rangos mal formados se ignoran
+// y una calidad inválida se trata como 0.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+
+		quality := 1.0
+		if raw, ok := params["q"]; ok {
+			q, err := strconv.ParseFloat(raw, 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			quality = q
+		}
+
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, quality: quality})
+	}
+
+	return ranges
+}
+
+// matchQuality devuelve la calidad con la que el cliente acepta el tipo de medio:
+// la del rango más específico que lo incluye, o 0 si ninguno lo incluye.
+func matchQuality(ranges []mediaRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+
+	quality, specificity := 0.0, -1
+	for _, rng := range ranges {
+		var s int
+		switch {
+		case rng.typ == typ && rng.subtype == subtype:
+			s = 2
+		case rng.typ == typ && rng.subtype == "*":
+			s = 1
+		case rng.typ == "*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s > specificity {
+			quality, specificity = rng.quality, s
+		}
+	}
+
+	return quality
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNegotiate: Se elige el formato de mayor calidad; ante un empate, el preferido por el servidor
+func TestNegotiate(t *testing.T) {
+	registry := Default()
+
+	tests := []struct {
+		name     string
+		accept   string
+		expected Encoder
+	}{
+		{"sin cabecera", "", JSON{}},
+		{"cualquier formato", "*/*", JSON{}},
+		{"exacto", "application/xml", XML{}},
+		{"alias", "text/xml", XML{}},
+		{"mayúsculas y parámetros", "Text/CSV; charset=utf-8", CSV{}},
+		{"calidad", "application/json;q=0.5, application/msgpack", MessagePack{}},
+		{"empate", "text/csv, application/xml", XML{}},
+		{"comodín de tipo", "text/*", XML{}},
+		{"el rango más específico manda", "application/*;q=0.9, application/json;q=0.1", XML{}},
+		{"q=0 excluye", "application/json;q=0, */*;q=0.1", XML{}},
+		{"calidad inválida", "application/xml;q=abc, text/csv;q=0.2", CSV{}},
+		{"navegador", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", XML{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, ok := registry.Negotiate(tt.accept)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, encoder)
+		})
+	}
+}
+
+// TestNegotiate_NotAcceptable: Sin coincidencias o con todo excluido no hay encoder
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	registry := Default()
+
+	for _, accept := range []string{"text/html", "application/pdf, image/*", "*/*;q=0", "not a media type"} {
+		_, ok := registry.Negotiate(accept)
+		assert.False(t, ok, accept)
+	}
+
+	_, ok := NewRegistry().Negotiate("")
+	assert.False(t, ok)
+}
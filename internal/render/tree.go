@@ -0,0 +1,99 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// nodeKind es el tipo de un valor JSON.
+type nodeKind int
+
+const (
+	nodeNull nodeKind = iota
+	nodeBool
+	nodeNumber
+	nodeString
+	nodeArray
+	nodeObject
+)
+
+// node es un valor JSON genérico que conserva el orden de las claves de los objetos.
+// Los encoders XML y MessagePack codifican este árbol en lugar de recorrer los
+// modelos con reflexión, de modo que respetan los tags json (nombres, omitempty) y
+// producen los mismos campos, en el mismo orden, que la respuesta JSON.
+type node struct {
+	kind   nodeKind
+	text   string // nodeString, o el literal de nodeNumber
+	bool   bool
+	items  []node
+	fields []field
+}
+
+// field es un par clave-valor de un objeto.
+type field struct {
+	key   string
+	value node
+}
+
+// toTree convierte v en un árbol a partir de su representación JSON.
+func toTree(v interface{}) (node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return node{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return readNode(decoder)
+}
+
+// readNode lee el siguiente valor del decoder.
+func readNode(decoder *json.Decoder) (node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return node{}, err
+	}
+
+	switch t := token.(type) {
+	case nil:
+		return node{kind: nodeNull}, nil
+	case bool:
+		return node{kind: nodeBool, bool: t}, nil
+	case json.Number:
+		return node{kind: nodeNumber, text: t.String()}, nil
+	case string:
+		return node{kind: nodeString, text: t}, nil
+	case json.Delim:
+		switch t {
+		case '[':
+			n := node{kind: nodeArray, items: []node{}}
+			for decoder.More() {
+				item, err := readNode(decoder)
+				if err != nil {
+					return node{}, err
+				}
+				n.items = append(n.items, item)
+			}
+			_, err := decoder.Token()
+			return n, err
+		case '{':
+			n := node{kind: nodeObject, fields: []field{}}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return node{}, err
+				}
+				value, err := readNode(decoder)
+				if err != nil {
+					return node{}, err
+				}
+				n.fields = append(n.fields, field{key: key.(string), value: value})
+			}
+			_, err := decoder.Token()
+			return n, err
+		}
+	}
+
+	return node{}, fmt.Errorf("unexpected JSON token %v", token)
+}
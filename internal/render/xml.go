@@ -0,0 +1,134 @@
+package render
+
+import (
+	"encoding/xml"
+	"io"
+	"project/internal/models"
+	"unicode"
+)
+
+// XML codifica las respuestas en XML con los mismos campos que la respuesta JSON:
+//
+//   - cada campo de un objeto es un elemento con el nombre del campo;
+//   - los elementos de un array se codifican como elementos <item>;
+//   - las claves que no son nombres XML válidos (por ejemplo, los IDs de los mapas
+//     de la comparación o una especificación con espacios) se codifican como
+//     <entry key="...">;
+//   - los campos nulos se omiten.
+type XML struct{}
+
+// ContentType implementa Encoder.
+func (XML) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+// Encode implementa Encoder.
+func (XML) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	if err := encodeXMLNode(encoder, xmlElement(xmlRootName(v)), tree); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// xmlRootName devuelve el nombre del elemento raíz según el tipo de la respuesta.
+func xmlRootName(v interface{}) string {
+	switch v.(type) {
+	case models.Item, *models.Item:
+		return "item"
+	case []models.Item:
+		return "items"
+	case models.ItemPage, *models.ItemPage:
+		return "item_page"
+	case models.CompareResponse, *models.CompareResponse:
+		return "comparison"
+	default:
+		return "response"
+	}
+}
+
+// xmlElement crea el elemento de un campo. Si la clave no es un nombre XML
+// válido, el elemento es <entry> y la clave va en el atributo key.
+func xmlElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// isXMLName indica si key puede usarse como nombre de elemento: letras, dígitos,
+// guiones, puntos y guiones bajos, sin empezar por dígito, guion o punto ni por "xml".
+func isXMLName(key string) bool {
+	if key == "" || len(key) >= 3 && (key[0]|0x20) == 'x' && (key[1]|0x20) == 'm' && (key[2]|0x20) == 'l' {
+		return false
+	}
+
+	for i, r := range key {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// encodeXMLNode escribe el elemento start con el contenido de n.
+func encodeXMLNode(encoder *xml.Encoder, start xml.StartElement, n node) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch n.kind {
+	case nodeBool:
+		text := "false"
+		if n.bool {
+			text = "true"
+		}
+		if err := encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	case nodeNumber, nodeString:
+		if err := encoder.EncodeToken(xml.CharData(n.text)); err != nil {
+			return err
+		}
+	case nodeArray:
+		for _, item := range n.items {
+			if item.kind == nodeNull {
+				continue
+			}
+			if err := encodeXMLNode(encoder, xmlElement("item"), item); err != nil {
+				return err
+			}
+		}
+	case nodeObject:
+		for _, f := range n.fields {
+			if f.value.kind == nodeNull {
+				continue
+			}
+			if err := encodeXMLNode(encoder, xmlElement(f.key), f.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
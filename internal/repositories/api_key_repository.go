@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+)
+
+// APIKeyRepository define el acceso a datos de las API keys.
+type APIKeyRepository interface {
+	// Create guarda una nueva clave y completa su ID y su fecha de alta.
+	Create(ctx context.Context, key *models.APIKey) error
+
+	// GetByID busca una clave por su ID, esté revocada o no.
+	// Retorna ErrNotFound si la clave no existe.
+	GetByID(ctx context.Context, id int64) (*models.APIKey, error)
+
+	// GetByPrefix busca una clave por su prefijo público.
+	// Retorna ErrNotFound si ninguna clave tiene ese prefijo.
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+
+	// List devuelve todas las claves, incluidas las revocadas, ordenadas por ID.
+	List(ctx context.Context) ([]models.APIKey, error)
+
+	// Rotate reemplaza el prefijo y el hash de una clave no revocada.
+	// Retorna ErrNotFound si la clave no existe o está revocada.
+	Rotate(ctx context.Context, id int64, prefix, hash string) error
+
+	// Revoke revoca una clave. Retorna ErrNotFound si la clave no existe o ya
+	// estaba revocada.
+	Revoke(ctx context.Context, id int64) error
+
+	// TouchLastUsed registra at como el último uso de la clave.
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
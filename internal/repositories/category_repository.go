@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"project/internal/models"
+)
+
+// CategoryRepository define el acceso a datos de las categorías de productos.
+type CategoryRepository interface {
+	// GetAll devuelve todas las categorías como una lista plana, ordenadas por nombre.
+	GetAll(ctx context.Context) ([]models.Category, error)
+
+	// GetByID busca una categoría por su ID.
+	// Retorna ErrNotFound si la categoría no existe.
+	GetByID(ctx context.Context, id int64) (*models.Category, error)
+
+	// SpecFields devuelve los campos de especificación declarados en la categoría
+	// y en todos sus ancestros, ordenados desde la categoría raíz.
+	SpecFields(ctx context.Context, categoryID int64) ([]models.SpecField, error)
+}
@@ -5,3 +5,7 @@ import "errors"
 // ErrNotFound es devuelto por la capa de repositorio cuando
 // no se puede encontrar un recurso específico.
 var ErrNotFound = errors.New("recurso no encontrado")
+
+// ErrVersionConflict es devuelto por las actualizaciones versionadas cuando la
+// versión actual del recurso no es la esperada.
+var ErrVersionConflict = errors.New("la versión del recurso no coincide")
@@ -11,8 +11,18 @@ import (
 // implementaciones.
 // Sigue el patrón Repository y aplica el Principio de Inversión de Dependencias.
 type ItemRepository interface {
-	// GetAll obtiene todos los items almacenados en el repositorio.
-	GetAll(ctx context.Context) ([]models.Item, error)
+	// GetAll obtiene los items que cumplen los filtros de la consulta,
+	// aplicando el ordenamiento y la paginación indicados.
+	GetAll(ctx context.Context, query models.ItemQuery) ([]models.Item, error)
+
+	// Count devuelve el total de items que cumplen los filtros de la consulta,
+	// ignorando la paginación.
+	Count(ctx context.Context, query models.ItemQuery) (int, error)
+
+	// Stream recorre los items que cumplen los filtros de la consulta, sin paginar,
+	// y llama a fn con cada uno sin cargarlos todos en memoria. Un error de fn
+	// interrumpe el recorrido y se devuelve tal cual.
+	Stream(ctx context.Context, query models.ItemQuery, fn func(item models.Item) error) error
 
 	// GetByID busca un item por su identificador único (ID).
 	GetByID(ctx context.Context, id int64) (*models.Item, error)
@@ -20,8 +30,51 @@ type ItemRepository interface {
 	// GetByIDs obtiene múltiples items a partir de una lista de IDs.
 	GetByIDs(ctx context.Context, ids []int64) ([]models.Item, error)
 
-	// Seed inicializa la base de datos con datos de prueba o datos por defecto.
-	Seed(ctx context.Context) error
+	// CatalogState devuelve la versión y la fecha de la última modificación del
+	// catálogo. La versión cambia con cada alta, modificación o baja de un item.
+	CatalogState(ctx context.Context) (*models.CatalogState, error)
+
+	// SpecKeys devuelve las claves de especificación distintas presentes en el catálogo.
+	SpecKeys(ctx context.Context) ([]string, error)
+
+	// Search realiza una búsqueda de texto completo sobre el nombre, la descripción
+	// y las especificaciones, devolviendo los resultados ordenados por relevancia.
+	Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error)
+
+	// Create inserta un nuevo item y asigna al item recibido el ID generado, la
+	// versión inicial y las fechas de creación y modificación.
+	Create(ctx context.Context, item *models.Item) error
+
+	// Update reemplaza todos los campos de un item existente, incrementa su versión
+	// y asigna al item recibido la nueva versión y fecha de modificación.
+	// Retorna ErrNotFound si el item no existe.
+	Update(ctx context.Context, item *models.Item) error
+
+	// UpdateVersioned es como Update, pero solo modifica el item si su versión actual
+	// es expectedVersion. Retorna ErrVersionConflict si la versión no coincide y
+	// ErrNotFound si el item no existe.
+	UpdateVersioned(ctx context.Context, item *models.Item, expectedVersion int64) error
+
+	// Delete elimina un item por su ID.
+	// Retorna ErrNotFound si el item no existe.
+	Delete(ctx context.Context, id int64) error
+
+	// Revisions devuelve el historial de revisiones de un item, de la más antigua a
+	// la más reciente. Cada alta, modificación o baja del item agrega una revisión
+	// con el autor de la escritura (ver models.WithActor).
+	Revisions(ctx context.Context, itemID int64) ([]models.ItemRevision, error)
+
+	// Revision devuelve una revisión de un item.
+	// Retorna ErrNotFound si no existe.
+	Revision(ctx context.Context, itemID, revision int64) (*models.ItemRevision, error)
+
+	// InTransaction ejecuta fn con un repositorio cuyas operaciones forman parte de
+	// una misma transacción. Si fn devuelve un error, la transacción se revierte.
+	InTransaction(ctx context.Context, fn func(tx ItemRepository) error) error
+
+	// Seed carga los fixtures indicados (JSON o YAML), insertando o actualizando los
+	// items por nombre. Sin fixtures, carga el catálogo de ejemplo si no hay items.
+	Seed(ctx context.Context, fixturePaths ...string) (*models.SeedReport, error)
 
 	// Close cierra la conexión o recursos asociados al repositorio.
 	// Esto es esencial para liberar recursos del sistema o conexiones abiertas.
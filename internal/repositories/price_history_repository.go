@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+)
+
+// PriceHistoryRepository define el acceso a la serie temporal de precios de los items.
+type PriceHistoryRepository interface {
+	// PriceHistory devuelve los precios del item registrados desde since, precedidos
+	// por el vigente en since si es anterior, ordenados cronológicamente. Incluye
+	// los de los items eliminados.
+	PriceHistory(ctx context.Context, itemID int64, since time.Time) ([]models.PricePoint, error)
+
+	// LowestPrices devuelve, por ID de item y por moneda, el precio más bajo vigente
+	// en algún momento desde since. Los items sin precios registrados no se incluyen.
+	LowestPrices(ctx context.Context, itemIDs []int64, since time.Time) (map[int64]map[string]float64, error)
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS items_fts;
+DROP TABLE items;
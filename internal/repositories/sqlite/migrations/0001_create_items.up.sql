@@ -0,0 +1,9 @@
+CREATE TABLE items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	image_url TEXT NOT NULL,
+	description TEXT NOT NULL,
+	price REAL NOT NULL,
+	rating REAL NOT NULL,
+	specifications TEXT NOT NULL
+);
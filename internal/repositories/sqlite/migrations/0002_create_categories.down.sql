@@ -0,0 +1,5 @@
+DROP INDEX idx_items_category_id;
+
+ALTER TABLE items DROP COLUMN category_id;
+
+DROP TABLE categories;
@@ -0,0 +1,10 @@
+CREATE TABLE categories (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	slug TEXT NOT NULL UNIQUE,
+	parent_id INTEGER REFERENCES categories(id)
+);
+
+ALTER TABLE items ADD COLUMN category_id INTEGER REFERENCES categories(id);
+
+CREATE INDEX idx_items_category_id ON items(category_id);
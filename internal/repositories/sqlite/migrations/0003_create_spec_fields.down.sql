@@ -0,0 +1 @@
+DROP TABLE spec_fields;
@@ -0,0 +1,10 @@
+CREATE TABLE spec_fields (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	category_id INTEGER NOT NULL REFERENCES categories(id),
+	key TEXT NOT NULL,
+	label TEXT NOT NULL,
+	type TEXT NOT NULL,
+	dimension TEXT NOT NULL DEFAULT '',
+	required INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (category_id, key)
+);
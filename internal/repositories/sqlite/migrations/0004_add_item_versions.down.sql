@@ -0,0 +1,11 @@
+DROP TRIGGER items_catalog_ad;
+
+DROP TRIGGER items_catalog_au;
+
+DROP TRIGGER items_catalog_ai;
+
+DROP TABLE catalog_state;
+
+ALTER TABLE items DROP COLUMN updated_at;
+
+ALTER TABLE items DROP COLUMN version;
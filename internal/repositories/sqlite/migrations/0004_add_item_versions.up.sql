@@ -0,0 +1,27 @@
+ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE items ADD COLUMN updated_at TEXT NOT NULL DEFAULT '1970-01-01T00:00:00.000Z';
+
+UPDATE items SET updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now');
+
+-- catalog_state tiene una única fila con la versión del catálogo completo, que los
+-- triggers incrementan ante cualquier alta, modificación o baja de items.
+CREATE TABLE catalog_state (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	version INTEGER NOT NULL,
+	updated_at TEXT NOT NULL
+);
+
+INSERT INTO catalog_state (id, version, updated_at) VALUES (1, 1, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+
+CREATE TRIGGER items_catalog_ai AFTER INSERT ON items BEGIN
+	UPDATE catalog_state SET version = version + 1, updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now') WHERE id = 1;
+END;
+
+CREATE TRIGGER items_catalog_au AFTER UPDATE ON items BEGIN
+	UPDATE catalog_state SET version = version + 1, updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now') WHERE id = 1;
+END;
+
+CREATE TRIGGER items_catalog_ad AFTER DELETE ON items BEGIN
+	UPDATE catalog_state SET version = version + 1, updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now') WHERE id = 1;
+END;
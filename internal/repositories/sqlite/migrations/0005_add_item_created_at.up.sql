@@ -0,0 +1,5 @@
+ALTER TABLE items ADD COLUMN created_at TEXT NOT NULL DEFAULT '1970-01-01T00:00:00.000Z';
+
+-- La fecha de creación de los items existentes se desconoce; la más aproximada es
+-- la de su última modificación.
+UPDATE items SET created_at = updated_at;
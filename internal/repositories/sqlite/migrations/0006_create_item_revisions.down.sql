@@ -0,0 +1,9 @@
+DROP TRIGGER items_revisions_ad;
+
+DROP TRIGGER items_revisions_au;
+
+DROP TRIGGER items_revisions_ai;
+
+DROP TABLE revision_context;
+
+DROP TABLE item_revisions;
@@ -0,0 +1,87 @@
+-- item_revisions guarda una fila por cada cambio de un item, con los valores
+-- anteriores y los nuevos. La completan los triggers ante cualquier escritura, de modo
+-- que también quedan registradas las del seed, la importación o el SQL manual.
+CREATE TABLE item_revisions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id INTEGER NOT NULL,
+	revision INTEGER NOT NULL,
+	operation TEXT NOT NULL CHECK (operation IN ('create', 'update', 'delete')),
+	actor TEXT,
+	source TEXT,
+	changed_at TEXT NOT NULL,
+	old_values TEXT,
+	new_values TEXT,
+	UNIQUE (item_id, revision)
+);
+
+-- revision_context contiene, solo durante la transacción de una escritura, el autor
+-- y el origen que los triggers copian en item_revisions. Sin fila, quedan en NULL.
+CREATE TABLE revision_context (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	actor TEXT NOT NULL,
+	source TEXT NOT NULL
+);
+
+CREATE TRIGGER items_revisions_ai AFTER INSERT ON items BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		NEW.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = NEW.id),
+		'create',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		NULL,
+		json_object(
+			'name', NEW.name, 'image_url', NEW.image_url, 'description', NEW.description,
+			'price', NEW.price, 'rating', NEW.rating, 'specifications', json(NEW.specifications),
+			'category_id', NEW.category_id
+		)
+	);
+END;
+
+-- Solo se registran los cambios de contenido: los de versión o fechas no son revisiones.
+CREATE TRIGGER items_revisions_au AFTER UPDATE ON items
+WHEN OLD.name IS NOT NEW.name OR OLD.image_url IS NOT NEW.image_url
+	OR OLD.description IS NOT NEW.description OR OLD.price IS NOT NEW.price
+	OR OLD.rating IS NOT NEW.rating OR OLD.specifications IS NOT NEW.specifications
+	OR OLD.category_id IS NOT NEW.category_id
+BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		NEW.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = NEW.id),
+		'update',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		json_object(
+			'name', OLD.name, 'image_url', OLD.image_url, 'description', OLD.description,
+			'price', OLD.price, 'rating', OLD.rating, 'specifications', json(OLD.specifications),
+			'category_id', OLD.category_id
+		),
+		json_object(
+			'name', NEW.name, 'image_url', NEW.image_url, 'description', NEW.description,
+			'price', NEW.price, 'rating', NEW.rating, 'specifications', json(NEW.specifications),
+			'category_id', NEW.category_id
+		)
+	);
+END;
+
+CREATE TRIGGER items_revisions_ad AFTER DELETE ON items BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		OLD.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = OLD.id),
+		'delete',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		json_object(
+			'name', OLD.name, 'image_url', OLD.image_url, 'description', OLD.description,
+			'price', OLD.price, 'rating', OLD.rating, 'specifications', json(OLD.specifications),
+			'category_id', OLD.category_id
+		),
+		NULL
+	);
+END;
@@ -0,0 +1,7 @@
+DROP TRIGGER items_prices_au;
+
+DROP TRIGGER items_prices_ai;
+
+DROP INDEX idx_item_prices_item_recorded;
+
+DROP TABLE item_prices;
@@ -0,0 +1,37 @@
+-- item_prices es la serie temporal de precios de cada item: una fila por cada precio,
+-- desde el momento en que empieza a regir. La completan los triggers, de modo que
+-- cubre cualquier escritura, y se conserva al eliminar el item.
+CREATE TABLE item_prices (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id INTEGER NOT NULL,
+	price REAL NOT NULL,
+	recorded_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_item_prices_item_recorded ON item_prices (item_id, recorded_at);
+
+-- La serie empieza con los cambios de precio que ya registró el historial de
+-- revisiones y, para los items sin ninguno, con el precio actual desde su alta.
+INSERT INTO item_prices (item_id, price, recorded_at)
+SELECT item_id, json_extract(new_values, '$.price'), changed_at
+FROM item_revisions
+WHERE new_values IS NOT NULL
+	AND (old_values IS NULL OR json_extract(old_values, '$.price') IS NOT json_extract(new_values, '$.price'))
+ORDER BY item_id, revision;
+
+INSERT INTO item_prices (item_id, price, recorded_at)
+SELECT id, price, created_at
+FROM items
+WHERE id NOT IN (SELECT item_id FROM item_prices);
+
+CREATE TRIGGER items_prices_ai AFTER INSERT ON items BEGIN
+	INSERT INTO item_prices (item_id, price, recorded_at)
+	VALUES (NEW.id, NEW.price, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+END;
+
+CREATE TRIGGER items_prices_au AFTER UPDATE OF price ON items
+WHEN OLD.price IS NOT NEW.price
+BEGIN
+	INSERT INTO item_prices (item_id, price, recorded_at)
+	VALUES (NEW.id, NEW.price, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+END;
@@ -0,0 +1,97 @@
+-- Cada item tiene la moneda (código ISO 4217) de su precio. Los existentes estaban
+-- en dólares.
+ALTER TABLE items ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+ALTER TABLE item_prices ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+UPDATE item_revisions SET old_values = json_set(old_values, '$.currency', 'USD') WHERE old_values IS NOT NULL;
+
+UPDATE item_revisions SET new_values = json_set(new_values, '$.currency', 'USD') WHERE new_values IS NOT NULL;
+
+-- Los triggers del historial de revisiones y de la serie de precios pasan a
+-- registrar la moneda.
+DROP TRIGGER items_revisions_ai;
+
+DROP TRIGGER items_revisions_au;
+
+DROP TRIGGER items_revisions_ad;
+
+DROP TRIGGER items_prices_ai;
+
+DROP TRIGGER items_prices_au;
+
+CREATE TRIGGER items_revisions_ai AFTER INSERT ON items BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		NEW.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = NEW.id),
+		'create',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		NULL,
+		json_object(
+			'name', NEW.name, 'image_url', NEW.image_url, 'description', NEW.description,
+			'price', NEW.price, 'currency', NEW.currency, 'rating', NEW.rating, 'specifications', json(NEW.specifications),
+			'category_id', NEW.category_id
+		)
+	);
+END;
+
+CREATE TRIGGER items_revisions_au AFTER UPDATE ON items
+WHEN OLD.name IS NOT NEW.name OR OLD.image_url IS NOT NEW.image_url
+	OR OLD.description IS NOT NEW.description OR OLD.price IS NOT NEW.price
+	OR OLD.currency IS NOT NEW.currency
+	OR OLD.rating IS NOT NEW.rating OR OLD.specifications IS NOT NEW.specifications
+	OR OLD.category_id IS NOT NEW.category_id
+BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		NEW.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = NEW.id),
+		'update',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		json_object(
+			'name', OLD.name, 'image_url', OLD.image_url, 'description', OLD.description,
+			'price', OLD.price, 'currency', OLD.currency, 'rating', OLD.rating, 'specifications', json(OLD.specifications),
+			'category_id', OLD.category_id
+		),
+		json_object(
+			'name', NEW.name, 'image_url', NEW.image_url, 'description', NEW.description,
+			'price', NEW.price, 'currency', NEW.currency, 'rating', NEW.rating, 'specifications', json(NEW.specifications),
+			'category_id', NEW.category_id
+		)
+	);
+END;
+
+CREATE TRIGGER items_revisions_ad AFTER DELETE ON items BEGIN
+	INSERT INTO item_revisions (item_id, revision, operation, actor, source, changed_at, old_values, new_values)
+	VALUES (
+		OLD.id,
+		(SELECT COALESCE(MAX(revision), 0) + 1 FROM item_revisions WHERE item_id = OLD.id),
+		'delete',
+		(SELECT actor FROM revision_context WHERE id = 1),
+		(SELECT source FROM revision_context WHERE id = 1),
+		strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+		json_object(
+			'name', OLD.name, 'image_url', OLD.image_url, 'description', OLD.description,
+			'price', OLD.price, 'currency', OLD.currency, 'rating', OLD.rating, 'specifications', json(OLD.specifications),
+			'category_id', OLD.category_id
+		),
+		NULL
+	);
+END;
+
+CREATE TRIGGER items_prices_ai AFTER INSERT ON items BEGIN
+	INSERT INTO item_prices (item_id, price, currency, recorded_at)
+	VALUES (NEW.id, NEW.price, NEW.currency, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+END;
+
+CREATE TRIGGER items_prices_au AFTER UPDATE OF price, currency ON items
+WHEN OLD.price IS NOT NEW.price OR OLD.currency IS NOT NEW.currency
+BEGIN
+	INSERT INTO item_prices (item_id, price, currency, recorded_at)
+	VALUES (NEW.id, NEW.price, NEW.currency, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+END;
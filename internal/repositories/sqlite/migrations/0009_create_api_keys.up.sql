@@ -0,0 +1,14 @@
+-- api_keys guarda las claves de acceso a la API. De cada clave solo se guarda su
+-- hash SHA-256; prefix es la parte pública de la clave con la que se busca al
+-- autenticar. Las claves revocadas se conservan para el listado.
+CREATE TABLE api_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	prefix TEXT NOT NULL UNIQUE,
+	key_hash TEXT NOT NULL,
+	scopes TEXT NOT NULL,
+	created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+	rotated_at TEXT,
+	last_used_at TEXT,
+	revoked_at TEXT
+);
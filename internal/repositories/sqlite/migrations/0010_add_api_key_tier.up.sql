@@ -0,0 +1,2 @@
+-- tier determina los límites de tasa de los clientes que usan la clave.
+ALTER TABLE api_keys ADD COLUMN tier TEXT NOT NULL DEFAULT 'standard';
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"project/internal/models"
+	"project/internal/repositories"
+	"strings"
+	"time"
+)
+
+// SQLiteAPIKeyRepository implementa APIKeyRepository sobre la tabla api_keys.
+type SQLiteAPIKeyRepository struct {
+	DB *sql.DB
+}
+
+// NewSQLiteAPIKeyRepository crea un repositorio de API keys sobre una conexión ya
+// abierta y migrada.
+func NewSQLiteAPIKeyRepository(db *sql.DB) *SQLiteAPIKeyRepository {
+	return &SQLiteAPIKeyRepository{DB: db}
+}
+
+// apiKeyColumns enumera las columnas seleccionadas en las consultas de API keys,
+// en el mismo orden que espera scanAPIKey.
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, tier, created_at, rotated_at, last_used_at, revoked_at"
+
+// Create inserta la clave. Los scopes se guardan separados por espacios.
+func (r *SQLiteAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	var createdAt string
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, tier)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`, key.Name, key.Prefix, key.Hash, strings.Join(key.Scopes, " "), key.Tier).Scan(&key.ID, &createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	key.CreatedAt, err = parseTimestamp(createdAt)
+	return err
+}
+
+// GetByID busca una clave por su ID.
+func (r *SQLiteAPIKeyRepository) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+// GetByPrefix busca una clave por su prefijo.
+func (r *SQLiteAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	return r.getOne(ctx, "prefix = ?", prefix)
+}
+
+// getOne busca la única clave que cumple la condición where.
+func (r *SQLiteAPIKeyRepository) getOne(ctx context.Context, where string, arg interface{}) (*models.APIKey, error) {
+	row := r.DB.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE "+where, arg)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query api key: %w", err)
+	}
+	return &key, nil
+}
+
+// List devuelve todas las claves ordenadas por ID.
+func (r *SQLiteAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Rotate reemplaza el prefijo y el hash de la clave y registra la fecha de rotación.
+func (r *SQLiteAPIKeyRepository) Rotate(ctx context.Context, id int64, prefix, hash string) error {
+	result, err := r.DB.ExecContext(ctx, `
+		UPDATE api_keys SET prefix = ?, key_hash = ?, rotated_at = `+nowSQL+`
+		WHERE id = ? AND revoked_at IS NULL
+	`, prefix, hash, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate api key: %w", err)
+	}
+	return checkRowsAffected(result.RowsAffected())
+}
+
+// Revoke registra la fecha de revocación de la clave.
+func (r *SQLiteAPIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	result, err := r.DB.ExecContext(ctx,
+		"UPDATE api_keys SET revoked_at = "+nowSQL+" WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return checkRowsAffected(result.RowsAffected())
+}
+
+// TouchLastUsed actualiza la fecha del último uso de la clave.
+func (r *SQLiteAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE id = ?",
+		at.UTC().Format(timestampLayout), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+	return nil
+}
+
+// scanAPIKey lee una fila con las columnas de apiKeyColumns.
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes, createdAt string
+	var rotatedAt, lastUsedAt, revokedAt sql.NullString
+
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.Tier,
+		&createdAt, &rotatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return key, err
+	}
+
+	key.Scopes = strings.Fields(scopes)
+
+	var err error
+	if key.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return key, err
+	}
+	for _, column := range []struct {
+		value  sql.NullString
+		target **time.Time
+	}{
+		{rotatedAt, &key.RotatedAt},
+		{lastUsedAt, &key.LastUsedAt},
+		{revokedAt, &key.RevokedAt},
+	} {
+		if !column.value.Valid {
+			continue
+		}
+		parsed, err := parseTimestamp(column.value.String)
+		if err != nil {
+			return key, err
+		}
+		*column.target = &parsed
+	}
+
+	return key, nil
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"project/internal/models"
+	"project/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIKeys_Lifecycle: Las claves se buscan por prefijo; rotar cambia el prefijo y
+// revocar las conserva en el listado
+func TestAPIKeys_Lifecycle(t *testing.T) {
+	repo := NewSQLiteAPIKeyRepository(newTestDB(t))
+	ctx := context.Background()
+
+	key := &models.APIKey{Name: "ci", Prefix: "aaaa0000", Hash: "hash-1", Scopes: []string{models.ScopeItemsRead, models.ScopeItemsCompare}, Tier: models.TierPremium}
+	require.NoError(t, repo.Create(ctx, key))
+	assert.NotZero(t, key.ID)
+	assert.False(t, key.CreatedAt.IsZero())
+
+	found, err := repo.GetByPrefix(ctx, "aaaa0000")
+	require.NoError(t, err)
+	assert.Equal(t, key.Scopes, found.Scopes)
+	assert.Equal(t, models.TierPremium, found.Tier)
+	assert.Nil(t, found.LastUsedAt)
+
+	usedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.TouchLastUsed(ctx, key.ID, usedAt))
+	require.NoError(t, repo.Rotate(ctx, key.ID, "bbbb1111", "hash-2"))
+
+	_, err = repo.GetByPrefix(ctx, "aaaa0000")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	rotated, err := repo.GetByPrefix(ctx, "bbbb1111")
+	require.NoError(t, err)
+	assert.Equal(t, "hash-2", rotated.Hash)
+	assert.NotNil(t, rotated.RotatedAt)
+	assert.Equal(t, usedAt, *rotated.LastUsedAt)
+
+	require.NoError(t, repo.Revoke(ctx, key.ID))
+	assert.ErrorIs(t, repo.Revoke(ctx, key.ID), repositories.ErrNotFound)
+	assert.ErrorIs(t, repo.Rotate(ctx, key.ID, "cccc2222", "hash-3"), repositories.ErrNotFound)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, keys[0].Revoked())
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"project/internal/models"
+	"project/internal/repositories"
+)
+
+// SQLiteCategoryRepository implementa CategoryRepository sobre la misma base de
+// datos que SQLiteItemRepository, que es quien crea la tabla categories.
+type SQLiteCategoryRepository struct {
+	DB *sql.DB
+}
+
+// NewSQLiteCategoryRepository crea un repositorio de categorías sobre una
+// conexión ya abierta (normalmente SQLiteItemRepository.DB).
+func NewSQLiteCategoryRepository(db *sql.DB) *SQLiteCategoryRepository {
+	return &SQLiteCategoryRepository{DB: db}
+}
+
+// categoryColumns enumera las columnas seleccionadas en las consultas de categorías.
+const categoryColumns = "id, name, slug, parent_id"
+
+// GetAll devuelve todas las categorías ordenadas por nombre.
+func (r *SQLiteCategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT "+categoryColumns+" FROM categories ORDER BY name COLLATE NOCASE, id")
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar las categorías: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear la categoría: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar las categorías: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetByID busca una categoría por su ID.
+// Retorna repositories.ErrNotFound si no existe.
+func (r *SQLiteCategoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	row := r.DB.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE id = ?", id)
+
+	category, err := scanCategory(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("error al consultar la categoría: %w", err)
+	}
+
+	return &category, nil
+}
+
+// scanCategory lee una fila con las columnas de categoryColumns.
+func scanCategory(row rowScanner) (models.Category, error) {
+	var category models.Category
+	var parentID sql.NullInt64
+
+	if err := row.Scan(&category.ID, &category.Name, &category.Slug, &parentID); err != nil {
+		return category, err
+	}
+
+	if parentID.Valid {
+		category.ParentID = &parentID.Int64
+	}
+
+	return category, nil
+}
+
+// maxCategoryDepth limita la búsqueda de ancestros para no recorrer ciclos indefinidamente.
+const maxCategoryDepth = 32
+
+// SpecFields devuelve los campos de especificación de la categoría y de sus
+// ancestros, desde la raíz hacia la categoría y, en cada nivel, en orden de alta.
+func (r *SQLiteCategoryRepository) SpecFields(ctx context.Context, categoryID int64) ([]models.SpecField, error) {
+	return querySpecFields(ctx, r.DB, categoryID)
+}
+
+// querySpecFields implementa SpecFields sobre la base de datos o una transacción.
+func querySpecFields(ctx context.Context, q queryer, categoryID int64) ([]models.SpecField, error) {
+	rows, err := q.QueryContext(ctx, `
+		WITH RECURSIVE ancestors(id, depth) AS (
+			SELECT ?, 0
+			UNION
+			SELECT categories.parent_id, ancestors.depth + 1
+			FROM categories JOIN ancestors ON categories.id = ancestors.id
+			WHERE categories.parent_id IS NOT NULL AND ancestors.depth < ?
+		)
+		SELECT spec_fields.category_id, spec_fields.key, spec_fields.label,
+			spec_fields.type, spec_fields.dimension, spec_fields.required
+		FROM spec_fields
+		JOIN ancestors ON spec_fields.category_id = ancestors.id
+		ORDER BY ancestors.depth DESC, spec_fields.id
+	`, categoryID, maxCategoryDepth)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar los campos de especificación: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []models.SpecField{}
+	for rows.Next() {
+		var field models.SpecField
+		if err := rows.Scan(
+			&field.CategoryID,
+			&field.Key,
+			&field.Label,
+			&field.Type,
+			&field.Dimension,
+			&field.Required,
+		); err != nil {
+			return nil, fmt.Errorf("error al escanear el campo de especificación: %w", err)
+		}
+		fields = append(fields, field)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar los campos de especificación: %w", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"project/internal/models"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFixture es el catálogo de ejemplo que se carga cuando no se indican fixtures.
+//
+//go:embed fixtures/default.yaml
+var defaultFixture []byte
+
+// defaultFixtureName identifica el fixture embebido en los mensajes de error.
+const defaultFixtureName = "fixtures/default.yaml"
+
+// Fixture es el contenido de un archivo de datos iniciales (JSON o YAML).
+type Fixture struct {
+	Categories []FixtureCategory `json:"categories" yaml:"categories"`
+	Items      []FixtureItem     `json:"items" yaml:"items"`
+}
+
+// FixtureCategory es una categoría identificada por su slug. Parent es el slug de
+// la categoría padre, que debe existir o aparecer antes en los fixtures.
+type FixtureCategory struct {
+	Name       string             `json:"name" yaml:"name"`
+	Slug       string             `json:"slug" yaml:"slug"`
+	Parent     string             `json:"parent" yaml:"parent"`
+	SpecFields []FixtureSpecField `json:"spec_fields" yaml:"spec_fields"`
+}
+
+// FixtureSpecField es un campo del esquema de especificaciones de una categoría.
+type FixtureSpecField struct {
+	Key       string               `json:"key" yaml:"key"`
+	Label     string               `json:"label" yaml:"label"`
+	Type      models.SpecValueType `json:"type" yaml:"type"`
+	Dimension models.Dimension     `json:"dimension" yaml:"dimension"`
+	Required  bool                 `json:"required" yaml:"required"`
+}
+
+// FixtureItem es un item identificado por su nombre. Category es el slug de su
+// categoría. Sin Currency, el precio está en models.DefaultCurrency.
+type FixtureItem struct {
+	Name           string                `json:"name" yaml:"name"`
+	ImageURL       string                `json:"image_url" yaml:"image_url"`
+	Description    string                `json:"description" yaml:"description"`
+	Price          float64               `json:"price" yaml:"price"`
+	Currency       string                `json:"currency" yaml:"currency"`
+	Rating         float64               `json:"rating" yaml:"rating"`
+	Category       string                `json:"category" yaml:"category"`
+	Specifications models.Specifications `json:"specifications" yaml:"specifications"`
+}
+
+// LoadFixture lee un archivo de fixtures. El formato se deduce de la extensión:
+// .json, o .yaml/.yml.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	return parseFixture(path, data)
+}
+
+// parseFixture decodifica y valida un fixture. Los campos desconocidos se rechazan
+// para detectar errores de escritura en las claves.
+func parseFixture(name string, data []byte) (*Fixture, error) {
+	var fixture Fixture
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", name, err)
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&fixture); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid fixture %s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q: use .json, .yaml or .yml", name)
+	}
+
+	if problems := fixture.problems(); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid fixture %s: %s", name, strings.Join(problems, "; "))
+	}
+
+	return &fixture, nil
+}
+
+// problems valida la estructura del fixture: campos obligatorios, rangos, tipos de
+// campo conocidos y claves naturales sin repetir. Las especificaciones de cada item
+// se validan contra el esquema de su categoría al cargarlo en la base de datos.
+func (f *Fixture) problems() []string {
+	var problems []string
+
+	slugs := make(map[string]bool, len(f.Categories))
+	for i, category := range f.Categories {
+		where := fmt.Sprintf("categories[%d]", i)
+		switch {
+		case category.Slug == "":
+			problems = append(problems, where+": slug is required")
+		case slugs[category.Slug]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate slug %q", where, category.Slug))
+		case category.Parent == category.Slug:
+			problems = append(problems, fmt.Sprintf("%s: category %q cannot be its own parent", where, category.Slug))
+		}
+		slugs[category.Slug] = true
+
+		if strings.TrimSpace(category.Name) == "" {
+			problems = append(problems, where+": name is required")
+		}
+
+		keys := make(map[string]bool, len(category.SpecFields))
+		for j, field := range category.SpecFields {
+			fieldWhere := fmt.Sprintf("%s.spec_fields[%d]", where, j)
+			if field.Key == "" {
+				problems = append(problems, fieldWhere+": key is required")
+			} else if keys[field.Key] {
+				problems = append(problems, fmt.Sprintf("%s: duplicate key %q", fieldWhere, field.Key))
+			}
+			keys[field.Key] = true
+			problems = append(problems, field.problems(fieldWhere)...)
+		}
+	}
+
+	names := make(map[string]bool, len(f.Items))
+	for i, item := range f.Items {
+		where := fmt.Sprintf("items[%d]", i)
+		switch {
+		case strings.TrimSpace(item.Name) == "":
+			problems = append(problems, where+": name is required")
+		case names[item.Name]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate name %q", where, item.Name))
+		}
+		names[item.Name] = true
+
+		if item.Price <= 0 {
+			problems = append(problems, where+": price must be greater than 0")
+		}
+		if item.Currency != "" && !models.IsSupportedCurrency(models.NormalizeCurrency(item.Currency)) {
+			problems = append(problems, fmt.Sprintf("%s: unsupported currency %q", where, item.Currency))
+		}
+		if item.Rating < 0 || item.Rating > 5 {
+			problems = append(problems, where+": rating must be between 0 and 5")
+		}
+		if u, err := url.Parse(item.ImageURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, where+": image_url must be an absolute http(s) URL")
+		}
+	}
+
+	return problems
+}
+
+// problems valida el tipo del campo y que solo las cantidades declaren una dimensión conocida.
+func (f FixtureSpecField) problems(where string) []string {
+	var problems []string
+
+	if strings.TrimSpace(f.Label) == "" {
+		problems = append(problems, where+": label is required")
+	}
+
+	switch f.Type {
+	case models.SpecTypeString, models.SpecTypeNumber, models.SpecTypeBoolean:
+		if f.Dimension != "" {
+			problems = append(problems, where+": only quantity fields have a dimension")
+		}
+	case models.SpecTypeQuantity:
+		if f.Dimension == models.DimensionNumber || f.Dimension.CanonicalUnit() == "" {
+			problems = append(problems, fmt.Sprintf("%s: unknown dimension %q", where, f.Dimension))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s: unknown type %q", where, f.Type))
+	}
+
+	return problems
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"database/sql"
+	"project/internal/models"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName is the go-sqlite3 driver registered with the catalog's SQL functions.
+const driverName = "sqlite3_catalog"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("spec_quantity", specQuantity, true)
+		},
+	})
+}
+
+// specQuantity implementa la función SQL spec_quantity(valor, dimensión): devuelve el
+// valor de especificación en la unidad canónica de su dimensión ("385 g" -> 0.385,
+// "1TB SSD" -> 1024) o NULL si no es cuantificable. Si dimension no está vacía, los
+// valores de otra dimensión también devuelven NULL, por lo que no cumplen ninguna
+// comparación.
+func specQuantity(value interface{}, dimension string) interface{} {
+	normalized, ok := models.NormalizeSpecValue(value)
+	if !ok || (dimension != "" && normalized.Dimension != models.Dimension(dimension)) {
+		return nil
+	}
+	return normalized.Value
+}
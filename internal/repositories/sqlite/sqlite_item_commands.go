@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project/internal/models"
+	"project/internal/repositories"
+	"time"
+)
+
+// Create inserta un nuevo item en la base de datos.
+// Serializa las especificaciones a JSON y asigna al item el ID generado por SQLite,
+// la versión inicial y las fechas de creación y modificación.
+func (r *SQLiteItemRepository) Create(ctx context.Context, item *models.Item) error {
+	query := `
+		INSERT INTO items (name, image_url, description, price, currency, rating, specifications, category_id, version, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, ` + nowSQL + `, ` + nowSQL + `)
+		RETURNING id, version, created_at, updated_at
+	`
+
+	specsJSON, err := marshalSpecifications(item.Specifications)
+	if err != nil {
+		return err
+	}
+	setDefaultCurrency(item)
+
+	var createdAt, updatedAt string
+	err = r.writeItems(ctx, func(q queryer) error {
+		return q.QueryRowContext(
+			ctx,
+			query,
+			item.Name,
+			item.ImageURL,
+			item.Description,
+			item.Price,
+			item.Currency,
+			item.Rating,
+			specsJSON,
+			item.CategoryID,
+		).Scan(&item.ID, &item.Version, &createdAt, &updatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert item: %w", err)
+	}
+
+	return setTimestamps(item, createdAt, updatedAt)
+}
+
+// Update reemplaza los campos de un item existente identificado por item.ID,
+// incrementa su versión y asigna al item la nueva versión, su fecha de creación y
+// la nueva fecha de modificación.
+// Retorna repositories.ErrNotFound si ninguna fila fue afectada.
+func (r *SQLiteItemRepository) Update(ctx context.Context, item *models.Item) error {
+	found, err := r.update(ctx, item, "")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateVersioned es como Update, pero la condición sobre la versión forma parte
+// del UPDATE, de modo que dos actualizaciones concurrentes con la misma versión
+// esperada no pueden aplicarse ambas.
+// Retorna repositories.ErrVersionConflict si la versión actual no es expectedVersion
+// y repositories.ErrNotFound si el item no existe.
+func (r *SQLiteItemRepository) UpdateVersioned(ctx context.Context, item *models.Item, expectedVersion int64) error {
+	found, err := r.update(ctx, item, "AND version = ?", expectedVersion)
+	if err != nil {
+		return err
+	}
+	if found {
+		return nil
+	}
+
+	var exists bool
+	if err := r.conn().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM items WHERE id = ?)", item.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check item existence: %w", err)
+	}
+	if !exists {
+		return repositories.ErrNotFound
+	}
+	return repositories.ErrVersionConflict
+}
+
+// update ejecuta el UPDATE del item con la condición adicional indicada y asigna
+// al item la nueva versión y las fechas de creación y modificación. Devuelve false si ninguna fila
+// cumplió la condición.
+func (r *SQLiteItemRepository) update(ctx context.Context, item *models.Item, condition string, conditionArgs ...interface{}) (bool, error) {
+	query := `
+		UPDATE items
+		SET name = ?, image_url = ?, description = ?, price = ?, currency = ?, rating = ?, specifications = ?, category_id = ?,
+			version = version + 1, updated_at = ` + nowSQL + `
+		WHERE id = ? ` + condition + `
+		RETURNING version, created_at, updated_at
+	`
+
+	specsJSON, err := marshalSpecifications(item.Specifications)
+	if err != nil {
+		return false, err
+	}
+	setDefaultCurrency(item)
+
+	args := []interface{}{
+		item.Name,
+		item.ImageURL,
+		item.Description,
+		item.Price,
+		item.Currency,
+		item.Rating,
+		specsJSON,
+		item.CategoryID,
+		item.ID,
+	}
+
+	var createdAt, updatedAt string
+	err = r.writeItems(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, append(args, conditionArgs...)...).Scan(&item.Version, &createdAt, &updatedAt)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	return true, setTimestamps(item, createdAt, updatedAt)
+}
+
+// Delete elimina el item con el ID indicado.
+// Retorna repositories.ErrNotFound si el item no existe.
+func (r *SQLiteItemRepository) Delete(ctx context.Context, id int64) error {
+	var result sql.Result
+	err := r.writeItems(ctx, func(q queryer) error {
+		var err error
+		result, err = q.ExecContext(ctx, "DELETE FROM items WHERE id = ?", id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+
+	return checkRowsAffected(result.RowsAffected())
+}
+
+// marshalSpecifications serializa las especificaciones a JSON.
+// Un mapa nil se guarda como objeto vacío porque la columna es NOT NULL.
+func marshalSpecifications(specs models.Specifications) ([]byte, error) {
+	if specs == nil {
+		specs = models.Specifications{}
+	}
+
+	specsJSON, err := json.Marshal(specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal specifications: %w", err)
+	}
+
+	return specsJSON, nil
+}
+
+// setTimestamps interpreta las fechas de creación y modificación devueltas por
+// SQLite y las asigna al item.
+func setTimestamps(item *models.Item, createdAt, updatedAt string) error {
+	var err error
+	if item.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return err
+	}
+	item.UpdatedAt, err = parseTimestamp(updatedAt)
+	return err
+}
+
+// parseTimestamp interpreta una fecha almacenada con el formato de timestampLayout.
+func parseTimestamp(value string) (time.Time, error) {
+	parsed, err := time.Parse(timestampLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse timestamp %q: %w", value, err)
+	}
+	return parsed, nil
+}
+
+// checkRowsAffected traduce "cero filas afectadas" a repositories.ErrNotFound.
+func checkRowsAffected(affected int64, err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// setDefaultCurrency asigna models.DefaultCurrency al item si no tiene moneda, igual
+// que el valor por defecto de la columna.
+func setDefaultCurrency(item *models.Item) {
+	if item.Currency == "" {
+		item.Currency = models.DefaultCurrency
+	}
+}
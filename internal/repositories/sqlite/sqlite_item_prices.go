@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"project/internal/models"
+	"strings"
+	"time"
+)
+
+// PriceHistory devuelve los precios del item registrados desde since, precedidos
+// por el vigente en since si es anterior, ordenados cronológicamente.
+func (r *SQLiteItemRepository) PriceHistory(ctx context.Context, itemID int64, since time.Time) ([]models.PricePoint, error) {
+	sinceValue := since.UTC().Format(timestampLayout)
+
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT price, currency, recorded_at FROM (
+			SELECT id, price, currency, recorded_at
+			FROM item_prices
+			WHERE item_id = ? AND recorded_at >= ?
+			UNION ALL
+			SELECT * FROM (
+				SELECT id, price, currency, recorded_at
+				FROM item_prices
+				WHERE item_id = ? AND recorded_at < ?
+				ORDER BY recorded_at DESC, id DESC
+				LIMIT 1
+			)
+		)
+		ORDER BY recorded_at, id
+	`, itemID, sinceValue, itemID, sinceValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %w", err)
+	}
+	defer rows.Close()
+
+	points := []models.PricePoint{}
+	for rows.Next() {
+		var point models.PricePoint
+		var recordedAt string
+		if err := rows.Scan(&point.Price, &point.Currency, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price history: %w", err)
+		}
+		if point.RecordedAt, err = parseTimestamp(recordedAt); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price history: %w", err)
+	}
+
+	return points, nil
+}
+
+// LowestPrices devuelve, por ID de item y por moneda, el precio más bajo vigente
+// desde since: el mínimo entre los registrados desde entonces y el que regía en since.
+func (r *SQLiteItemRepository) LowestPrices(ctx context.Context, itemIDs []int64, since time.Time) (map[int64]map[string]float64, error) {
+	lowest := make(map[int64]map[string]float64, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return lowest, nil
+	}
+
+	sinceValue := since.UTC().Format(timestampLayout)
+	args := make([]interface{}, 0, len(itemIDs)+2)
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	args = append(args, sinceValue, sinceValue)
+
+	query := fmt.Sprintf(`
+		SELECT p.item_id, p.currency, MIN(p.price)
+		FROM item_prices p
+		WHERE p.item_id IN (%s)
+			AND (p.recorded_at >= ? OR p.id = (
+				SELECT q.id
+				FROM item_prices q
+				WHERE q.item_id = p.item_id AND q.recorded_at < ?
+				ORDER BY q.recorded_at DESC, q.id DESC
+				LIMIT 1
+			))
+		GROUP BY p.item_id, p.currency
+	`, strings.TrimSuffix(strings.Repeat("?,", len(itemIDs)), ","))
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lowest prices: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var currency string
+		var price float64
+		if err := rows.Scan(&id, &currency, &price); err != nil {
+			return nil, fmt.Errorf("failed to scan lowest price: %w", err)
+		}
+		if lowest[id] == nil {
+			lowest[id] = make(map[string]float64)
+		}
+		lowest[id][currency] = price
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lowest prices: %w", err)
+	}
+
+	return lowest, nil
+}
@@ -7,46 +7,112 @@ import (
 	"fmt"
 	"project/internal/models"
 	"project/internal/repositories"
+	"sort"
+	"strings"
 )
 
-// GetAll recupera todos los items almacenados en la base de datos.
-// Ejecuta una consulta SQL, escanea los resultados y convierte el JSON
+// itemColumns enumera las columnas seleccionadas en todas las consultas de items,
+// en el mismo orden que espera scanItem.
+const itemColumns = "id, name, image_url, description, price, currency, rating, specifications, category_id, version, created_at, updated_at"
+
+// timestampLayout es el formato de las columnas de fecha, que SQLite genera con
+// nowSQL: UTC con milisegundos y ancho fijo, para que se puedan comparar como texto.
+const timestampLayout = "2006-01-02T15:04:05.000Z"
+
+// nowSQL es la expresión SQL de la fecha actual en el formato de timestampLayout.
+const nowSQL = "strftime('%Y-%m-%dT%H:%M:%fZ', 'now')"
+
+// sortColumns traduce cada uno de models.SortFields a su columna SQL. Los campos
+// que no están en este mapa se ignoran, lo que evita inyectar SQL a través del
+// parámetro sort.
+var sortColumns = map[string]string{
+	models.SortByID:     "id",
+	models.SortByName:   "name COLLATE NOCASE",
+	models.SortByPrice:  "price",
+	models.SortByRating: "rating",
+}
+
+// rowScanner abstrae *sql.Row y *sql.Rows para reutilizar scanItem.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanItem lee una fila con las columnas de itemColumns y convierte el JSON
 // almacenado en la columna `specifications` a un mapa Go.
-func (r *SQLiteItemRepository) GetAll(ctx context.Context) ([]models.Item, error) {
-	query := `
-		SELECT id, name, image_url, description, price, rating, specifications
+// Las columnas adicionales seleccionadas después de itemColumns se escanean en extra.
+func scanItem(row rowScanner, extra ...interface{}) (models.Item, error) {
+	var item models.Item
+	var specsJSON string
+	var categoryID sql.NullInt64
+	var createdAt, updatedAt string
+
+	dest := []interface{}{
+		&item.ID,
+		&item.Name,
+		&item.ImageURL,
+		&item.Description,
+		&item.Price,
+		&item.Currency,
+		&item.Rating,
+		&specsJSON,
+		&categoryID,
+		&item.Version,
+		&createdAt,
+		&updatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return item, err
+	}
+
+	if categoryID.Valid {
+		item.CategoryID = &categoryID.Int64
+	}
+
+	if err := setTimestamps(&item, createdAt, updatedAt); err != nil {
+		return item, err
+	}
+
+	if err := json.Unmarshal([]byte(specsJSON), &item.Specifications); err != nil {
+		return item, fmt.Errorf("error al deserializar las especificaciones: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetAll recupera los items que cumplen los filtros de la consulta.
+// Los filtros, el ordenamiento y la paginación se resuelven en SQL para no
+// cargar el catálogo completo en memoria.
+func (r *SQLiteItemRepository) GetAll(ctx context.Context, query models.ItemQuery) ([]models.Item, error) {
+	where, args := buildItemFilter(query)
+	orderBy, orderArgs := buildItemOrderBy(query)
+	args = append(args, orderArgs...)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT %s
 		FROM items
-		ORDER BY id
-	`
+		%s
+		ORDER BY %s
+	`, itemColumns, where, orderBy)
+
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	}
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := r.conn().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query items: %w", err)
 	}
 	defer rows.Close()
 
-	var items []models.Item
+	items := []models.Item{}
 
 	for rows.Next() {
-		var item models.Item
-		var specsJSON string
-
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.ImageURL,
-			&item.Description,
-			&item.Price,
-			&item.Rating,
-			&specsJSON,
-		); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
-
-		if err := json.Unmarshal([]byte(specsJSON), &item.Specifications); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal specifications: %w", err)
-		}
-
 		items = append(items, item)
 	}
 
@@ -57,29 +123,261 @@ func (r *SQLiteItemRepository) GetAll(ctx context.Context) ([]models.Item, error
 	return items, nil
 }
 
+// Stream recorre con un cursor los items que cumplen los filtros de la consulta,
+// en el orden indicado, y llama a fn con cada uno. A diferencia de GetAll, los items
+// no se acumulan en memoria, por lo que sirve para exportar el catálogo completo.
+// La paginación de la consulta no se aplica. Si fn devuelve un error, el recorrido
+// se interrumpe y Stream devuelve ese mismo error.
+func (r *SQLiteItemRepository) Stream(ctx context.Context, query models.ItemQuery, fn func(item models.Item) error) error {
+	where, args := buildItemFilter(query)
+	orderBy, orderArgs := buildItemOrderBy(query)
+	args = append(args, orderArgs...)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM items
+		%s
+		ORDER BY %s
+	`, itemColumns, where, orderBy)
+
+	rows, err := r.conn().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+// CatalogState devuelve la versión del catálogo, que los triggers de la tabla items
+// incrementan ante cualquier alta, modificación o baja.
+func (r *SQLiteItemRepository) CatalogState(ctx context.Context) (*models.CatalogState, error) {
+	var state models.CatalogState
+	var updatedAt string
+
+	err := r.conn().QueryRowContext(ctx, "SELECT version, updated_at FROM catalog_state WHERE id = 1").
+		Scan(&state.Version, &updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog state: %w", err)
+	}
+
+	if state.UpdatedAt, err = parseTimestamp(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// Count devuelve la cantidad total de items que cumplen los filtros de la consulta.
+func (r *SQLiteItemRepository) Count(ctx context.Context, query models.ItemQuery) (int, error) {
+	where, args := buildItemFilter(query)
+
+	var total int
+	if err := r.conn().QueryRowContext(ctx, "SELECT COUNT(*) FROM items "+where, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("error al contar los items: %w", err)
+	}
+
+	return total, nil
+}
+
+// buildItemFilter construye la cláusula WHERE y sus argumentos a partir de los
+// filtros de la consulta. Todos los valores se pasan como parámetros.
+func buildItemFilter(query models.ItemQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if query.MinPrice != nil {
+		price, priceArgs := priceExpression(query.PriceRates)
+		conditions = append(conditions, price+" >= ?")
+		args = append(append(args, priceArgs...), *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		price, priceArgs := priceExpression(query.PriceRates)
+		conditions = append(conditions, price+" <= ?")
+		args = append(append(args, priceArgs...), *query.MaxPrice)
+	}
+	if query.MinRating != nil {
+		conditions = append(conditions, "rating >= ?")
+		args = append(args, *query.MinRating)
+	}
+	if query.MinID != nil {
+		conditions = append(conditions, "id >= ?")
+		args = append(args, *query.MinID)
+	}
+	if query.MaxID != nil {
+		conditions = append(conditions, "id <= ?")
+		args = append(args, *query.MaxID)
+	}
+	if query.CategoryID != nil {
+		conditions = append(conditions, "category_id IN ("+categorySubtreeSQL+")")
+		args = append(args, *query.CategoryID)
+	}
+
+	for _, filter := range query.SpecFilters {
+		condition, filterArgs := buildSpecCondition(filter)
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// categorySubtreeSQL selecciona el ID de una categoría (primer parámetro) y los de
+// todas sus subcategorías. UNION descarta repetidos, lo que corta posibles ciclos.
+const categorySubtreeSQL = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT ?
+		UNION
+		SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+	)
+	SELECT id FROM subtree`
+
+// specNumericOperators traduce los operadores numéricos a SQL.
+var specNumericOperators = map[models.SpecOperator]string{
+	models.SpecOpLess:         "<",
+	models.SpecOpLessEqual:    "<=",
+	models.SpecOpGreater:      ">",
+	models.SpecOpGreaterEqual: ">=",
+}
+
+// buildSpecCondition construye la condición SQL de un filtro de especificación
+// usando json_extract. La ruta JSON y el valor se pasan como parámetros.
+//
+// Las comparaciones de texto no distinguen mayúsculas. Las numéricas comparan el
+// valor almacenado y el del filtro en la unidad canónica de su dimensión (ver
+// spec_quantity), así que spec.weight_lt=2kg incluye "385 g". Si el filtro lleva
+// unidad, los valores de otra dimensión o no cuantificables no lo cumplen; un número
+// sin unidad se compara con el valor canónico de cualquier dimensión.
+func buildSpecCondition(filter models.SpecFilter) (string, []interface{}) {
+	path := `$."` + filter.Key + `"`
+
+	switch filter.Operator {
+	case models.SpecOpContains:
+		return "instr(lower(json_extract(specifications, ?)), lower(?)) > 0", []interface{}{path, filter.Value}
+	case models.SpecOpLess, models.SpecOpLessEqual, models.SpecOpGreater, models.SpecOpGreaterEqual:
+		quantity, _ := filter.Quantity()
+		dimension := ""
+		if quantity.Dimension != models.DimensionNumber {
+			dimension = string(quantity.Dimension)
+		}
+		condition := fmt.Sprintf(
+			"spec_quantity(json_extract(specifications, ?), ?) %s ?",
+			specNumericOperators[filter.Operator],
+		)
+		return condition, []interface{}{path, dimension, quantity.Value}
+	default:
+		return "lower(json_extract(specifications, ?)) = lower(?)", []interface{}{path, filter.Value}
+	}
+}
+
+// SpecKeys devuelve las claves de especificación distintas presentes en el catálogo,
+// ordenadas alfabéticamente.
+func (r *SQLiteItemRepository) SpecKeys(ctx context.Context) ([]string, error) {
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT DISTINCT specs.key
+		FROM items, json_each(items.specifications) AS specs
+		ORDER BY specs.key
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar las claves de especificación: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("error al escanear la clave de especificación: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar las claves de especificación: %w", err)
+	}
+
+	return keys, nil
+}
+
+// buildItemOrderBy construye la cláusula ORDER BY y sus argumentos. Siempre añade
+// el id como desempate para que la paginación sea estable.
+func buildItemOrderBy(query models.ItemQuery) (string, []interface{}) {
+	clauses := make([]string, 0, len(query.Sort)+1)
+	var args []interface{}
+
+	for _, field := range query.Sort {
+		column, ok := sortColumns[field.Field]
+		if !ok {
+			continue
+		}
+		if field.Field == models.SortByPrice {
+			var priceArgs []interface{}
+			column, priceArgs = priceExpression(query.PriceRates)
+			args = append(args, priceArgs...)
+		}
+		if field.Desc {
+			column += " DESC"
+		}
+		clauses = append(clauses, column)
+	}
+
+	clauses = append(clauses, "id")
+	return strings.Join(clauses, ", "), args
+}
+
+// priceExpression devuelve la expresión SQL del precio convertido con los factores
+// de rates, por moneda, y sus argumentos. Sin factores, es la columna price.
+func priceExpression(rates map[string]float64) (string, []interface{}) {
+	if len(rates) == 0 {
+		return "price", nil
+	}
+
+	codes := make([]string, 0, len(rates))
+	for code := range rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var expr strings.Builder
+	args := make([]interface{}, 0, 2*len(codes))
+	expr.WriteString("(price * CASE currency")
+	for _, code := range codes {
+		expr.WriteString(" WHEN ? THEN ?")
+		args = append(args, code, rates[code])
+	}
+	expr.WriteString(" END)")
+	return expr.String(), args
+}
+
 // GetByID obtiene un item específico buscándolo por su ID.
 // Retorna nil si no se encuentra un registro con el ID dado.
 // Si existe, convierte el JSON de specifications y devuelve el item completo.
 func (r *SQLiteItemRepository) GetByID(ctx context.Context, id int64) (*models.Item, error) {
-	query := `
-		SELECT id, name, image_url, description, price, rating, specifications
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM items
 		WHERE id = ?
-	`
-
-	var item models.Item
-	var specsJSON string
-
-	err := r.DB.QueryRowContext(ctx, query, id).Scan(
-		&item.ID,
-		&item.Name,
-		&item.ImageURL,
-		&item.Description,
-		&item.Price,
-		&item.Rating,
-		&specsJSON,
-	)
+	`, itemColumns)
 
+	item, err := scanItem(r.conn().QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Traducimos el error de la DB a un error de repositorio
@@ -88,10 +386,6 @@ func (r *SQLiteItemRepository) GetByID(ctx context.Context, id int64) (*models.I
 		return nil, fmt.Errorf("error al consultar item: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(specsJSON), &item.Specifications); err != nil {
-		return nil, fmt.Errorf("error al deserializar las especificaciones: %w", err)
-	}
-
 	return &item, nil
 }
 
@@ -114,18 +408,18 @@ func (r *SQLiteItemRepository) GetByIDs(ctx context.Context, ids []int64) ([]mod
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, name, image_url, description, price, rating, specifications
+		SELECT %s
 		FROM items
 		WHERE id IN (%s)
 		ORDER BY id
-	`, placeholders)
+	`, itemColumns, placeholders)
 
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
 		args[i] = id
 	}
 
-	rows, err := r.DB.QueryContext(ctx, query, args...)
+	rows, err := r.conn().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error al consultar los items: %w", err)
 	}
@@ -134,25 +428,11 @@ func (r *SQLiteItemRepository) GetByIDs(ctx context.Context, ids []int64) ([]mod
 	var items []models.Item
 
 	for rows.Next() {
-		var item models.Item
-		var specsJSON string
-
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.ImageURL,
-			&item.Description,
-			&item.Price,
-			&item.Rating,
-			&specsJSON,
-		); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error al escanear el ítem: %w", err)
 		}
 
-		if err := json.Unmarshal([]byte(specsJSON), &item.Specifications); err != nil {
-			return nil, fmt.Errorf("error al deserializar las especificaciones: %w", err)
-		}
-
 		items = append(items, item)
 	}
 
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project/internal/models"
+	"project/internal/repositories"
+)
+
+// revisionColumns enumera las columnas seleccionadas en las consultas del historial,
+// en el mismo orden que espera scanRevision.
+const revisionColumns = "item_id, revision, operation, actor, source, changed_at, old_values, new_values"
+
+// writeItems ejecuta fn, que escribe en la tabla items, dentro de una transacción
+// (la del repositorio o una nueva) con el autor de ctx guardado en revision_context,
+// de donde lo toman los triggers del historial de revisiones.
+func (r *SQLiteItemRepository) writeItems(ctx context.Context, fn func(q queryer) error) error {
+	if r.tx != nil {
+		return withRevisionContext(ctx, r.tx, models.ActorFromContext(ctx), fn)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := withRevisionContext(ctx, tx, models.ActorFromContext(ctx), fn); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withRevisionContext guarda el autor en revision_context, ejecuta fn y vuelve a
+// vaciar la tabla, también si fn falla, para que el autor no se atribuya a
+// escrituras posteriores de la misma transacción. q debe ser una transacción.
+func withRevisionContext(ctx context.Context, q queryer, actor models.Actor, fn func(q queryer) error) error {
+	if _, err := q.ExecContext(ctx,
+		"INSERT OR REPLACE INTO revision_context (id, actor, source) VALUES (1, ?, ?)", actor.Name, actor.Source,
+	); err != nil {
+		return fmt.Errorf("failed to set revision context: %w", err)
+	}
+
+	fnErr := fn(q)
+
+	if _, err := q.ExecContext(ctx, "DELETE FROM revision_context"); err != nil && fnErr == nil {
+		return fmt.Errorf("failed to clear revision context: %w", err)
+	}
+	return fnErr
+}
+
+// Revisions devuelve el historial de revisiones del item, de la más antigua a la
+// más reciente. Incluye el de los items eliminados.
+func (r *SQLiteItemRepository) Revisions(ctx context.Context, itemID int64) ([]models.ItemRevision, error) {
+	rows, err := r.conn().QueryContext(ctx,
+		"SELECT "+revisionColumns+" FROM item_revisions WHERE item_id = ? ORDER BY revision", itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query item revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ItemRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating item revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// Revision devuelve una revisión del item.
+// Retorna repositories.ErrNotFound si no existe.
+func (r *SQLiteItemRepository) Revision(ctx context.Context, itemID, revision int64) (*models.ItemRevision, error) {
+	row := r.conn().QueryRowContext(ctx,
+		"SELECT "+revisionColumns+" FROM item_revisions WHERE item_id = ? AND revision = ?", itemID, revision,
+	)
+
+	result, err := scanRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// scanRevision lee una fila con las columnas de revisionColumns.
+func scanRevision(row rowScanner) (models.ItemRevision, error) {
+	var revision models.ItemRevision
+	var actor, source, oldValues, newValues sql.NullString
+	var changedAt string
+
+	err := row.Scan(
+		&revision.ItemID,
+		&revision.Revision,
+		&revision.Operation,
+		&actor,
+		&source,
+		&changedAt,
+		&oldValues,
+		&newValues,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return revision, err
+		}
+		return revision, fmt.Errorf("failed to scan item revision: %w", err)
+	}
+
+	revision.Actor = actor.String
+	revision.Source = source.String
+	if revision.ChangedAt, err = parseTimestamp(changedAt); err != nil {
+		return revision, err
+	}
+	if revision.OldValues, err = unmarshalSnapshot(oldValues); err != nil {
+		return revision, err
+	}
+	if revision.NewValues, err = unmarshalSnapshot(newValues); err != nil {
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+// unmarshalSnapshot decodifica los valores JSON de una revisión. NULL es nil.
+func unmarshalSnapshot(value sql.NullString) (*models.ItemSnapshot, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	var snapshot models.ItemSnapshot
+	if err := json.Unmarshal([]byte(value.String), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision values: %w", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,334 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"project/internal/models"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Marcadores usados para resaltar los términos encontrados en nombres y snippets.
+const (
+	highlightOpen   = "<mark>"
+	highlightClose  = "</mark>"
+	snippetEllipsis = "…"
+)
+
+// maxSearchTerms limita la cantidad de términos considerados en una búsqueda.
+const maxSearchTerms = 10
+
+// Pesos de relevancia por columna: nombre, descripción y especificaciones.
+// Se usan tanto en bm25() como en la búsqueda alternativa sin FTS5.
+const (
+	nameWeight        = 10.0
+	descriptionWeight = 5.0
+	specsWeight       = 1.0
+)
+
+// flattenedSpecsSQL devuelve una expresión SQL que aplana el JSON de
+// especificaciones de la fila indicada a texto plano ("clave valor clave valor").
+func flattenedSpecsSQL(row string) string {
+	return fmt.Sprintf(
+		"(SELECT COALESCE(group_concat(key || ' ' || value, ' '), '') FROM json_each(%s.specifications))",
+		row,
+	)
+}
+
+// SearchIndexEnabled indica si la búsqueda usa el índice FTS5 o, si el driver no
+// incluye FTS5, la búsqueda alternativa con LIKE.
+func (r *SQLiteItemRepository) SearchIndexEnabled() bool {
+	return r.ftsEnabled
+}
+
+// initSearchIndex crea la tabla virtual FTS5 items_fts y los triggers que la
+// mantienen sincronizada con la tabla items.
+//
+// FTS5 solo está disponible cuando el driver se compila con el build tag
+// `sqlite_fts5`. Si no lo está, la búsqueda usa un modo alternativo basado en
+// LIKE (ver searchLike) y este método no crea nada.
+func (r *SQLiteItemRepository) initSearchIndex(ctx context.Context) error {
+	var enabled int
+	if err := r.DB.QueryRowContext(ctx, "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return fmt.Errorf("failed to detect FTS5 support: %w", err)
+	}
+
+	r.ftsEnabled = enabled == 1
+	if !r.ftsEnabled {
+		return nil
+	}
+
+	var exists int
+	if err := r.DB.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'items_fts'",
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
+
+	insertNew := fmt.Sprintf(`
+		INSERT INTO items_fts (rowid, name, description, specifications)
+		VALUES (new.id, new.name, new.description, %s);
+	`, flattenedSpecsSQL("new"))
+
+	statements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS items_fts USING fts5(
+			name,
+			description,
+			specifications,
+			tokenize = 'unicode61 remove_diacritics 2'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS items_fts_ai AFTER INSERT ON items BEGIN` + insertNew + `END`,
+		`CREATE TRIGGER IF NOT EXISTS items_fts_ad AFTER DELETE ON items BEGIN
+			DELETE FROM items_fts WHERE rowid = old.id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS items_fts_au AFTER UPDATE ON items BEGIN
+			DELETE FROM items_fts WHERE rowid = old.id;` + insertNew + `END`,
+	}
+
+	for _, statement := range statements {
+		if _, err := r.DB.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	// Si el índice se acaba de crear, se indexan los items existentes.
+	if exists == 0 {
+		backfill := fmt.Sprintf(`
+			INSERT INTO items_fts (rowid, name, description, specifications)
+			SELECT items.id, items.name, items.description, %s FROM items
+		`, flattenedSpecsSQL("items"))
+
+		if _, err := r.DB.ExecContext(ctx, backfill); err != nil {
+			return fmt.Errorf("failed to populate search index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Search busca items cuyo nombre, descripción o especificaciones contengan
+// todos los términos del texto (coincidencia por prefijo).
+// Los resultados se ordenan por relevancia, de mayor a menor.
+func (r *SQLiteItemRepository) Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	if r.ftsEnabled {
+		return r.searchFTS(ctx, terms, limit)
+	}
+	return r.searchLike(ctx, terms, limit)
+}
+
+// searchFTS ejecuta la búsqueda sobre la tabla FTS5, usando bm25() para el
+// ranking y snippet()/highlight() para resaltar los términos.
+func (r *SQLiteItemRepository) searchFTS(ctx context.Context, terms []string, limit int) ([]models.SearchResult, error) {
+	matchTerms := make([]string, len(terms))
+	for i, term := range terms {
+		matchTerms[i] = `"` + term + `"*`
+	}
+
+	query := fmt.Sprintf(`
+		SELECT items.id, items.name, items.image_url, items.description, items.price, items.currency, items.rating, items.specifications, items.category_id,
+			items.version, items.created_at, items.updated_at,
+			-bm25(items_fts, %[1]g, %[2]g, %[3]g) AS score,
+			highlight(items_fts, 0, '%[4]s', '%[5]s'),
+			snippet(items_fts, -1, '%[4]s', '%[5]s', '%[6]s', 12)
+		FROM items_fts
+		JOIN items ON items.id = items_fts.rowid
+		WHERE items_fts MATCH ?
+		ORDER BY score DESC, items.id
+		LIMIT ?
+	`, nameWeight, descriptionWeight, specsWeight, highlightOpen, highlightClose, snippetEllipsis)
+
+	rows, err := r.conn().QueryContext(ctx, query, strings.Join(matchTerms, " "), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar items: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+
+	for rows.Next() {
+		var result models.SearchResult
+		item, err := scanItem(rows, &result.Score, &result.HighlightedName, &result.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear el resultado de búsqueda: %w", err)
+		}
+		result.Item = item
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar los resultados de búsqueda: %w", err)
+	}
+
+	return results, nil
+}
+
+// searchLike es la búsqueda alternativa usada cuando el driver no incluye FTS5.
+// Filtra con LIKE en SQL y calcula la relevancia y los snippets en Go usando
+// los mismos pesos por columna que la búsqueda FTS5.
+func (r *SQLiteItemRepository) searchLike(ctx context.Context, terms []string, limit int) ([]models.SearchResult, error) {
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, 0, len(terms)*3)
+	for i, term := range terms {
+		conditions[i] = `(name LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\' OR specs_text LIKE ? ESCAPE '\')`
+		pattern := "%" + escapeLike(term) + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	query := fmt.Sprintf(`
+		WITH docs AS (
+			SELECT items.*, %s AS specs_text FROM items
+		)
+		SELECT %s, specs_text
+		FROM docs
+		WHERE %s
+	`, flattenedSpecsSQL("items"), itemColumns, strings.Join(conditions, " AND "))
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar items: %w", err)
+	}
+	defer rows.Close()
+
+	highlighter := termsRegexp(terms)
+	results := []models.SearchResult{}
+
+	for rows.Next() {
+		var specsText string
+		item, err := scanItem(rows, &specsText)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear el resultado de búsqueda: %w", err)
+		}
+
+		results = append(results, models.SearchResult{
+			Item:            item,
+			Score:           likeScore(item, specsText, terms),
+			HighlightedName: highlighter.ReplaceAllString(item.Name, highlightOpen+"$0"+highlightClose),
+			Snippet:         buildSnippet(item, specsText, highlighter),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar los resultados de búsqueda: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Item.ID < results[j].Item.ID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// searchTerms divide el texto de búsqueda en términos alfanuméricos en minúsculas,
+// sin duplicados. Al descartar cualquier otro carácter, los términos pueden usarse
+// de forma segura en la sintaxis de consulta de FTS5 y en patrones LIKE.
+func searchTerms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		terms = append(terms, field)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// likeEscaper antepone '\' a los comodines de LIKE y al propio carácter de escape,
+// para que un término se busque literalmente con ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapa los comodines de LIKE de un término de búsqueda.
+func escapeLike(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+// termsRegexp construye una expresión regular que encuentra palabras que
+// comienzan con alguno de los términos, sin distinguir mayúsculas.
+func termsRegexp(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)[\p{L}\p{N}]*`)
+}
+
+// likeScore calcula la relevancia de un item sumando el peso de cada columna
+// en la que aparece cada término.
+func likeScore(item models.Item, specsText string, terms []string) float64 {
+	name := strings.ToLower(item.Name)
+	description := strings.ToLower(item.Description)
+	specs := strings.ToLower(specsText)
+
+	var score float64
+	for _, term := range terms {
+		if strings.Contains(name, term) {
+			score += nameWeight
+		}
+		if strings.Contains(description, term) {
+			score += descriptionWeight
+		}
+		if strings.Contains(specs, term) {
+			score += specsWeight
+		}
+	}
+	return score
+}
+
+// buildSnippet devuelve un fragmento de la descripción (o de las especificaciones)
+// alrededor de la primera coincidencia, con los términos resaltados.
+func buildSnippet(item models.Item, specsText string, highlighter *regexp.Regexp) string {
+	const window = 40
+
+	for _, text := range []string{item.Description, specsText, item.Name} {
+		loc := highlighter.FindStringIndex(text)
+		if loc == nil {
+			continue
+		}
+
+		runes := []rune(text)
+		matchStart := len([]rune(text[:loc[0]]))
+		start := matchStart - window
+		if start < 0 {
+			start = 0
+		}
+		end := matchStart + window
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		fragment := highlighter.ReplaceAllString(string(runes[start:end]), highlightOpen+"$0"+highlightClose)
+		if start > 0 {
+			fragment = snippetEllipsis + fragment
+		}
+		if end < len(runes) {
+			fragment += snippetEllipsis
+		}
+		return fragment
+	}
+
+	return ""
+}
@@ -2,138 +2,318 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"project/internal/models"
+	"strings"
 )
 
-// Seed inserta datos iniciales en la base de datos si aún no existen.
-
+// Seed carga datos iniciales desde fixtures JSON o YAML y devuelve cuántos items
+// se insertaron, actualizaron y omitieron.
+//
 // Flujo del proceso:
-// 1. Verifica si la tabla items ya contiene datos.
-// 2. Si está vacía, construye una lista de items de ejemplo.
-// 3. Serializa el campo Specifications a JSON para almacenarlo correctamente.
-// 4. Inserta cada item en la base de datos usando SQL parametrizado.
-func (r *SQLiteItemRepository) Seed(ctx context.Context) error {
-	// Check if data already exists
-	var count int
-	if err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM items").Scan(&count); err != nil {
-		return fmt.Errorf("failed to check existing data: %w", err)
+// 1. Lee y valida todos los fixtures antes de escribir nada. Sin fixtures se usa el
+// catálogo de ejemplo embebido, solo si la tabla items está vacía.
+// 2. Dentro de una única transacción, inserta o actualiza las categorías (por slug)
+// y su esquema de especificaciones (por categoría y clave).
+// 3. Valida cada item contra el esquema de su categoría.
+// 4. Inserta los items nuevos y actualiza los existentes con el mismo nombre; los
+// que no cambian se omiten. Los cambios quedan en el historial de revisiones con
+// origen models.SourceSeed.
+func (r *SQLiteItemRepository) Seed(ctx context.Context, fixturePaths ...string) (*models.SeedReport, error) {
+	fixtures := make([]*Fixture, 0, len(fixturePaths))
+	for _, path := range fixturePaths {
+		fixture, err := LoadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	if len(fixtures) == 0 {
+		var count int
+		if err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM items").Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to check existing data: %w", err)
+		}
+		if count > 0 {
+			return &models.SeedReport{}, nil
+		}
+
+		fixture, err := parseFixture(defaultFixtureName, defaultFixture)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin seed: %w", err)
 	}
+	defer tx.Rollback()
 
-	if count > 0 {
+	report := &models.SeedReport{}
+	actor := models.ActorFromContext(ctx)
+	actor.Source = models.SourceSeed
+	err = withRevisionContext(ctx, tx, actor, func(queryer) error {
+		for _, fixture := range fixtures {
+			if err := seedFixture(ctx, tx, fixture, report); err != nil {
+				return err
+			}
+		}
 		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit seed: %w", err)
+	}
+
+	return report, nil
+}
+
+// seedFixture carga las categorías y luego los items de un fixture.
+func seedFixture(ctx context.Context, tx *sql.Tx, fixture *Fixture, report *models.SeedReport) error {
+	for _, category := range fixture.Categories {
+		if err := upsertSeedCategory(ctx, tx, category); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range fixture.Items {
+		if err := upsertSeedItem(ctx, tx, item, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsertSeedCategory inserta o actualiza una categoría por slug junto con sus campos
+// de especificación. Los campos que ya existían y no aparecen en el fixture se conservan.
+func upsertSeedCategory(ctx context.Context, tx *sql.Tx, category FixtureCategory) error {
+	var parentID *int64
+	if category.Parent != "" {
+		id, err := categoryIDBySlug(ctx, tx, category.Parent)
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			return fmt.Errorf("invalid seed category %q: unknown parent %q", category.Slug, category.Parent)
+		}
+		parentID = id
 	}
 
-	seedItems := []models.Item{
-		{
-			Name:        "MacBook Pro 16\"",
-			ImageURL:    "https://example.com/images/macbook-pro.jpg",
-			Description: "Powerful laptop for professionals with M2 Pro chip",
-			Price:       2499.99,
-			Rating:      4.8,
-			Specifications: models.Specifications{
-				"processor":    "Apple M2 Pro",
-				"memory":       "16GB",
-				"storage":      "512GB SSD",
-				"display":      "16.2-inch Liquid Retina XDR",
-				"graphics":     "19-core GPU",
-				"battery_life": "Up to 22 hours",
-				"weight":       "2.15 kg",
-			},
-		},
-		{
-			Name:        "Dell XPS 15",
-			ImageURL:    "https://example.com/images/dell-xps.jpg",
-			Description: "Premium Windows laptop with stunning display",
-			Price:       1899.99,
-			Rating:      4.6,
-			Specifications: models.Specifications{
-				"processor":    "Intel Core i7-13700H",
-				"memory":       "32GB",
-				"storage":      "1TB SSD",
-				"display":      "15.6-inch OLED 3.5K",
-				"graphics":     "NVIDIA RTX 4050",
-				"battery_life": "Up to 10 hours",
-				"weight":       "1.92 kg",
-			},
-		},
-		{
-			Name:        "HP Spectre x360",
-			ImageURL:    "https://example.com/images/hp-spectre.jpg",
-			Description: "Versatile 2-in-1 convertible laptop",
-			Price:       1499.99,
-			Rating:      4.5,
-			Specifications: models.Specifications{
-				"processor":    "Intel Core i7-1355U",
-				"memory":       "16GB",
-				"storage":      "512GB SSD",
-				"display":      "13.5-inch OLED",
-				"graphics":     "Intel Iris Xe",
-				"battery_life": "Up to 17 hours",
-				"weight":       "1.36 kg",
-				"touchscreen":  "Yes",
-			},
-		},
-		{
-			Name:        "Lenovo ThinkPad X1 Carbon",
-			ImageURL:    "https://example.com/images/thinkpad.jpg",
-			Description: "Business-class laptop with exceptional keyboard",
-			Price:       1699.99,
-			Rating:      4.7,
-			Specifications: models.Specifications{
-				"processor":    "Intel Core i7-1355U",
-				"memory":       "16GB",
-				"storage":      "512GB SSD",
-				"display":      "14-inch WQXGA",
-				"graphics":     "Intel Iris Xe",
-				"battery_life": "Up to 15 hours",
-				"weight":       "1.12 kg",
-				"durability":   "MIL-STD tested",
-			},
-		},
-		{
-			Name:        "ASUS ROG Zephyrus G14",
-			ImageURL:    "https://example.com/images/asus-rog.jpg",
-			Description: "Gaming laptop with powerful GPU and compact design",
-			Price:       1599.99,
-			Rating:      4.4,
-			Specifications: models.Specifications{
-				"processor":    "AMD Ryzen 9 7940HS",
-				"memory":       "16GB",
-				"storage":      "1TB SSD",
-				"display":      "14-inch QHD 165Hz",
-				"graphics":     "NVIDIA RTX 4060",
-				"battery_life": "Up to 8 hours",
-				"weight":       "1.65 kg",
-				"rgb_keyboard": "Yes",
-			},
-		},
-	}
-
-	insertQuery := `
-		INSERT INTO items (name, image_url, description, price, rating, specifications)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`
-
-	for _, item := range seedItems {
-		specsJSON, err := json.Marshal(item.Specifications)
+	existingID, err := categoryIDBySlug(ctx, tx, category.Slug)
+	if err != nil {
+		return err
+	}
+	if existingID != nil && parentID != nil {
+		cycle, err := isCategoryAncestor(ctx, tx, *existingID, *parentID)
 		if err != nil {
-			return fmt.Errorf("failed to marshal specifications: %w", err)
-		}
-
-		if _, err := r.DB.ExecContext(
-			ctx,
-			insertQuery,
-			item.Name,
-			item.ImageURL,
-			item.Description,
-			item.Price,
-			item.Rating,
-			specsJSON,
-		); err != nil {
+			return err
+		}
+		if cycle {
+			return fmt.Errorf("invalid seed category %q: parent %q would create a cycle", category.Slug, category.Parent)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO categories (name, slug, parent_id) VALUES (?, ?, ?)
+		ON CONFLICT (slug) DO UPDATE SET name = excluded.name, parent_id = excluded.parent_id
+	`, strings.TrimSpace(category.Name), category.Slug, parentID); err != nil {
+		return fmt.Errorf("failed to upsert seed category: %w", err)
+	}
+
+	id, err := categoryIDBySlug(ctx, tx, category.Slug)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range category.SpecFields {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO spec_fields (category_id, key, label, type, dimension, required) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (category_id, key) DO UPDATE SET
+				label = excluded.label, type = excluded.type,
+				dimension = excluded.dimension, required = excluded.required
+		`, *id, field.Key, field.Label, field.Type, field.Dimension, field.Required); err != nil {
+			return fmt.Errorf("failed to upsert seed spec field: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// categoryIDBySlug devuelve el ID de la categoría con el slug indicado, o nil si no existe.
+func categoryIDBySlug(ctx context.Context, q queryer, slug string) (*int64, error) {
+	var id int64
+	err := q.QueryRowContext(ctx, "SELECT id FROM categories WHERE slug = ?", slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category %q: %w", slug, err)
+	}
+	return &id, nil
+}
+
+// isCategoryAncestor indica si ancestorID es categoryID o uno de sus ancestros.
+func isCategoryAncestor(ctx context.Context, q queryer, ancestorID, categoryID int64) (bool, error) {
+	var count int
+	err := q.QueryRowContext(ctx, `
+		WITH RECURSIVE ancestors(id) AS (
+			SELECT ?
+			UNION
+			SELECT categories.parent_id FROM categories JOIN ancestors ON categories.id = ancestors.id
+			WHERE categories.parent_id IS NOT NULL
+		)
+		SELECT COUNT(*) FROM ancestors WHERE id = ?
+	`, categoryID, ancestorID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check category ancestors: %w", err)
+	}
+	return count > 0, nil
+}
+
+// upsertSeedItem inserta el item o, si ya existe uno con el mismo nombre, lo actualiza.
+// Si el item existente es idéntico se cuenta como omitido.
+func upsertSeedItem(ctx context.Context, tx *sql.Tx, fixtureItem FixtureItem, report *models.SeedReport) error {
+	item := models.Item{
+		Name:           strings.TrimSpace(fixtureItem.Name),
+		ImageURL:       fixtureItem.ImageURL,
+		Description:    fixtureItem.Description,
+		Price:          fixtureItem.Price,
+		Currency:       models.NormalizeCurrency(fixtureItem.Currency),
+		Rating:         fixtureItem.Rating,
+		Specifications: fixtureItem.Specifications,
+	}
+	if item.Specifications == nil {
+		item.Specifications = models.Specifications{}
+	}
+	setDefaultCurrency(&item)
+
+	if fixtureItem.Category != "" {
+		id, err := categoryIDBySlug(ctx, tx, fixtureItem.Category)
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			return fmt.Errorf("invalid seed item %q: unknown category %q", item.Name, fixtureItem.Category)
+		}
+		item.CategoryID = id
+	}
+
+	if err := validateSeedItem(ctx, tx, item); err != nil {
+		return err
+	}
+
+	specsJSON, err := json.Marshal(item.Specifications)
+	if err != nil {
+		return fmt.Errorf("failed to marshal specifications: %w", err)
+	}
+
+	existing, err := seedItemByName(ctx, tx, item.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO items (name, image_url, description, price, currency, rating, specifications, category_id, version, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, `+nowSQL+`, `+nowSQL+`)
+		`, item.Name, item.ImageURL, item.Description, item.Price, item.Currency, item.Rating, specsJSON, item.CategoryID); err != nil {
 			return fmt.Errorf("failed to insert seed item: %w", err)
 		}
+		report.Inserted++
+		return nil
+	}
+
+	existingSpecs, err := json.Marshal(existing.Specifications)
+	if err != nil {
+		return fmt.Errorf("failed to marshal specifications: %w", err)
+	}
+
+	if existing.ImageURL == item.ImageURL &&
+		existing.Description == item.Description &&
+		existing.Price == item.Price &&
+		existing.Currency == item.Currency &&
+		existing.Rating == item.Rating &&
+		sameCategory(existing.CategoryID, item.CategoryID) &&
+		string(existingSpecs) == string(specsJSON) {
+		report.Skipped++
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE items
+		SET image_url = ?, description = ?, price = ?, currency = ?, rating = ?, specifications = ?, category_id = ?,
+			version = version + 1, updated_at = `+nowSQL+`
+		WHERE id = ?
+	`, item.ImageURL, item.Description, item.Price, item.Currency, item.Rating, specsJSON, item.CategoryID, existing.ID); err != nil {
+		return fmt.Errorf("failed to update seed item: %w", err)
+	}
+	report.Updated++
+	return nil
+}
+
+// seedItemByName busca el item identificado por el nombre. Devuelve nil si no existe
+// y un error si el nombre no identifica a un único item.
+func seedItemByName(ctx context.Context, q queryer, name string) (*models.Item, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM items WHERE name = ? ORDER BY id LIMIT 2", itemColumns), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seed item: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []models.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan seed item: %w", err)
+		}
+		matches = append(matches, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate seed items: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("invalid seed item %q: the name matches more than one existing item", name)
+	}
+}
+
+// sameCategory compara dos categorías opcionales.
+func sameCategory(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// validateSeedItem valida las especificaciones de un item de ejemplo contra el
+// esquema de su categoría, para que los datos iniciales cumplan las mismas
+// reglas que las escrituras de la API.
+func validateSeedItem(ctx context.Context, q queryer, item models.Item) error {
+	if item.CategoryID == nil {
+		return nil
+	}
+
+	fields, err := querySpecFields(ctx, q, *item.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	problems := models.ResolveSpecSchema(*item.CategoryID, fields).Validate(item.Specifications)
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid seed item %q: specifications.%s %s", item.Name, problems[0].Key, problems[0].Message)
 	}
 
 	return nil
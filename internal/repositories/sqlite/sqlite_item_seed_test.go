@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"project/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFixture escribe un fixture en un directorio temporal y devuelve su ruta.
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const baseFixtureJSON = `{
+	"categories": [
+		{"name": "Tablets", "slug": "tablets", "spec_fields": [
+			{"key": "storage", "label": "Storage", "type": "quantity", "dimension": "data_size", "required": true},
+			{"key": "stylus", "label": "Stylus", "type": "boolean"}
+		]}
+	],
+	"items": [
+		{"name": "iPad Air", "image_url": "https://example.com/ipad.jpg", "description": "Tablet",
+		 "price": 599, "rating": 4.6, "category": "tablets", "specifications": {"storage": "64GB", "stylus": "Yes"}},
+		{"name": "Galaxy Tab S9", "image_url": "https://example.com/tab.jpg", "description": "Tablet",
+		 "price": 799, "rating": 4.5, "category": "tablets", "specifications": {"storage": "128GB"}}
+	]
+}`
+
+// TestSeed_DefaultCatalogOnlyWhenEmpty: El catálogo de ejemplo no se vuelve a cargar
+// sobre una base de datos con items
+func TestSeed_DefaultCatalogOnlyWhenEmpty(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	report, err := repo.Seed(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.SeedReport{}, *report)
+
+	total, err := repo.Count(ctx, models.ItemQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 6, total)
+}
+
+// TestSeed_UpsertByName: Los fixtures insertan, actualizan u omiten items según su nombre
+func TestSeed_UpsertByName(t *testing.T) {
+	repo, err := NewSQLiteItemRepository(newTestDB(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	base := writeFixture(t, "base.json", baseFixtureJSON)
+	report, err := repo.Seed(ctx, base)
+	require.NoError(t, err)
+	assert.Equal(t, models.SeedReport{Inserted: 2}, *report)
+
+	update := writeFixture(t, "update.yaml", `
+items:
+  - name: iPad Air
+    image_url: https://example.com/ipad.jpg
+    description: Tablet
+    price: 549
+    rating: 4.6
+    category: tablets
+    specifications: {storage: 64GB, stylus: "Yes"}
+  - name: Fire HD 10
+    image_url: https://example.com/fire.jpg
+    description: Budget tablet
+    price: 139.99
+    rating: 4.1
+    category: tablets
+    specifications: {storage: 32GB}
+`)
+	report, err = repo.Seed(ctx, base, update)
+	require.NoError(t, err)
+	assert.Equal(t, models.SeedReport{Inserted: 1, Updated: 1, Skipped: 2}, *report,
+		"base no cambia nada; update cambia el precio de iPad Air y agrega Fire HD 10")
+
+	items, err := repo.GetAll(ctx, models.ItemQuery{Sort: []models.SortField{{Field: "name"}}})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "Fire HD 10", items[0].Name)
+	assert.Equal(t, 549.0, items[2].Price)
+}
+
+// TestSeed_InvalidFixtureWritesNothing: Un item que no cumple el esquema de su
+// categoría aborta la carga completa
+func TestSeed_InvalidFixtureWritesNothing(t *testing.T) {
+	repo, err := NewSQLiteItemRepository(newTestDB(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	base := writeFixture(t, "base.json", baseFixtureJSON)
+	invalid := writeFixture(t, "invalid.yaml", `
+items:
+  - name: Mystery tablet
+    image_url: https://example.com/mystery.jpg
+    description: Tablet
+    price: 99
+    rating: 3
+    category: tablets
+    specifications: {storage: lots}
+`)
+
+	_, err = repo.Seed(ctx, base, invalid)
+	assert.ErrorContains(t, err, `invalid seed item "Mystery tablet": specifications.storage`)
+
+	total, err := repo.Count(ctx, models.ItemQuery{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+// TestParseFixture_Problems: La estructura del fixture se valida antes de escribir
+func TestParseFixture_Problems(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"formato no soportado", "items.toml", ``, "unsupported fixture format"},
+		{"campo desconocido", "items.yaml", "items:\n  - nam: typo\n", "field nam not found"},
+		{"campo desconocido JSON", "items.json", `{"itemz": []}`, `unknown field "itemz"`},
+		{
+			"item inválido", "items.json",
+			`{"items": [{"name": "A", "image_url": "ftp://x", "price": 0, "rating": 6}]}`,
+			"items[0]: price must be greater than 0; items[0]: rating must be between 0 and 5; items[0]: image_url must be an absolute http(s) URL",
+		},
+		{
+			"nombre repetido", "items.json",
+			`{"items": [{"name": "A", "image_url": "https://e.com/a.jpg", "price": 1}, {"name": "A", "image_url": "https://e.com/a.jpg", "price": 1}]}`,
+			`items[1]: duplicate name "A"`,
+		},
+		{
+			"campo de especificación inválido", "items.yaml",
+			"categories:\n  - {name: X, slug: x, spec_fields: [{key: size, label: Size, type: quantity, dimension: volume}]}\n",
+			`categories[0].spec_fields[0]: unknown dimension "volume"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFixture(tt.file, []byte(tt.content))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationFiles contiene los scripts SQL de las migraciones. Cada versión tiene un
+// archivo NNNN_nombre.up.sql y su reverso NNNN_nombre.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFilePattern reconoce los nombres de archivo de migración.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration es una versión del esquema con sus scripts de aplicación y reversión.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum devuelve el SHA-256 del script up. Se guarda al aplicar la migración
+// para detectar scripts modificados después de haberse aplicado.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// MigrationStatus describe el estado de una migración en la base de datos.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified indica que el script embebido no coincide con el checksum registrado.
+	Modified bool
+	// Unknown indica una migración registrada en la base de datos que este binario no incluye.
+	Unknown bool
+}
+
+// appliedMigration es una fila de la tabla schema_migrations.
+type appliedMigration struct {
+	version   int
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator aplica y revierte las migraciones embebidas, registrando cada versión
+// aplicada en la tabla schema_migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator crea un Migrator con las migraciones embebidas en el binario.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations lee los scripts del directorio migrations y los ordena por versión.
+// Cada versión debe tener su script up y su script down.
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d (%s) needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up aplica, en orden y cada una en su propia transacción, las migraciones pendientes.
+// Devuelve las migraciones aplicadas. Falla sin aplicar nada si alguna migración ya
+// aplicada fue modificada o no pertenece a este binario.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.adoptLegacySchema(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down revierte las últimas `steps` migraciones aplicadas, de la más reciente a la
+// más antigua. Devuelve las migraciones revertidas.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Status devuelve el estado de cada migración conocida, seguido de las migraciones
+// registradas en la base de datos que este binario no incluye.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := make(map[int]bool)
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.appliedAt
+			status.Modified = row.checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, row := range sortedApplied(applied) {
+		if !known[row.version] {
+			statuses = append(statuses, MigrationStatus{
+				Version:   row.version,
+				Name:      row.name,
+				Applied:   true,
+				AppliedAt: row.appliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+
+	return statuses, nil
+}
+
+// ensureTable crea la tabla schema_migrations si no existe.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied lee las migraciones registradas en schema_migrations, indexadas por versión.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var row appliedMigration
+		var appliedAt string
+		if err := rows.Scan(&row.version, &row.name, &row.checksum, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		row.appliedAt, _ = time.Parse(time.RFC3339, appliedAt)
+		applied[row.version] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schema_migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// verify lee las migraciones aplicadas y comprueba que todas existan en este binario
+// con el mismo checksum con el que se aplicaron.
+func (m *Migrator) verify(ctx context.Context) (map[int]appliedMigration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	for _, row := range sortedApplied(applied) {
+		migration, ok := byVersion[row.version]
+		if !ok {
+			return nil, fmt.Errorf("migration %d (%s) is applied but unknown to this binary", row.version, row.name)
+		}
+		if row.checksum != migration.Checksum() {
+			return nil, fmt.Errorf("migration %d (%s) was modified after being applied: checksum mismatch", row.version, row.name)
+		}
+	}
+
+	return applied, nil
+}
+
+// apply ejecuta el script up de la migración y la registra en una misma transacción.
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	return m.inTx(ctx, migration, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum(), time.Now().UTC().Format(time.RFC3339),
+		)
+		return err
+	})
+}
+
+// revert ejecuta el script down de la migración y borra su registro en una misma transacción.
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	return m.inTx(ctx, migration, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+		return err
+	})
+}
+
+// inTx ejecuta fn dentro de una transacción y la confirma solo si fn no falla.
+func (m *Migrator) inTx(ctx context.Context, migration Migration, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// legacyMarkers indica, por versión, la tabla y la columna cuya existencia demuestra
+// que la migración ya estaba aplicada en bases de datos creadas antes de existir las
+// migraciones (cuando el esquema se creaba con CREATE TABLE IF NOT EXISTS).
+var legacyMarkers = []struct {
+	version int
+	table   string
+	column  string
+}{
+	{1, "items", "specifications"},
+	{2, "items", "category_id"},
+	{3, "spec_fields", "key"},
+}
+
+// adoptLegacySchema registra como aplicadas las migraciones cuyo esquema ya existe
+// en una base de datos sin historial de migraciones. Se detiene en la primera
+// migración ausente, que se aplicará normalmente.
+func (m *Migrator) adoptLegacySchema(ctx context.Context) error {
+	var count int
+	if err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		return fmt.Errorf("failed to count schema_migrations: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, marker := range legacyMarkers {
+		exists, err := columnExists(ctx, m.db, marker.table, marker.column)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return nil
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version != marker.version {
+				continue
+			}
+			_, err := m.db.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+				migration.Version, migration.Name, migration.Checksum(), time.Now().UTC().Format(time.RFC3339),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to record legacy migration %d: %w", migration.Version, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// columnExists indica si la tabla existe y tiene la columna indicada.
+func columnExists(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	return count > 0, nil
+}
+
+// sortedApplied devuelve las migraciones aplicadas ordenadas por versión.
+func sortedApplied(applied map[int]appliedMigration) []appliedMigration {
+	rows := make([]appliedMigration, 0, len(applied))
+	for _, row := range applied {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].version < rows[j].version
+	})
+	return rows
+}
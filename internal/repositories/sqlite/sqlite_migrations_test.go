@@ -0,0 +1,165 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMigrator crea un Migrator sobre una base de datos temporal vacía.
+func newTestMigrator(t *testing.T) (*Migrator, *sql.DB) {
+	t.Helper()
+
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "items.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	return migrator, db
+}
+
+// TestMigrator_UpAndDown: Las migraciones se aplican en orden, una sola vez, y se revierten
+func TestMigrator_UpAndDown(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrator.migrations))
+	for i, migration := range applied {
+		assert.Equal(t, i+1, migration.Version)
+	}
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "una base de datos al día no aplica nada")
+
+	steps := len(migrator.migrations) - 1
+	reverted, err := migrator.Down(ctx, steps)
+	require.NoError(t, err)
+	require.Len(t, reverted, steps)
+	for i, migration := range reverted {
+		assert.Equal(t, steps+1-i, migration.Version, "se revierten de la más reciente a la más antigua")
+	}
+	assert.Equal(t, "create_categories", reverted[steps-1].Name)
+
+	hasColumn, err := columnExists(ctx, db, "items", "category_id")
+	require.NoError(t, err)
+	assert.False(t, hasColumn)
+
+	hasColumn, err = columnExists(ctx, db, "items", "version")
+	require.NoError(t, err)
+	assert.False(t, hasColumn)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	for _, status := range statuses[1:] {
+		assert.False(t, status.Applied)
+	}
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, steps)
+}
+
+// TestMigrator_ChecksumMismatch: Una migración aplicada que cambió impide migrar
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	_, err = db.Exec("UPDATE schema_migrations SET checksum = 'tampered' WHERE version = 2")
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[1].Modified)
+}
+
+// TestMigrator_UnknownMigration: Una versión registrada que el binario no incluye impide migrar
+func TestMigrator_UnknownMigration(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at)
+		VALUES (99, 'from_the_future', 'x', '2026-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorContains(t, err, "unknown to this binary")
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	last := statuses[len(statuses)-1]
+	assert.Equal(t, 99, last.Version)
+	assert.True(t, last.Unknown)
+}
+
+// TestMigrator_AdoptsLegacySchema: Una base de datos creada antes de las migraciones
+// se registra como migrada hasta donde llega su esquema y conserva sus datos
+func TestMigrator_AdoptsLegacySchema(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		image_url TEXT NOT NULL,
+		description TEXT NOT NULL,
+		price REAL NOT NULL,
+		rating REAL NOT NULL,
+		specifications TEXT NOT NULL
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO items (name, image_url, description, price, rating, specifications)
+		VALUES ('Old', 'https://example.com/old.jpg', 'Legacy item', 10, 3, '{}')`)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrator.migrations)-1)
+	assert.Equal(t, 2, applied[0].Version)
+
+	repo, err := NewSQLiteItemRepository(db)
+	require.NoError(t, err)
+
+	item, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Old", item.Name)
+	assert.Nil(t, item.CategoryID)
+}
+
+// TestLoadMigrations_RequiresDownScript: Cada migración debe poder revertirse
+func TestLoadMigrations_RequiresDownScript(t *testing.T) {
+	files := fstest.MapFS{
+		"migrations/0001_first.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"migrations/0001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+		"migrations/0002_second.up.sql":  {Data: []byte("CREATE TABLE b (id INTEGER);")},
+	}
+
+	_, err := loadMigrations(files)
+	assert.ErrorContains(t, err, "migration 2 (second)")
+
+	files["migrations/0002_second.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE b;")}
+	migrations, err := loadMigrations(files)
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+}
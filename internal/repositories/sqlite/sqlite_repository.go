@@ -4,56 +4,97 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-
-	_ "github.com/mattn/go-sqlite3"
+	"project/internal/repositories"
+	"strings"
 )
 
 // SQLiteItemRepository implements the ItemRepository interface using SQLite.
-// This file only handles repository creation, schema initialization and cleanup.
+// This file only handles opening the database, repository creation and cleanup.
 type SQLiteItemRepository struct {
 	DB *sql.DB
+
+	// tx, si no es nil, es la transacción en la que se ejecutan las operaciones
+	// del repositorio (ver InTransaction).
+	tx *sql.Tx
+
+	// ftsEnabled indica si el driver incluye FTS5 y existe el índice items_fts.
+	ftsEnabled bool
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx, so the same queries can run
+// inside or outside a transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
-// NewSQLiteItemRepository creates a new SQLite repository instance
-// and initializes the database schema.
-func NewSQLiteItemRepository(dbPath string) (*SQLiteItemRepository, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// databaseOptions are the connection parameters added to the database path. In WAL
+// mode readers do not block writers, so a long export does not lock the catalog;
+// writers that find the database busy wait up to five seconds before failing.
+const databaseOptions = "_journal_mode=WAL&_busy_timeout=5000"
+
+// OpenDatabase opens the SQLite database file with the catalog's SQL functions
+// (see sqlite_functions.go) and databaseOptions. The schema is not created here:
+// run the migrations (see Migrator) before building the repositories.
+func OpenDatabase(dbPath string) (*sql.DB, error) {
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+
+	db, err := sql.Open(driverName, dbPath+separator+databaseOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	return db, nil
+}
 
+// NewSQLiteItemRepository creates a new SQLite repository instance over a migrated
+// database and prepares the full-text search index.
+// The search index is not a migration because it depends on the driver supporting FTS5.
+func NewSQLiteItemRepository(db *sql.DB) (*SQLiteItemRepository, error) {
 	repo := &SQLiteItemRepository{DB: db}
 
-	// Initialize the DB schema
-	if err := repo.initSchema(context.Background()); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	if err := repo.initSearchIndex(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to initialize search index: %w", err)
 	}
 
 	return repo, nil
 }
 
-// initSchema creates the items table if it does not exist.
-func (r *SQLiteItemRepository) initSchema(ctx context.Context) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS items (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			image_url TEXT NOT NULL,
-			description TEXT NOT NULL,
-			price REAL NOT NULL,
-			rating REAL NOT NULL,
-			specifications TEXT NOT NULL
-		)
-	`
+// InTransaction runs fn with a repository whose operations share one transaction.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+// Nested calls reuse the current transaction.
+func (r *SQLiteItemRepository) InTransaction(ctx context.Context, fn func(tx repositories.ItemRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
 
-	if _, err := r.DB.ExecContext(ctx, query); err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
+	if err := fn(&SQLiteItemRepository{DB: r.DB, tx: tx, ftsEnabled: r.ftsEnabled}); err != nil {
+		tx.Rollback()
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
+// conn returns the transaction the repository is bound to, or the database.
+func (r *SQLiteItemRepository) conn() queryer {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
 // Close closes the repository database connection.
 func (r *SQLiteItemRepository) Close() error {
 	return r.DB.Close()
@@ -0,0 +1,623 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"project/internal/models"
+	"project/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB abre una base de datos temporal con todas las migraciones aplicadas.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "items.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// newTestRepository crea un repositorio sobre una base de datos temporal
+// con el esquema migrado y los datos de ejemplo cargados.
+func newTestRepository(t *testing.T) *SQLiteItemRepository {
+	t.Helper()
+
+	repo, err := NewSQLiteItemRepository(newTestDB(t))
+	require.NoError(t, err)
+
+	_, err = repo.Seed(context.Background())
+	require.NoError(t, err)
+	return repo
+}
+
+// TestGetAll_FiltersSortAndPagination: Los filtros, el orden y la paginación se aplican en SQL
+func TestGetAll_FiltersSortAndPagination(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	minPrice := 1500.0
+	query := models.ItemQuery{
+		Limit:    2,
+		Offset:   1,
+		Sort:     []models.SortField{{Field: "price", Desc: true}},
+		MinPrice: &minPrice,
+	}
+
+	items, err := repo.GetAll(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Dell XPS 15", items[0].Name)
+	assert.Equal(t, "Lenovo ThinkPad X1 Carbon", items[1].Name)
+
+	total, err := repo.Count(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+}
+
+// TestSortColumns_CoverSortFields: Todos los campos de ordenamiento que acepta el
+// servicio tienen una columna SQL
+func TestSortColumns_CoverSortFields(t *testing.T) {
+	for _, field := range models.SortFields {
+		assert.Contains(t, sortColumns, field)
+	}
+	assert.Len(t, sortColumns, len(models.SortFields))
+}
+
+// TestGetAll_SpecFilters: Los filtros por especificación se resuelven con json_extract
+func TestGetAll_SpecFilters(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		filters  []models.SpecFilter
+		expected []string
+	}{
+		{
+			name:     "igualdad sin distinguir mayúsculas",
+			filters:  []models.SpecFilter{{Key: "memory", Operator: models.SpecOpEquals, Value: "32gb"}},
+			expected: []string{"Dell XPS 15"},
+		},
+		{
+			name:     "contiene",
+			filters:  []models.SpecFilter{{Key: "processor", Operator: models.SpecOpContains, Value: "m2"}},
+			expected: []string{"MacBook Pro 16\""},
+		},
+		{
+			name:     "menor que con unidad",
+			filters:  []models.SpecFilter{{Key: "weight", Operator: models.SpecOpLess, Value: "1.5kg"}},
+			expected: []string{"HP Spectre x360", "Lenovo ThinkPad X1 Carbon", "Apple AirPods Max"},
+		},
+		{
+			name:     "menor que en otra unidad de la misma dimensión",
+			filters:  []models.SpecFilter{{Key: "weight", Operator: models.SpecOpLess, Value: "500 g"}},
+			expected: []string{"Apple AirPods Max"},
+		},
+		{
+			name:     "mayor o igual entre unidades de almacenamiento",
+			filters:  []models.SpecFilter{{Key: "storage", Operator: models.SpecOpGreaterEqual, Value: "1TB"}},
+			expected: []string{"Dell XPS 15", "ASUS ROG Zephyrus G14"},
+		},
+		{
+			name:     "cantidad dentro de un texto",
+			filters:  []models.SpecFilter{{Key: "battery_life", Operator: models.SpecOpGreaterEqual, Value: "20 hours"}},
+			expected: []string{"MacBook Pro 16\"", "Apple AirPods Max"},
+		},
+		{
+			name:     "unidad de otra dimensión no coincide",
+			filters:  []models.SpecFilter{{Key: "weight", Operator: models.SpecOpLess, Value: "1TB"}},
+			expected: []string{},
+		},
+		{
+			name: "combinación de filtros",
+			filters: []models.SpecFilter{
+				{Key: "storage", Operator: models.SpecOpContains, Value: "1TB"},
+				{Key: "weight", Operator: models.SpecOpGreaterEqual, Value: "1.9"},
+			},
+			expected: []string{"Dell XPS 15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := repo.GetAll(ctx, models.ItemQuery{SpecFilters: tt.filters})
+			require.NoError(t, err)
+
+			names := make([]string, len(items))
+			for i, item := range items {
+				names[i] = item.Name
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+// TestSearch_RanksNameMatchesFirst: Una coincidencia en el nombre pesa más que en las especificaciones
+func TestSearch_RanksNameMatchesFirst(t *testing.T) {
+	repo := newTestRepository(t)
+
+	results, err := repo.Search(context.Background(), "ThinkPad", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Lenovo ThinkPad X1 Carbon", results[0].Item.Name)
+	assert.Contains(t, results[0].HighlightedName, "<mark>ThinkPad</mark>")
+
+	results, err = repo.Search(context.Background(), "intel i7", 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.Contains(t, result.Snippet, "<mark>")
+	}
+}
+
+// TestSearch_IndexFollowsWrites: El índice refleja las altas y bajas de items
+func TestSearch_IndexFollowsWrites(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	item := &models.Item{
+		Name:           "Sony WH-1000XM5",
+		ImageURL:       "https://example.com/images/sony.jpg",
+		Description:    "Noise cancelling headphones",
+		Price:          399.99,
+		Rating:         4.7,
+		Specifications: models.Specifications{"battery_life": "Up to 30 hours"},
+	}
+	require.NoError(t, repo.Create(ctx, item))
+
+	results, err := repo.Search(ctx, "cancelling", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, item.ID, results[0].Item.ID)
+
+	require.NoError(t, repo.Delete(ctx, item.ID))
+
+	results, err = repo.Search(ctx, "cancelling", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+// TestSearchLike_EscapesWildcards: La búsqueda sin FTS5 trata %, _ y \ como texto
+func TestSearchLike_EscapesWildcards(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, tt := range []struct {
+		text     string
+		term     string
+		expected bool
+	}{
+		{"50% off", "50%", true},
+		{"500 off", "50%", false},
+		{"usb_c", "usb_c", true},
+		{"usb-c", "usb_c", false},
+		{`c:\temp`, `c:\t`, true},
+	} {
+		var matches bool
+		err := db.QueryRow(`SELECT ? LIKE ? ESCAPE '\'`, tt.text, "%"+escapeLike(tt.term)+"%").Scan(&matches)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, matches, "%q LIKE %q", tt.text, tt.term)
+	}
+}
+
+// TestStream_IDRangeAndFilters: El cursor recorre en orden de ID solo los items
+// que cumplen los filtros, sin paginar
+func TestStream_IDRangeAndFilters(t *testing.T) {
+	repo := newTestRepository(t)
+	minID, maxID, maxPrice := int64(2), int64(5), 1700.0
+
+	var ids []int64
+	err := repo.Stream(context.Background(), models.ItemQuery{
+		MinID:    &minID,
+		MaxID:    &maxID,
+		MaxPrice: &maxPrice,
+		Limit:    1,
+	}, func(item models.Item) error {
+		ids = append(ids, item.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 5}, ids)
+}
+
+// TestStream_StopsOnCallbackError: Un error de la función interrumpe el recorrido
+func TestStream_StopsOnCallbackError(t *testing.T) {
+	repo := newTestRepository(t)
+
+	calls := 0
+	err := repo.Stream(context.Background(), models.ItemQuery{}, func(item models.Item) error {
+		calls++
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+}
+
+// TestStream_AllowsWritesWhileOpen: Mientras un cursor está abierto, como durante una
+// exportación, se puede escribir en la base de datos
+func TestStream_AllowsWritesWhileOpen(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	streamed := 0
+	err := repo.Stream(ctx, models.ItemQuery{}, func(item models.Item) error {
+		streamed++
+		if streamed > 1 {
+			return nil
+		}
+		return repo.Update(ctx, &models.Item{
+			ID:             item.ID,
+			Name:           item.Name + " (2025)",
+			ImageURL:       item.ImageURL,
+			Price:          item.Price,
+			Currency:       item.Currency,
+			Rating:         item.Rating,
+			Specifications: item.Specifications,
+		})
+	})
+	require.NoError(t, err)
+
+	total, err := repo.Count(ctx, models.ItemQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, total, streamed)
+
+	updated, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "MacBook Pro 16\" (2025)", updated.Name)
+}
+
+// TestInTransaction_RollsBackOnError: Si la función falla no se guarda ninguna escritura
+func TestInTransaction_RollsBackOnError(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	before, err := repo.Count(ctx, models.ItemQuery{})
+	require.NoError(t, err)
+
+	failure := assert.AnError
+	err = repo.InTransaction(ctx, func(tx repositories.ItemRepository) error {
+		item := &models.Item{
+			Name:           "Sony WH-1000XM5",
+			ImageURL:       "https://example.com/images/sony.jpg",
+			Price:          399.99,
+			Rating:         4.7,
+			Specifications: models.Specifications{},
+		}
+		if err := tx.Create(ctx, item); err != nil {
+			return err
+		}
+		if _, err := tx.GetByID(ctx, item.ID); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	after, err := repo.Count(ctx, models.ItemQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+// TestVersions_FollowWrites: Cada escritura incrementa la versión del item y la del catálogo
+func TestVersions_FollowWrites(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	initial, err := repo.CatalogState(ctx)
+	require.NoError(t, err)
+
+	item := &models.Item{
+		Name:           "Sony WH-1000XM5",
+		ImageURL:       "https://example.com/images/sony.jpg",
+		Price:          399.99,
+		Rating:         4.7,
+		Specifications: models.Specifications{},
+	}
+	require.NoError(t, repo.Create(ctx, item))
+	assert.Equal(t, int64(1), item.Version)
+	assert.False(t, item.UpdatedAt.IsZero())
+
+	created, createdAt := item.UpdatedAt, item.CreatedAt
+	// Como en un PUT, el item reemplazado no trae la fecha de creación.
+	item.Price, item.CreatedAt = 349.99, time.Time{}
+	require.NoError(t, repo.Update(ctx, item))
+	assert.Equal(t, int64(2), item.Version)
+	assert.False(t, item.UpdatedAt.Before(created))
+	assert.Equal(t, createdAt, item.CreatedAt)
+
+	stored, err := repo.GetByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, item.Version, stored.Version)
+	assert.Equal(t, item.CreatedAt, stored.CreatedAt)
+	assert.Equal(t, item.UpdatedAt, stored.UpdatedAt)
+
+	require.NoError(t, repo.Delete(ctx, item.ID))
+
+	state, err := repo.CatalogState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, initial.Version+3, state.Version)
+	assert.False(t, state.UpdatedAt.Before(initial.UpdatedAt))
+
+	missing := &models.Item{ID: 9999, Name: "Missing", Specifications: models.Specifications{}}
+	assert.ErrorIs(t, repo.Update(ctx, missing), repositories.ErrNotFound)
+}
+
+// TestUpdateVersioned_Conflict: Solo se actualiza si la versión esperada es la actual
+func TestUpdateVersioned_Conflict(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	item, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, item.CreatedAt.IsZero())
+	version := item.Version
+
+	first := *item
+	first.Price = 100
+	require.NoError(t, repo.UpdateVersioned(ctx, &first, version))
+	assert.Equal(t, version+1, first.Version)
+	assert.Equal(t, item.CreatedAt, first.CreatedAt)
+
+	second := *item
+	second.Price = 200
+	assert.ErrorIs(t, repo.UpdateVersioned(ctx, &second, version), repositories.ErrVersionConflict)
+
+	stored, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, stored.Price)
+	assert.Equal(t, version+1, stored.Version)
+
+	missing := &models.Item{ID: 9999, Name: "Missing", Specifications: models.Specifications{}}
+	assert.ErrorIs(t, repo.UpdateVersioned(ctx, missing, 1), repositories.ErrNotFound)
+}
+
+// TestCategories_TreeAndSubtreeFilter: El filtro por categoría incluye las subcategorías
+func TestCategories_TreeAndSubtreeFilter(t *testing.T) {
+	repo := newTestRepository(t)
+	categories := NewSQLiteCategoryRepository(repo.DB)
+	ctx := context.Background()
+
+	all, err := categories.GetAll(ctx)
+	require.NoError(t, err)
+
+	bySlug := make(map[string]models.Category)
+	for _, category := range all {
+		bySlug[category.Slug] = category
+	}
+	require.Contains(t, bySlug, "computers")
+	require.Contains(t, bySlug, "laptops")
+	assert.Equal(t, bySlug["computers"].ID, *bySlug["laptops"].ParentID)
+
+	computers := bySlug["computers"].ID
+	total, err := repo.Count(ctx, models.ItemQuery{CategoryID: &computers})
+	require.NoError(t, err)
+	assert.Equal(t, 5, total, "los laptops pertenecen a una subcategoría de computers")
+
+	audio := bySlug["audio"].ID
+	items, err := repo.GetAll(ctx, models.ItemQuery{CategoryID: &audio})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, bySlug["headphones"].ID, *items[0].CategoryID)
+
+	_, err = categories.GetByID(ctx, 999)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+// TestSpecFields_Inheritance: Los campos de las categorías ancestro se devuelven primero
+func TestSpecFields_Inheritance(t *testing.T) {
+	repo := newTestRepository(t)
+	categories := NewSQLiteCategoryRepository(repo.DB)
+	ctx := context.Background()
+
+	all, err := categories.GetAll(ctx)
+	require.NoError(t, err)
+
+	bySlug := make(map[string]int64)
+	for _, category := range all {
+		bySlug[category.Slug] = category.ID
+	}
+
+	fields, err := categories.SpecFields(ctx, bySlug["laptops"])
+	require.NoError(t, err)
+
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = field.Key
+	}
+	assert.Equal(t, []string{
+		"processor", "memory", "storage", "graphics", "weight",
+		"display", "battery_life", "touchscreen", "durability", "rgb_keyboard",
+	}, keys)
+	assert.Equal(t, bySlug["computers"], fields[0].CategoryID)
+	assert.Equal(t, models.DimensionDataSize, fields[1].Dimension)
+
+	schema := models.ResolveSpecSchema(bySlug["laptops"], fields)
+	laptops := bySlug["laptops"]
+	items, err := repo.GetAll(ctx, models.ItemQuery{CategoryID: &laptops})
+	require.NoError(t, err)
+	for _, item := range items {
+		assert.Empty(t, schema.Validate(item.Specifications), "el item de ejemplo %q cumple el esquema", item.Name)
+	}
+}
+
+// TestRevisions_RecordWrites: Cada alta, cambio de contenido y baja queda en el
+// historial con su autor y origen; las escrituras sin cambios no generan revisión
+func TestRevisions_RecordWrites(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := models.WithActor(context.Background(), models.Actor{Name: "alice", Source: models.SourceAPI})
+
+	seeded, err := repo.Revisions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, seeded, 1)
+	assert.Equal(t, models.RevisionCreate, seeded[0].Operation)
+	assert.Equal(t, models.SourceSeed, seeded[0].Source)
+
+	item := &models.Item{
+		Name:           "Sony WH-1000XM5",
+		ImageURL:       "https://example.com/images/sony.jpg",
+		Price:          399.99,
+		Rating:         4.7,
+		Specifications: models.Specifications{"color": "black"},
+	}
+	require.NoError(t, repo.Create(ctx, item))
+
+	// Misma información: solo cambian la versión y la fecha.
+	require.NoError(t, repo.Update(ctx, item))
+
+	item.Price = 349.99
+	require.NoError(t, repo.Update(models.WithSource(ctx, models.SourceRestore), item))
+	require.NoError(t, repo.Delete(ctx, item.ID))
+
+	revisions, err := repo.Revisions(ctx, item.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	assert.Equal(t, int64(1), revisions[0].Revision)
+	assert.Equal(t, models.RevisionCreate, revisions[0].Operation)
+	assert.Equal(t, "alice", revisions[0].Actor)
+	assert.Equal(t, models.SourceAPI, revisions[0].Source)
+	assert.Nil(t, revisions[0].OldValues)
+	assert.Equal(t, "black", revisions[0].NewValues.Specifications["color"])
+
+	assert.Equal(t, models.RevisionUpdate, revisions[1].Operation)
+	assert.Equal(t, models.SourceRestore, revisions[1].Source)
+	assert.Equal(t, 399.99, revisions[1].OldValues.Price)
+	assert.Equal(t, 349.99, revisions[1].NewValues.Price)
+
+	assert.Equal(t, models.RevisionDelete, revisions[2].Operation)
+	assert.Nil(t, revisions[2].NewValues)
+	assert.Equal(t, 349.99, revisions[2].OldValues.Price)
+
+	rev, err := repo.Revision(ctx, item.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, revisions[1], *rev)
+
+	_, err = repo.Revision(ctx, item.ID, 4)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	// Las escrituras que no pasan por la aplicación no tienen autor.
+	_, err = repo.DB.ExecContext(ctx, "UPDATE items SET price = price + 1 WHERE id = 1")
+	require.NoError(t, err)
+	manual, err := repo.Revisions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, manual, 2)
+	assert.Empty(t, manual[1].Actor)
+	assert.Empty(t, manual[1].Source)
+}
+
+// TestPriceHistory_FollowsPriceChanges: Solo los cambios de precio se registran en
+// la serie, y la consulta incluye el precio vigente al inicio de la ventana
+func TestPriceHistory_FollowsPriceChanges(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	item := &models.Item{
+		Name:           "Sony WH-1000XM5",
+		ImageURL:       "https://example.com/images/sony.jpg",
+		Price:          399.99,
+		Rating:         4.7,
+		Specifications: models.Specifications{},
+	}
+	require.NoError(t, repo.Create(ctx, item))
+
+	item.Rating = 4.8
+	require.NoError(t, repo.Update(ctx, item))
+	item.Price = 349.99
+	require.NoError(t, repo.Update(ctx, item))
+	item.Price = 379.99
+	require.NoError(t, repo.Update(ctx, item))
+
+	past := time.Now().Add(-time.Hour)
+	points, err := repo.PriceHistory(ctx, item.ID, past)
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.Equal(t, 399.99, points[0].Price)
+	assert.Equal(t, 379.99, points[2].Price)
+	assert.False(t, points[2].RecordedAt.Before(points[0].RecordedAt))
+
+	// Después del último cambio solo rige el precio actual.
+	future := time.Now().Add(time.Hour)
+	points, err = repo.PriceHistory(ctx, item.ID, future)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, 379.99, points[0].Price)
+
+	lowest, err := repo.LowestPrices(ctx, []int64{1, item.ID, 9999}, past)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 349.99}, lowest[item.ID])
+	assert.Contains(t, lowest, int64(1))
+	assert.NotContains(t, lowest, int64(9999))
+
+	lowest, err = repo.LowestPrices(ctx, []int64{item.ID}, future)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 379.99}, lowest[item.ID])
+
+	// La serie se conserva al eliminar el item.
+	require.NoError(t, repo.Delete(ctx, item.ID))
+	points, err = repo.PriceHistory(ctx, item.ID, past)
+	require.NoError(t, err)
+	assert.Len(t, points, 3)
+}
+
+// TestGetAll_PriceRates: La moneda se guarda con el item y, con PriceRates, los
+// filtros y el orden por precio comparan los precios convertidos
+func TestGetAll_PriceRates(t *testing.T) {
+	repo, err := NewSQLiteItemRepository(newTestDB(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	create := func(name string, price float64, currency string) *models.Item {
+		item := &models.Item{
+			Name: name, ImageURL: "https://example.com/" + name + ".jpg", Price: price, Currency: currency,
+			Specifications: models.Specifications{},
+		}
+		require.NoError(t, repo.Create(ctx, item))
+		return item
+	}
+	dollars := create("dollars", 100, "")
+	euros := create("euros", 90, "EUR")
+	pesos := create("pesos", 300000, "COP")
+
+	stored, err := repo.GetByID(ctx, dollars.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", stored.Currency)
+
+	minPrice := 80.0
+	query := models.ItemQuery{
+		Sort:       []models.SortField{{Field: "price", Desc: true}},
+		MinPrice:   &minPrice,
+		PriceRates: map[string]float64{"USD": 1, "EUR": 1.25, "COP": 0.00025},
+	}
+	items, err := repo.GetAll(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, euros.ID, items[0].ID)
+	assert.Equal(t, dollars.ID, items[1].ID)
+
+	// Sin tipos de cambio se comparan los importes tal cual.
+	query.PriceRates = nil
+	total, err := repo.Count(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	// Un cambio de moneda con el mismo importe también es un cambio de precio.
+	pesos.Currency = "USD"
+	pesos.Price = 300000
+	require.NoError(t, repo.Update(ctx, pesos))
+	lowest, err := repo.LowestPrices(ctx, []int64{pesos.ID}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"COP": 300000, "USD": 300000}, lowest[pesos.ID])
+}
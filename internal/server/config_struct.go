@@ -1,8 +1,76 @@
 package server
 
+import "time"
+
 // Config contiene los parámetros de configuración del servidor.
 // Esto ayuda a mantener la configuración separada y mejora la capacidad de prueba.
 type Config struct {
 	Port   string
 	DBPath string
+
+	// ComparisonRulesPath es un archivo JSON opcional con las direcciones
+	// ("higher" o "lower") por criterio de comparación.
+	ComparisonRulesPath string
+
+	// SeedPaths son archivos de fixtures (JSON o YAML) que se cargan al iniciar, en orden.
+	// Si está vacío, se carga el catálogo de ejemplo cuando la base de datos no tiene items.
+	SeedPaths []string
+
+	// ExchangeRatesSource es el origen opcional de los tipos de cambio: una URL
+	// http(s) que se consulta periódicamente o la ruta de un archivo JSON. Sin él,
+	// los precios solo se pueden expresar en la moneda de cada item.
+	ExchangeRatesSource string
+
+	// ExchangeRatesTTL es el tiempo durante el que se reutilizan los tipos de cambio
+	// obtenidos de una URL antes de volver a consultarla.
+	ExchangeRatesTTL time.Duration
+
+	// AuthDisabled desactiva la autenticación con API keys: la API queda anónima.
+	// Solo es apropiado para desarrollo local.
+	AuthDisabled bool
+
+	// JWKSSource es el origen opcional de las claves públicas del proveedor de
+	// identidad: una URL http(s) o la ruta de un archivo JWKS. Con él, la API acepta
+	// también JWT como bearer tokens.
+	JWKSSource string
+
+	// JWKSTTL es el tiempo durante el que se reutiliza el JWKS obtenido de una URL.
+	JWKSTTL time.Duration
+
+	// JWTIssuer y JWTAudience son los valores exigidos en los claims iss y aud.
+	// Son obligatorios si hay un JWKSSource.
+	JWTIssuer   string
+	JWTAudience string
+
+	// JWTRolesClaim es el claim con los roles del usuario (por defecto "roles").
+	JWTRolesClaim string
+
+	// JWTTierClaim es el claim con el tier del usuario (por defecto "tier").
+	JWTTierClaim string
+
+	// JWTRolesPath es un archivo JSON opcional que traduce los roles a scopes.
+	JWTRolesPath string
+
+	// TrustedProxies son las redes (CIDR o direcciones sueltas) de los proxies cuya
+	// cabecera TrustedProxyHeader se acepta para resolver la IP del cliente.
+	// Sin ninguna, la IP del cliente es la dirección remota de la conexión.
+	TrustedProxies []string
+
+	// TrustedProxyHeader es la cabecera de la que se lee la IP del cliente en las
+	// peticiones de proxies de confianza: "x-forwarded-for" (por defecto) o
+	// "forwarded". La otra se ignora.
+	TrustedProxyHeader string
+
+	// RateLimitsPath es un archivo JSON opcional con políticas de límite de tasa que
+	// reemplazan a las por defecto del mismo tier y grupo de rutas.
+	RateLimitsPath string
+
+	// RateLimitRedisURL es la URL opcional (redis:// o rediss://) de un servidor Redis
+	// donde se guardan los límites de tasa, compartidos por todas las réplicas. Sin
+	// ella, cada réplica los guarda en memoria.
+	RateLimitRedisURL string
+
+	// RateLimitFailClosed rechaza las peticiones con 503 si el servidor Redis no
+	// responde. Por defecto se permiten sin límite mientras no responda.
+	RateLimitFailClosed bool
 }
@@ -1,10 +1,9 @@
 package server
 
 import (
-	"time"
-
 	"project/internal/handlers"
 	customMiddleware "project/internal/middleware"
+	"project/internal/models"
 	"project/internal/services"
 
 	"github.com/go-chi/chi/v5"
@@ -15,7 +14,17 @@ import (
 // Este diseño respeta principios de **Inyección de Dependencias**, **Responsabilidad Única (SRP)**
 // y conceptos de **Arquitectura Limpia**, permitiendo que el router no dependa directamente
 // de la capa de datos, sino únicamente de los servicios.
-func SetupRouter(itemService services.ItemService) *chi.Mux {
+//
+// Si auth tiene algún mecanismo de autenticación, las rutas de /api/v1 exigen una
+// API key o un JWT cuyo cliente tenga el scope de cada ruta (los roles de los JWT se
+// traducen a scopes); si no, la API es anónima.
+//
+// clientIP resuelve la IP del cliente con los proxies de confianza configurados; con
+// nil, la IP es siempre la dirección remota de la conexión.
+//
+// rateLimiter aplica las políticas de límite de tasa globales y de cada grupo de rutas
+// (lectura, escritura y comparación); con nil, las de DefaultRateLimitPolicies.
+func SetupRouter(itemService services.ItemService, categoryService services.CategoryService, auth customMiddleware.Authenticators, clientIP *customMiddleware.ClientIPResolver, rateLimiter *customMiddleware.RateLimiter) *chi.Mux {
 	r := chi.NewRouter()
 
 	// ----------------------------
@@ -31,17 +40,33 @@ func SetupRouter(itemService services.ItemService) *chi.Mux {
 	// RequestID: asigna un ID único por petición, útil para trazabilidad y debug.
 	r.Use(chiMiddleware.RequestID)
 
-	// Logger: registra cada petición HTTP con detalles como ruta, método, latencia y código de respuesta.
-	r.Use(chiMiddleware.Logger)
+	// ClientIP: resuelve la IP del cliente, confiando en la cabecera de reenvío configurada
+	// (X-Forwarded-For o Forwarded) solo si la petición llega de un proxy de confianza. El registro, el rate limiting y el autor de
+	// las escrituras usan esta IP, así que debe ir antes que ellos.
+	if clientIP == nil {
+		clientIP = &customMiddleware.ClientIPResolver{}
+	}
+	r.Use(clientIP.ClientIP)
+
+	// Logger: registra cada petición HTTP con detalles como IP del cliente, ruta, método,
+	// latencia y código de respuesta.
+	r.Use(customMiddleware.Logger)
 
 	// Recoverer: captura cualquier panic en la ejecución de handlers y previene que el servidor colapse.
 	r.Use(chiMiddleware.Recoverer)
 
-	// RateLimiter: límite de 100 solicitudes por minuto por IP.
-	// Esto protege la API contra abuso o ataques de denegación de servicio (DoS).
-	rateLimiter := customMiddleware.NewRateLimiter(100, 1*time.Minute)
+	// RateLimiter: límite global de solicitudes por IP del cliente (por defecto 1000 por minuto).
+	// Esto protege la API contra abuso o ataques de denegación de servicio (DoS). Los límites
+	// de cada grupo de rutas por cliente y tier se aplican en las rutas, tras autenticar.
+	if rateLimiter == nil {
+		rateLimiter = customMiddleware.NewRateLimiter(customMiddleware.DefaultRateLimitPolicies())
+	}
 	r.Use(rateLimiter.RateLimit)
 
+	// Actor: identifica al autor de las escrituras para el historial de revisiones de items
+	// (anónimo hasta que Authenticate identifica al cliente).
+	r.Use(customMiddleware.Actor)
+
 	// ----------------------------
 	// Inicialización de handlers
 	// ----------------------------
@@ -49,15 +74,59 @@ func SetupRouter(itemService services.ItemService) *chi.Mux {
 	// Se inyecta itemService.
 	itemHandler := handlers.NewItemHandler(itemService)
 
+	// CategoryHandler maneja el árbol de categorías y los items de cada categoría.
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+
+	// routeGroup devuelve las rutas de r que exigen el scope indicado (admin los
+	// concede todos) y aplican la política de límite de tasa del grupo según el tier
+	// del cliente. Sin autenticación, no se exige ningún scope.
+	routeGroup := func(r chi.Router, scope, group string) chi.Router {
+		if auth.Enabled() {
+			r = r.With(customMiddleware.RequireScope(scope))
+		}
+		return r.With(rateLimiter.Limit(group))
+	}
+
 	// ----------------------------
 	// Definición de rutas
 	// ----------------------------
 	r.Route("/api/v1", func(r chi.Router) {
+		// Authenticate: exige una API key (X-API-Key) o un bearer token válido y
+		// reemplaza al autor anónimo de las escrituras por el cliente autenticado.
+		if auth.Enabled() {
+			r.Use(customMiddleware.Authenticate(auth))
+		}
+
 		// Items endpoints
 		r.Route("/items", func(r chi.Router) {
-			r.Get("/", itemHandler.GetAllItems)
-			r.Get("/{id}", itemHandler.GetItemByID)
-			r.Post("/compare", itemHandler.CompareItems)
+			read := routeGroup(r, models.ScopeItemsRead, customMiddleware.RouteGroupRead)
+			write := routeGroup(r, models.ScopeItemsWrite, customMiddleware.RouteGroupWrite)
+			compare := routeGroup(r, models.ScopeItemsCompare, customMiddleware.RouteGroupCompare)
+
+			read.Get("/", itemHandler.GetAllItems)
+			write.Post("/", itemHandler.CreateItem)
+			read.Get("/search", itemHandler.SearchItems)
+			write.Post("/import", itemHandler.ImportItems)
+			read.Get("/export", itemHandler.ExportItems)
+			read.Get("/{id}", itemHandler.GetItemByID)
+			write.Put("/{id}", itemHandler.UpdateItem)
+			write.Patch("/{id}", itemHandler.PatchItem)
+			write.Delete("/{id}", itemHandler.DeleteItem)
+			read.Get("/{id}/revisions", itemHandler.ItemRevisions)
+			read.Get("/{id}/revisions/diff", itemHandler.DiffItemRevisions)
+			write.Post("/{id}/revisions/{rev}/restore", itemHandler.RestoreItemRevision)
+			read.Get("/{id}/price-history", itemHandler.PriceHistory)
+			compare.Post("/compare", itemHandler.CompareItems)
+			compare.Post("/compare/score", itemHandler.ScoreItems)
+		})
+
+		// Categories endpoints
+		r.Route("/categories", func(r chi.Router) {
+			read := routeGroup(r, models.ScopeItemsRead, customMiddleware.RouteGroupRead)
+
+			read.Get("/", categoryHandler.GetCategories)
+			read.Get("/{id}/items", categoryHandler.GetCategoryItems)
+			read.Get("/{id}/spec-schema", categoryHandler.GetSpecSchema)
 		})
 	})
 
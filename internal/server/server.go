@@ -2,14 +2,20 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"project/internal/currency"
+	"project/internal/jwtauth"
+	customMiddleware "project/internal/middleware"
+	"project/internal/ratelimit"
 	"project/internal/repositories/sqlite"
 	"project/internal/services"
 )
@@ -17,25 +23,94 @@ import (
 // NewServer crea e inicializa una nueva instancia del servidor.
 //
 // Este constructor realiza los siguientes pasos:
-// 1. Inicializa el repositorio SQLite, encargado de la persistencia.
-// 2. Ejecuta la siembra (Seed) para cargar datos iniciales en la base de datos.
-// 3. Crea el servicio de negocio (ItemService), aplicando el patrón de inyección de dependencias.
-// 4. Configura el router con todas las rutas HTTP y middleware.
+// 1. Abre la base de datos, aplica las migraciones pendientes e inicializa el repositorio SQLite.
+// 2. Ejecuta la siembra (Seed) con los fixtures configurados para cargar datos iniciales.
+// 3. Crea los servicios de negocio (ItemService, CategoryService) con las reglas de comparación
+// y el proveedor de tipos de cambio configurados.
+// 4. Configura el router con todas las rutas HTTP y middleware, con autenticación por
+// API keys y, si hay un JWKS configurado, por JWT, salvo que esté desactivada, y con
+// las políticas de límite de tasa configuradas.
 // 5. Construye el servidor HTTP con configuraciones de timeout apropiadas.
-func NewServer(cfg Config) (*Server, error) {
-	repo, err := sqlite.NewSQLiteItemRepository(cfg.DBPath)
+func NewServer(cfg Config) (_ *Server, err error) {
+	db, err := sqlite.OpenDatabase(cfg.DBPath)
 	if err != nil {
-		return nil, fmt.Errorf("error al inicializar el repositorio: %w", err)
+		return nil, fmt.Errorf("error al abrir la base de datos: %w", err)
 	}
 
+	// Si la configuración falla después de abrir la base de datos, se cierran ella y
+	// el store de los límites de tasa, si ya se creó.
+	var rateLimiter *customMiddleware.RateLimiter
+	defer func() {
+		if err == nil {
+			return
+		}
+		if rateLimiter != nil {
+			rateLimiter.Stop()
+		}
+		db.Close()
+	}()
+
 	ctx := context.Background()
-	if err := repo.Seed(ctx); err != nil {
+	if err := migrate(ctx, db); err != nil {
+		return nil, err
+	}
+
+	repo, err := sqlite.NewSQLiteItemRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("error al inicializar el repositorio: %w", err)
+	}
+	if !repo.SearchIndexEnabled() {
+		log.Printf("Advertencia: el driver de SQLite no incluye FTS5; la búsqueda usa LIKE en lugar del índice items_fts. Compila con -tags sqlite_fts5 para habilitarlo")
+	}
+
+	report, err := repo.Seed(ctx, cfg.SeedPaths...)
+	if err != nil {
 		return nil, fmt.Errorf("error al poblar la base de datos: %w", err)
 	}
+	log.Printf("Seed: %d items insertados, %d actualizados, %d sin cambios", report.Inserted, report.Updated, report.Skipped)
+
+	rules := services.DefaultComparisonRules()
+	if cfg.ComparisonRulesPath != "" {
+		rules, err = services.LoadComparisonRules(cfg.ComparisonRulesPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	categoryRepo := sqlite.NewSQLiteCategoryRepository(repo.DB)
+
+	options := []services.ItemServiceOption{
+		services.WithComparisonRules(rules),
+		services.WithCategories(categoryRepo),
+		services.WithPriceHistory(repo),
+	}
+	if cfg.ExchangeRatesSource != "" {
+		rates, err := newExchangeRateProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, services.WithExchangeRates(rates))
+	}
+
+	service := services.NewItemService(repo, options...)
+	categoryService := services.NewCategoryService(categoryRepo, service)
+
+	auth, err := newAuthenticators(cfg, repo.DB)
+	if err != nil {
+		return nil, err
+	}
 
-	service := services.NewItemService(repo)
+	clientIP, err := customMiddleware.NewClientIPResolver(cfg.TrustedProxies, cfg.TrustedProxyHeader)
+	if err != nil {
+		return nil, fmt.Errorf("error en los proxies de confianza: %w", err)
+	}
 
-	router := SetupRouter(service)
+	rateLimiter, err = newRateLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	router := SetupRouter(service, categoryService, auth, clientIP, rateLimiter)
 
 	httpServer := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -46,13 +121,135 @@ func NewServer(cfg Config) (*Server, error) {
 	}
 
 	return &Server{
-		router:     router,
-		repo:       repo,
-		service:    service,
-		httpServer: httpServer,
+		router:      router,
+		repo:        repo,
+		service:     service,
+		rateLimiter: rateLimiter,
+		httpServer:  httpServer,
 	}, nil
 }
 
+// migrate aplica las migraciones de esquema pendientes y registra cuáles se aplicaron.
+func migrate(ctx context.Context, db *sql.DB) error {
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("error al cargar las migraciones: %w", err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("error al migrar la base de datos: %w", err)
+	}
+
+	for _, migration := range applied {
+		log.Printf("Migración aplicada: %04d_%s", migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// newAuthenticators crea los mecanismos de autenticación configurados: API keys
+// guardadas en db y, si hay un JWKS, JWT de un proveedor de identidad. Con la
+// autenticación desactivada no crea ninguno.
+func newAuthenticators(cfg Config, db *sql.DB) (customMiddleware.Authenticators, error) {
+	if cfg.AuthDisabled {
+		log.Println("Autenticación desactivada: la API es anónima")
+		return customMiddleware.Authenticators{}, nil
+	}
+
+	auth := customMiddleware.Authenticators{
+		APIKeys: services.NewAPIKeyService(sqlite.NewSQLiteAPIKeyRepository(db)),
+	}
+	if cfg.JWKSSource == "" {
+		return auth, nil
+	}
+
+	var keys jwtauth.KeySet
+	if isURL(cfg.JWKSSource) {
+		keys = jwtauth.NewRemoteKeySet(cfg.JWKSSource, cfg.JWKSTTL)
+	} else {
+		set, err := jwtauth.LoadStaticKeySet(cfg.JWKSSource)
+		if err != nil {
+			return auth, fmt.Errorf("error al cargar el JWKS: %w", err)
+		}
+		keys = set
+	}
+
+	jwtConfig := jwtauth.Config{
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		RolesClaim: cfg.JWTRolesClaim,
+		TierClaim:  cfg.JWTTierClaim,
+	}
+	if cfg.JWTRolesPath != "" {
+		roles, err := jwtauth.LoadRoleMapping(cfg.JWTRolesPath)
+		if err != nil {
+			return auth, fmt.Errorf("error al cargar los roles de los JWT: %w", err)
+		}
+		jwtConfig.Roles = roles
+	}
+
+	verifier, err := jwtauth.NewVerifier(keys, jwtConfig)
+	if err != nil {
+		return auth, fmt.Errorf("error al configurar la verificación de JWT: %w", err)
+	}
+	log.Printf("JWT: emisor %s, audiencia %s, JWKS %s", cfg.JWTIssuer, cfg.JWTAudience, cfg.JWKSSource)
+	auth.Tokens = verifier
+	return auth, nil
+}
+
+// newRateLimiter crea el limitador de tasa con las políticas configuradas. Guarda los
+// límites en Redis si hay una URL configurada o, si no, en memoria.
+func newRateLimiter(cfg Config) (*customMiddleware.RateLimiter, error) {
+	policies := customMiddleware.DefaultRateLimitPolicies()
+	if cfg.RateLimitsPath != "" {
+		var err error
+		if policies, err = customMiddleware.LoadRateLimitPolicies(cfg.RateLimitsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.RateLimitRedisURL == "" {
+		return customMiddleware.NewRateLimiter(policies), nil
+	}
+
+	store, err := ratelimit.DialRedis(cfg.RateLimitRedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("error al configurar el store de límites de tasa: %w", err)
+	}
+	mode := "se permiten las peticiones si no responde"
+	if cfg.RateLimitFailClosed {
+		mode = "se rechazan las peticiones si no responde"
+	}
+	log.Printf("Límites de tasa en Redis (%s)", mode)
+
+	return customMiddleware.NewRateLimiter(policies,
+		customMiddleware.WithRateLimitStore(store),
+		customMiddleware.WithFailClosed(cfg.RateLimitFailClosed),
+	), nil
+}
+
+// isURL indica si source es una URL http(s) en lugar de la ruta de un archivo.
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// newExchangeRateProvider crea el proveedor de tipos de cambio: uno HTTP si el
+// origen es una URL http(s) o, si no, uno estático con el archivo indicado.
+func newExchangeRateProvider(cfg Config) (currency.ExchangeRateProvider, error) {
+	source := cfg.ExchangeRatesSource
+	if isURL(source) {
+		log.Printf("Tipos de cambio: %s (cada %s)", source, cfg.ExchangeRatesTTL)
+		return currency.NewHTTPProvider(source, cfg.ExchangeRatesTTL), nil
+	}
+
+	provider, err := currency.LoadStaticProvider(source)
+	if err != nil {
+		return nil, fmt.Errorf("error al cargar los tipos de cambio: %w", err)
+	}
+	log.Printf("Tipos de cambio: %s", source)
+	return provider, nil
+}
+
 // Start inicia el servidor HTTP y maneja el apagado seguro (graceful shutdown).
 //
 // Este método:
@@ -61,6 +258,7 @@ func NewServer(cfg Config) (*Server, error) {
 // 3. Cuando llega una señal de finalización, inicia un apagado controlado:
 //   - Detiene nuevas conexiones.
 //   - Espera hasta 10 segundos para que las conexiones activas finalicen.
+//   - Cierra el store de los límites de tasa.
 //   - Cierra la base de datos de manera segura.
 func (s *Server) Start() error {
 	stop := make(chan os.Signal, 1)
@@ -82,6 +280,7 @@ func (s *Server) Start() error {
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("error al detener el servidor: %w", err)
 	}
+	s.rateLimiter.Stop()
 
 	if err := s.repo.Close(); err != nil {
 		return fmt.Errorf("Error al cerrar la base de datos: %w", err)
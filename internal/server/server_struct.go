@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 
+	customMiddleware "project/internal/middleware"
 	"project/internal/repositories"
 	"project/internal/services"
 
@@ -11,8 +12,9 @@ import (
 
 // Server representa el servidor HTTP y sus dependencias.
 type Server struct {
-	router     *chi.Mux
-	repo       repositories.ItemRepository
-	service    services.ItemService
-	httpServer *http.Server
+	router      *chi.Mux
+	repo        repositories.ItemRepository
+	service     services.ItemService
+	rateLimiter *customMiddleware.RateLimiter
+	httpServer  *http.Server
 }
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"project/internal/models"
+)
+
+// APIKeyService define la gestión de las API keys y la autenticación con ellas.
+type APIKeyService interface {
+	// CreateKey crea una clave con el nombre, los scopes y el tier indicados (vacío
+	// es models.TierStandard). Devuelve también la clave completa, que solo se puede
+	// mostrar en este momento.
+	CreateKey(ctx context.Context, name string, scopes []string, tier string) (*models.APIKey, string, error)
+
+	// ListKeys devuelve todas las claves, incluidas las revocadas.
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// RotateKey reemplaza la clave completa de una clave no revocada, que conserva
+	// su nombre, sus scopes y su tier; la anterior deja de ser válida. Devuelve la nueva clave.
+	RotateKey(ctx context.Context, id int64) (*models.APIKey, string, error)
+
+	// RevokeKey revoca una clave: deja de autenticar, pero se sigue listando.
+	RevokeKey(ctx context.Context, id int64) error
+
+	// Authenticate valida una clave completa y devuelve el cliente que identifica.
+	// Una clave mal formada, desconocida o revocada es un error Unauthorized.
+	Authenticate(ctx context.Context, key string) (*models.Principal, error)
+}
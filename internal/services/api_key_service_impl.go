@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	stdErrors "errors"
+	"fmt"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"strings"
+	"time"
+)
+
+const (
+	// apiKeyMarker antecede a cada clave para reconocerla, por ejemplo en los logs.
+	// Una clave completa es apiKeyMarker + prefijo + "_" + secreto, en hexadecimal.
+	apiKeyMarker = "ick_"
+
+	apiKeyPrefixBytes = 4
+	apiKeySecretBytes = 32
+
+	maxAPIKeyNameLength = 100
+
+	// lastUsedInterval es cada cuánto se registra, como mucho, el último uso de una
+	// clave, para no escribir en la base de datos en cada petición.
+	lastUsedInterval = time.Minute
+)
+
+// APIKeyServiceImpl implementa APIKeyService. De cada clave solo se guarda el hash
+// SHA-256: las claves son aleatorias y largas, así que no necesitan un hash lento.
+type APIKeyServiceImpl struct {
+	repo repositories.APIKeyRepository
+	now  func() time.Time
+}
+
+// NewAPIKeyService crea una nueva instancia del servicio de API keys.
+func NewAPIKeyService(repo repositories.APIKeyRepository) APIKeyService {
+	return &APIKeyServiceImpl{repo: repo, now: time.Now}
+}
+
+// CreateKey valida el nombre, los scopes y el tier, genera la clave y guarda su hash.
+func (s *APIKeyServiceImpl) CreateKey(ctx context.Context, name string, scopes []string, tier string) (*models.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, "", errors.NewValidationError(
+			fmt.Sprintf("el nombre de la clave es obligatorio y no puede superar %d caracteres", maxAPIKeyNameLength), nil,
+		)
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == "" {
+		tier = models.TierStandard
+	}
+	if !models.IsValidTier(tier) {
+		return nil, "", errors.NewValidationError(
+			fmt.Sprintf("tier no válido: %q (debe ser uno de %s)", tier, strings.Join(models.Tiers, ", ")), nil,
+		)
+	}
+
+	prefix, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, "", errors.NewInternalServerError("error al generar la clave", err)
+	}
+
+	key := &models.APIKey{Name: name, Prefix: prefix, Hash: hashAPIKey(secret), Scopes: scopes, Tier: tier}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", errors.NewInternalServerError("error al guardar la clave", err)
+	}
+	return key, secret, nil
+}
+
+// ListKeys devuelve todas las claves.
+func (s *APIKeyServiceImpl) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener las claves", err)
+	}
+	return keys, nil
+}
+
+// RotateKey genera una nueva clave completa para la clave indicada.
+func (s *APIKeyServiceImpl) RotateKey(ctx context.Context, id int64) (*models.APIKey, string, error) {
+	key, err := s.activeKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, "", errors.NewInternalServerError("error al generar la clave", err)
+	}
+	if err := s.repo.Rotate(ctx, id, prefix, hashAPIKey(secret)); err != nil {
+		return nil, "", s.keyError(err, id, "error al rotar la clave")
+	}
+
+	rotatedAt := s.now().UTC()
+	key.Prefix, key.RotatedAt = prefix, &rotatedAt
+	return key, secret, nil
+}
+
+// RevokeKey revoca la clave indicada.
+func (s *APIKeyServiceImpl) RevokeKey(ctx context.Context, id int64) error {
+	if _, err := s.activeKey(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return s.keyError(err, id, "error al revocar la clave")
+	}
+	return nil
+}
+
+// Authenticate busca la clave por su prefijo y compara el hash en tiempo constante.
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, secret string) (*models.Principal, error) {
+	prefix, ok := apiKeyPrefix(secret)
+	if !ok {
+		return nil, errors.NewUnauthorizedError("API key inválida")
+	}
+
+	key, err := s.repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if stdErrors.Is(err, repositories.ErrNotFound) {
+			return nil, errors.NewUnauthorizedError("API key inválida")
+		}
+		return nil, errors.NewInternalServerError("error al validar la API key", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKey(secret))) != 1 {
+		return nil, errors.NewUnauthorizedError("API key inválida")
+	}
+	if key.Revoked() {
+		return nil, errors.NewUnauthorizedError("la API key fue revocada")
+	}
+
+	now := s.now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		// No poder registrar el último uso no impide autenticar la petición.
+		_ = s.repo.TouchLastUsed(ctx, key.ID, now)
+	}
+
+	return &models.Principal{Name: "apikey:" + key.Name, Scopes: key.Scopes, Tier: key.Tier}, nil
+}
+
+// activeKey busca una clave que exista y no esté revocada.
+func (s *APIKeyServiceImpl) activeKey(ctx context.Context, id int64) (*models.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, s.keyError(err, id, "error al obtener la clave")
+	}
+	if key.Revoked() {
+		return nil, errors.NewConflictError(fmt.Sprintf("la clave %d ya está revocada", id), nil)
+	}
+	return key, nil
+}
+
+// keyError traduce repositories.ErrNotFound a NotFound y el resto de errores a
+// un error interno con el mensaje indicado.
+func (s *APIKeyServiceImpl) keyError(err error, id int64, message string) error {
+	if stdErrors.Is(err, repositories.ErrNotFound) {
+		return errors.NewNotFoundError("API key", id)
+	}
+	return errors.NewInternalServerError(message, err)
+}
+
+// normalizeScopes valida los scopes y elimina los vacíos y los repetidos.
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if !models.IsValidScope(scope) {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("scope no válido: %q (debe ser uno de %s)", scope, strings.Join(models.Scopes, ", ")), nil,
+			)
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.NewValidationError("la clave debe tener al menos un scope", nil)
+	}
+	return normalized, nil
+}
+
+// generateAPIKey genera una clave aleatoria y devuelve su prefijo y la clave completa.
+func generateAPIKey() (string, string, error) {
+	random := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+	prefix := hex.EncodeToString(random[:apiKeyPrefixBytes])
+	return prefix, apiKeyMarker + prefix + "_" + hex.EncodeToString(random[apiKeyPrefixBytes:]), nil
+}
+
+// apiKeyPrefix extrae el prefijo de una clave completa y comprueba su formato.
+func apiKeyPrefix(secret string) (string, bool) {
+	rest, ok := strings.CutPrefix(secret, apiKeyMarker)
+	if !ok {
+		return "", false
+	}
+	prefix, random, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes || len(random) != 2*apiKeySecretBytes {
+		return "", false
+	}
+	return prefix, true
+}
+
+// hashAPIKey devuelve el hash SHA-256 en hexadecimal de la clave completa.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
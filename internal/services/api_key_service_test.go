@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository es una implementación mock de APIKeyRepository para pruebas
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Rotate(ctx context.Context, id int64, prefix, hash string) error {
+	args := m.Called(ctx, id, prefix, hash)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// assertErrorCode comprueba que err sea un DomainError con el código indicado
+func assertErrorCode(t *testing.T, err error, code errors.ErrorCode) {
+	t.Helper()
+	var domainErr *errors.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, code, domainErr.Code)
+}
+
+// TestAPIKeyService_CreateAndAuthenticate: Solo se guarda el hash de la clave, que
+// después autentica al cliente con sus scopes
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	var stored *models.APIKey
+	mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.APIKey)
+		stored.ID = 1
+	}).Return(nil)
+
+	key, secret, err := service.CreateKey(context.Background(), " ci ", []string{"Items:Read", "items:compare", "items:read", ""}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", key.Name)
+	assert.Equal(t, []string{models.ScopeItemsRead, models.ScopeItemsCompare}, key.Scopes)
+	assert.Equal(t, models.TierStandard, key.Tier)
+	assert.True(t, strings.HasPrefix(secret, apiKeyMarker+key.Prefix+"_"))
+	assert.NotContains(t, stored.Hash, secret)
+
+	mockRepo.On("GetByPrefix", mock.Anything, key.Prefix).Return(stored, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, int64(1), mock.Anything).Return(nil).Once()
+
+	principal, err := service.Authenticate(context.Background(), secret)
+	require.NoError(t, err)
+	assert.Equal(t, models.Principal{Name: "apikey:ci", Scopes: key.Scopes, Tier: models.TierStandard}, *principal)
+
+	// Una clave con el mismo prefijo y otro secreto no autentica.
+	tampered := secret[:len(secret)-1] + "0"
+	if tampered == secret {
+		tampered = secret[:len(secret)-1] + "1"
+	}
+	_, err = service.Authenticate(context.Background(), tampered)
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestAPIKeyService_CreateKey_Validation: El nombre y al menos un scope válido son
+// obligatorios y el tier, si se indica, debe existir
+func TestAPIKeyService_CreateKey_Validation(t *testing.T) {
+	service := NewAPIKeyService(new(MockAPIKeyRepository))
+
+	_, _, err := service.CreateKey(context.Background(), "", []string{models.ScopeAdmin}, "")
+	assertErrorCode(t, err, errors.ErrorCodeValidation)
+
+	_, _, err = service.CreateKey(context.Background(), "ci", []string{" "}, "")
+	assertErrorCode(t, err, errors.ErrorCodeValidation)
+
+	_, _, err = service.CreateKey(context.Background(), "ci", []string{"items:delete"}, "")
+	assertErrorCode(t, err, errors.ErrorCodeValidation)
+
+	_, _, err = service.CreateKey(context.Background(), "ci", []string{models.ScopeItemsRead}, models.TierAnonymous)
+	assertErrorCode(t, err, errors.ErrorCodeValidation)
+}
+
+// TestAPIKeyService_Authenticate_Rejected: Las claves mal formadas, desconocidas o
+// revocadas no autentican
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo).(*APIKeyServiceImpl)
+
+	_, err := service.Authenticate(context.Background(), "not-a-key")
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+
+	prefix, secret, err := generateAPIKey()
+	require.NoError(t, err)
+
+	mockRepo.On("GetByPrefix", mock.Anything, prefix).Return(nil, repositories.ErrNotFound).Once()
+	_, err = service.Authenticate(context.Background(), secret)
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+
+	revokedAt := time.Now()
+	mockRepo.On("GetByPrefix", mock.Anything, prefix).
+		Return(&models.APIKey{ID: 1, Prefix: prefix, Hash: hashAPIKey(secret), RevokedAt: &revokedAt}, nil).Once()
+	_, err = service.Authenticate(context.Background(), secret)
+	assertErrorCode(t, err, errors.ErrorCodeUnauthorized)
+
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAPIKeyService_Authenticate_LastUsedThrottled: El último uso se registra como
+// mucho una vez por minuto
+func TestAPIKeyService_Authenticate_LastUsedThrottled(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo).(*APIKeyServiceImpl)
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	prefix, secret, err := generateAPIKey()
+	require.NoError(t, err)
+	lastUsed := now.Add(-30 * time.Second)
+	mockRepo.On("GetByPrefix", mock.Anything, prefix).
+		Return(&models.APIKey{ID: 1, Prefix: prefix, Hash: hashAPIKey(secret), Scopes: []string{models.ScopeAdmin}, LastUsedAt: &lastUsed}, nil)
+
+	_, err = service.Authenticate(context.Background(), secret)
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+
+	now = now.Add(30 * time.Second)
+	mockRepo.On("TouchLastUsed", mock.Anything, int64(1), now).Return(nil).Once()
+	_, err = service.Authenticate(context.Background(), secret)
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestAPIKeyService_RotateAndRevoke: Rotar genera un nuevo prefijo y hash; las claves
+// revocadas no se pueden rotar ni revocar de nuevo
+func TestAPIKeyService_RotateAndRevoke(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	active := &models.APIKey{ID: 1, Name: "ci", Prefix: "00000000", Scopes: []string{models.ScopeItemsRead}}
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(active, nil)
+
+	var newPrefix, newHash string
+	mockRepo.On("Rotate", mock.Anything, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		newPrefix, newHash = args.String(2), args.String(3)
+	}).Return(nil)
+
+	key, secret, err := service.RotateKey(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, newPrefix, key.Prefix)
+	assert.NotEqual(t, "00000000", newPrefix)
+	assert.Equal(t, hashAPIKey(secret), newHash)
+	assert.NotNil(t, key.RotatedAt)
+
+	revokedAt := time.Now()
+	mockRepo.On("GetByID", mock.Anything, int64(2)).Return(&models.APIKey{ID: 2, RevokedAt: &revokedAt}, nil)
+	_, _, err = service.RotateKey(context.Background(), 2)
+	assertErrorCode(t, err, errors.ErrorCodeConflict)
+	assertErrorCode(t, service.RevokeKey(context.Background(), 2), errors.ErrorCodeConflict)
+
+	mockRepo.On("GetByID", mock.Anything, int64(3)).Return(nil, repositories.ErrNotFound)
+	assertErrorCode(t, service.RevokeKey(context.Background(), 3), errors.ErrorCodeNotFound)
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"project/internal/models"
+)
+
+// CategoryService define la lógica de negocio de las categorías de productos.
+type CategoryService interface {
+	// GetCategoryTree devuelve todas las categorías organizadas como árbol.
+	GetCategoryTree(ctx context.Context) ([]models.Category, error)
+
+	// GetCategoryItems devuelve una página de los ítems de la categoría indicada
+	// y de todas sus subcategorías, con los mismos filtros que el listado general.
+	// Si la categoría no existe, devuelve un error NotFound.
+	GetCategoryItems(ctx context.Context, id int64, query models.ItemQuery) (*models.ItemPage, error)
+
+	// GetSpecSchema devuelve el esquema de especificaciones de la categoría,
+	// incluidos los campos heredados de sus ancestros.
+	// Si la categoría no existe, devuelve un error NotFound.
+	GetSpecSchema(ctx context.Context, id int64) (*models.SpecSchema, error)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	stdErrors "errors"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+)
+
+// CategoryServiceImpl implementa CategoryService. El listado de ítems de una
+// categoría se delega en ItemService para reutilizar su validación y paginación.
+type CategoryServiceImpl struct {
+	repo  repositories.CategoryRepository
+	items ItemService
+}
+
+// NewCategoryService crea una nueva instancia del servicio de categorías.
+func NewCategoryService(repo repositories.CategoryRepository, items ItemService) CategoryService {
+	return &CategoryServiceImpl{repo: repo, items: items}
+}
+
+// GetCategoryTree obtiene todas las categorías y las organiza como árbol.
+func (s *CategoryServiceImpl) GetCategoryTree(ctx context.Context) ([]models.Category, error) {
+	categories, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener las categorías", err)
+	}
+
+	return models.BuildCategoryTree(categories), nil
+}
+
+// GetCategoryItems verifica que la categoría exista y lista sus ítems,
+// incluyendo los de sus subcategorías.
+func (s *CategoryServiceImpl) GetCategoryItems(ctx context.Context, id int64, query models.ItemQuery) (*models.ItemPage, error) {
+	if err := s.checkCategoryExists(ctx, id); err != nil {
+		return nil, err
+	}
+
+	query.CategoryID = &id
+	return s.items.GetAllItems(ctx, query)
+}
+
+// GetSpecSchema verifica que la categoría exista y resuelve su esquema de
+// especificaciones a partir de los campos propios y heredados.
+func (s *CategoryServiceImpl) GetSpecSchema(ctx context.Context, id int64) (*models.SpecSchema, error) {
+	if err := s.checkCategoryExists(ctx, id); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.repo.SpecFields(ctx, id)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener el esquema de especificaciones", err)
+	}
+
+	schema := models.ResolveSpecSchema(id, fields)
+	return &schema, nil
+}
+
+// checkCategoryExists valida el ID y traduce una categoría inexistente a NotFound.
+func (s *CategoryServiceImpl) checkCategoryExists(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return errors.NewValidationError("ID inválido", nil)
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		if stdErrors.Is(err, repositories.ErrNotFound) {
+			return errors.NewNotFoundError("Category", id)
+		}
+		return errors.NewInternalServerError("error al obtener la categoría", err)
+	}
+
+	return nil
+}
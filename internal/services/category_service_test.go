@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryRepository es una implementación mock de CategoryRepository para pruebas
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SpecFields(ctx context.Context, categoryID int64) ([]models.SpecField, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SpecField), args.Error(1)
+}
+
+// int64Ptr devuelve un puntero al valor recibido
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+// testCategories devuelve dos árboles: Computers > Laptops y Audio > Headphones
+func testCategories() []models.Category {
+	return []models.Category{
+		{ID: 4, Name: "Audio", Slug: "audio"},
+		{ID: 1, Name: "Computers", Slug: "computers"},
+		{ID: 5, Name: "Headphones", Slug: "headphones", ParentID: int64Ptr(4)},
+		{ID: 2, Name: "Laptops", Slug: "laptops", ParentID: int64Ptr(1)},
+	}
+}
+
+// TestCategoryService_GetCategoryTree_OK: La lista plana se devuelve como árbol
+func TestCategoryService_GetCategoryTree_OK(t *testing.T) {
+	mockCategories := new(MockCategoryRepository)
+	service := NewCategoryService(mockCategories, NewItemService(new(MockItemRepository)))
+
+	mockCategories.On("GetAll", mock.Anything).Return(testCategories(), nil)
+
+	tree, err := service.GetCategoryTree(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Audio", tree[0].Name)
+	assert.Equal(t, "Headphones", tree[0].Children[0].Name)
+	assert.Equal(t, "Computers", tree[1].Name)
+	assert.Equal(t, "Laptops", tree[1].Children[0].Name)
+
+	mockCategories.AssertExpectations(t)
+}
+
+// TestCategoryService_GetCategoryItems_OK: Se listan los items filtrando por la categoría
+func TestCategoryService_GetCategoryItems_OK(t *testing.T) {
+	mockCategories := new(MockCategoryRepository)
+	mockRepo := new(MockItemRepository)
+	service := NewCategoryService(mockCategories, NewItemService(mockRepo))
+
+	expectedQuery := models.ItemQuery{Limit: models.DefaultPageLimit, CategoryID: int64Ptr(1)}
+	items := []models.Item{{ID: 1, Name: "Laptop", CategoryID: int64Ptr(2)}}
+
+	mockCategories.On("GetByID", mock.Anything, int64(1)).Return(&testCategories()[1], nil)
+	mockRepo.On("GetAll", mock.Anything, expectedQuery).Return(items, nil)
+	mockRepo.On("Count", mock.Anything, expectedQuery).Return(1, nil)
+
+	page, err := service.GetCategoryItems(context.Background(), 1, models.ItemQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, items, page.Data)
+	assert.Equal(t, 1, page.Pagination.Total)
+
+	mockCategories.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCategoryService_GetCategoryItems_NotFound: Una categoría inexistente devuelve NotFound
+func TestCategoryService_GetCategoryItems_NotFound(t *testing.T) {
+	mockCategories := new(MockCategoryRepository)
+	mockRepo := new(MockItemRepository)
+	service := NewCategoryService(mockCategories, NewItemService(mockRepo))
+
+	mockCategories.On("GetByID", mock.Anything, int64(99)).Return(nil, repositories.ErrNotFound)
+
+	page, err := service.GetCategoryItems(context.Background(), 99, models.ItemQuery{})
+
+	assert.Nil(t, page)
+	var domainErr *errors.DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeNotFound, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+// TestService_CompareItems_UnrelatedCategories: Comparar items de árboles distintos
+// devuelve una advertencia, o un ValidationError en modo estricto
+func TestService_CompareItems_UnrelatedCategories(t *testing.T) {
+	items := []models.Item{
+		{ID: 1, Name: "Laptop", Price: 1000, Rating: 4, CategoryID: int64Ptr(2)},
+		{ID: 2, Name: "Headphones", Price: 300, Rating: 4, CategoryID: int64Ptr(5)},
+	}
+
+	t.Run("advertencia", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockCategories := new(MockCategoryRepository)
+		service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+		mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+		mockCategories.On("GetAll", mock.Anything).Return(testCategories(), nil)
+
+		response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Warnings, 1)
+		assert.Contains(t, response.Warnings[0], "Audio (items [2])")
+		assert.Contains(t, response.Warnings[0], "Computers (items [1])")
+	})
+
+	t.Run("estricto", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockCategories := new(MockCategoryRepository)
+		service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+		mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+		mockCategories.On("GetAll", mock.Anything).Return(testCategories(), nil)
+
+		response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}, Strict: true})
+
+		assert.Nil(t, response)
+		var domainErr *errors.DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	})
+}
+
+// TestService_CompareItems_RelatedCategories: Una categoría y su subcategoría
+// comparten raíz y no generan advertencias
+func TestService_CompareItems_RelatedCategories(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockCategories := new(MockCategoryRepository)
+	service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+	items := []models.Item{
+		{ID: 1, Name: "Laptop", Price: 1000, Rating: 4, CategoryID: int64Ptr(2)},
+		{ID: 2, Name: "Computer", Price: 1500, Rating: 4, CategoryID: int64Ptr(1)},
+		{ID: 3, Name: "Sin categoría", Price: 500, Rating: 3},
+	}
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2, 3}).Return(items, nil)
+	mockCategories.On("GetAll", mock.Anything).Return(testCategories(), nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2, 3}, Strict: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.Warnings)
+}
+
+// TestService_CreateItem_UnknownCategory: Asignar una categoría inexistente es un ValidationError
+func TestService_CreateItem_UnknownCategory(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockCategories := new(MockCategoryRepository)
+	service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+	item := validItem()
+	item.CategoryID = int64Ptr(42)
+	mockCategories.On("GetByID", mock.Anything, int64(42)).Return(nil, repositories.ErrNotFound)
+
+	created, err := service.CreateItem(context.Background(), item)
+
+	assert.Nil(t, created)
+	var domainErr *errors.DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// laptopSpecFields devuelve el esquema de Laptops (ID 2), que hereda memory de Computers (ID 1)
+func laptopSpecFields() []models.SpecField {
+	return []models.SpecField{
+		{CategoryID: 1, Key: "memory", Label: "Memory", Type: models.SpecTypeQuantity, Dimension: models.DimensionDataSize, Required: true},
+		{CategoryID: 2, Key: "battery_life", Label: "Battery life", Type: models.SpecTypeQuantity, Dimension: models.DimensionDuration},
+	}
+}
+
+// TestService_CreateItem_SpecSchemaViolations: Las especificaciones se validan contra
+// el esquema de la categoría y los errores se devuelven por campo
+func TestService_CreateItem_SpecSchemaViolations(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockCategories := new(MockCategoryRepository)
+	service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+	item := validItem()
+	item.Price = 0
+	item.CategoryID = int64Ptr(2)
+	item.Specifications = models.Specifications{"battery life": "10 hours"}
+
+	mockCategories.On("GetByID", mock.Anything, int64(2)).Return(&testCategories()[3], nil)
+	mockCategories.On("SpecFields", mock.Anything, int64(2)).Return(laptopSpecFields(), nil)
+
+	created, err := service.CreateItem(context.Background(), item)
+
+	assert.Nil(t, created)
+	var domainErr *errors.DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+
+	fields := make([]string, len(domainErr.Details))
+	for i, detail := range domainErr.Details {
+		fields[i] = detail.Field
+	}
+	assert.Equal(t, []string{"price", "specifications.battery life", "specifications.memory"}, fields)
+	assert.Contains(t, domainErr.Details[1].Message, `"battery_life"`)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestService_CreateItem_SpecSchemaOK: Un item que cumple el esquema se crea
+func TestService_CreateItem_SpecSchemaOK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockCategories := new(MockCategoryRepository)
+	service := NewItemService(mockRepo, WithCategories(mockCategories))
+
+	item := validItem()
+	item.CategoryID = int64Ptr(2)
+	item.Specifications = models.Specifications{"memory": "16GB", "battery_life": "Up to 10 hours"}
+
+	mockCategories.On("GetByID", mock.Anything, int64(2)).Return(&testCategories()[3], nil)
+	mockCategories.On("SpecFields", mock.Anything, int64(2)).Return(laptopSpecFields(), nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item")).Return(nil)
+
+	created, err := service.CreateItem(context.Background(), item)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, created)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCategoryService_GetSpecSchema_OK: El esquema incluye los campos heredados
+func TestCategoryService_GetSpecSchema_OK(t *testing.T) {
+	mockCategories := new(MockCategoryRepository)
+	service := NewCategoryService(mockCategories, NewItemService(new(MockItemRepository)))
+
+	mockCategories.On("GetByID", mock.Anything, int64(2)).Return(&testCategories()[3], nil)
+	mockCategories.On("SpecFields", mock.Anything, int64(2)).Return(laptopSpecFields(), nil)
+
+	schema, err := service.GetSpecSchema(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), schema.CategoryID)
+	assert.Len(t, schema.Fields, 2)
+	assert.True(t, schema.Fields[0].Inherited)
+	assert.Equal(t, "GB", schema.Fields[0].Unit)
+	assert.False(t, schema.Fields[1].Inherited)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"project/internal/models"
+)
+
+// Criterios de comparación que no provienen de las especificaciones.
+const (
+	CriterionPrice  = "price"
+	CriterionRating = "rating"
+)
+
+// ComparisonRules asocia cada criterio de comparación (price, rating o una clave
+// de especificación) con la dirección en la que un valor es mejor.
+// Las especificaciones sin regla usan la dirección por defecto de su dimensión.
+type ComparisonRules map[string]models.Direction
+
+// DefaultComparisonRules devuelve las reglas por defecto: precio más bajo,
+// rating más alto, más memoria y almacenamiento, menos peso y más batería.
+func DefaultComparisonRules() ComparisonRules {
+	return ComparisonRules{
+		CriterionPrice:  models.DirectionLower,
+		CriterionRating: models.DirectionHigher,
+		"memory":        models.DirectionHigher,
+		"storage":       models.DirectionHigher,
+		"weight":        models.DirectionLower,
+		"battery_life":  models.DirectionHigher,
+	}
+}
+
+// LoadComparisonRules lee un archivo JSON con reglas de dirección, por ejemplo
+// {"weight": "lower", "display": "higher"}, y las combina con las reglas por defecto.
+func LoadComparisonRules(path string) (ComparisonRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las reglas de comparación: %w", err)
+	}
+
+	var overrides ComparisonRules
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("error al interpretar las reglas de comparación: %w", err)
+	}
+
+	rules := DefaultComparisonRules()
+	for key, direction := range overrides {
+		if direction != models.DirectionHigher && direction != models.DirectionLower {
+			return nil, fmt.Errorf("dirección inválida para %q: %q (use \"higher\" o \"lower\")", key, direction)
+		}
+		rules[key] = direction
+	}
+
+	return rules, nil
+}
+
+// directionFor devuelve la dirección configurada para el criterio o, si no
+// existe una regla, la dirección por defecto indicada.
+func (r ComparisonRules) directionFor(criterion string, fallback models.Direction) models.Direction {
+	if direction, ok := r[criterion]; ok {
+		return direction
+	}
+	return fallback
+}
+
+// WithComparisonRules reemplaza las reglas de dirección usadas en las comparaciones.
+func WithComparisonRules(rules ComparisonRules) ItemServiceOption {
+	return func(s *ItemServiceImpl) {
+		s.rules = rules
+	}
+}
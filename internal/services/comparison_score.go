@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"project/internal/errors"
+	"project/internal/models"
+	"sort"
+	"strings"
+)
+
+// scoreCriterion reúne los valores comparables de un criterio para todos los items.
+type scoreCriterion struct {
+	unit      string
+	direction models.Direction
+	values    map[int64]float64
+}
+
+// ScoreItems puntúa los items de 0 a 100 ponderando cada criterio con el peso
+// indicado y devuelve el ranking de mayor a menor puntuación.
+// Los items se cargan y comparan con CompareItems; los criterios válidos son
+// price, rating y las especificaciones cuantificables de spec_ranges.
+func (s *ItemServiceImpl) ScoreItems(ctx context.Context, req models.ScoreRequest) (*models.ScoreResponse, error) {
+	if err := validateScoreWeights(req.Weights); err != nil {
+		return nil, err
+	}
+
+	comparison, err := s.CompareItems(ctx, models.CompareRequest{ItemIDs: req.ItemIDs, Strict: req.Strict})
+	if err != nil {
+		return nil, err
+	}
+
+	criteria := s.scoreCriteria(comparison)
+
+	var unknown []string
+	for criterion := range req.Weights {
+		if _, ok := criteria[criterion]; !ok {
+			unknown = append(unknown, criterion)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.NewValidationError(fmt.Sprintf(
+			"criterios desconocidos o no cuantificables para estos items: %s",
+			strings.Join(unknown, ", "),
+		), nil)
+	}
+
+	weights := normalizeWeights(req.Weights)
+	ranking := make([]models.ItemScore, 0, len(comparison.Items))
+
+	for _, item := range comparison.Items {
+		itemScore := models.ItemScore{
+			ItemID:    item.ID,
+			Name:      item.Name,
+			Breakdown: make(map[string]models.CriterionScore, len(weights)),
+		}
+
+		for name, weight := range weights {
+			criterionScore := scoreItemCriterion(item.ID, criteria[name], weight)
+			itemScore.Breakdown[name] = criterionScore
+			itemScore.Score += criterionScore.Contribution
+		}
+		itemScore.Score = roundScore(itemScore.Score)
+
+		ranking = append(ranking, itemScore)
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].ItemID < ranking[j].ItemID
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+
+	return &models.ScoreResponse{
+		Weights:  weights,
+		Ranking:  ranking,
+		Warnings: comparison.Warnings,
+	}, nil
+}
+
+// validateScoreWeights comprueba que haya al menos un criterio y que los pesos
+// sean no negativos con suma positiva.
+func validateScoreWeights(weights map[string]float64) error {
+	if len(weights) == 0 {
+		return errors.NewValidationError("se requiere al menos un criterio en weights", nil)
+	}
+
+	var total float64
+	for criterion, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return errors.NewValidationError(fmt.Sprintf("el peso de %q debe ser un número mayor o igual a 0", criterion), nil)
+		}
+		total += weight
+	}
+
+	if total <= 0 {
+		return errors.NewValidationError("la suma de los pesos debe ser mayor a 0", nil)
+	}
+
+	return nil
+}
+
+// scoreCriteria construye los criterios puntuables a partir de la comparación:
+// precio, rating y cada especificación con rango normalizado.
+func (s *ItemServiceImpl) scoreCriteria(comparison *models.CompareResponse) map[string]scoreCriterion {
+	criteria := make(map[string]scoreCriterion, len(comparison.Comparison.SpecRanges)+2)
+
+	prices := make(map[int64]float64, len(comparison.Items))
+	ratings := make(map[int64]float64, len(comparison.Items))
+	for _, item := range comparison.Items {
+		prices[item.ID] = item.Price
+		ratings[item.ID] = item.Rating
+	}
+
+	criteria[CriterionPrice] = scoreCriterion{
+		direction: s.rules.directionFor(CriterionPrice, models.DirectionLower),
+		values:    prices,
+	}
+	criteria[CriterionRating] = scoreCriterion{
+		direction: s.rules.directionFor(CriterionRating, models.DirectionHigher),
+		values:    ratings,
+	}
+
+	for key, specRange := range comparison.Comparison.SpecRanges {
+		criteria[key] = scoreCriterion{
+			unit:      specRange.Unit,
+			direction: specRange.Direction,
+			values:    specRange.Values,
+		}
+	}
+
+	return criteria
+}
+
+// scoreItemCriterion normaliza el valor del item dentro del criterio con min-max:
+// el mejor valor obtiene 100 y el peor 0. Si todos los valores coinciden, todos
+// obtienen 100; si el item no tiene valor, obtiene 0.
+func scoreItemCriterion(itemID int64, criterion scoreCriterion, weight float64) models.CriterionScore {
+	result := models.CriterionScore{
+		Unit:      criterion.unit,
+		Direction: criterion.direction,
+		Weight:    weight,
+	}
+
+	value, ok := criterion.values[itemID]
+	if !ok {
+		return result
+	}
+	result.Value = &value
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range criterion.values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	score := 100.0
+	if max > min {
+		if criterion.direction == models.DirectionLower {
+			score = (max - value) / (max - min) * 100
+		} else {
+			score = (value - min) / (max - min) * 100
+		}
+	}
+
+	result.Score = roundScore(score)
+	result.Contribution = roundScore(score * weight)
+	return result
+}
+
+// normalizeWeights escala los pesos para que sumen 1.
+func normalizeWeights(weights map[string]float64) map[string]float64 {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	normalized := make(map[string]float64, len(weights))
+	for criterion, weight := range weights {
+		normalized[criterion] = weight / total
+	}
+	return normalized
+}
+
+// roundScore redondea una puntuación a dos decimales.
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
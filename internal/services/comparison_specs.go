@@ -0,0 +1,166 @@
+package services
+
+import (
+	"project/internal/models"
+	"sort"
+)
+
+// buildSpecRanges normaliza los valores de cada especificación y calcula su
+// rango (mínimo/máximo) y el mejor valor entre los ítems comparados.
+//
+// Para cada clave se usa la dimensión del primer ítem cuyo valor puede
+// normalizarse; los valores de otra dimensión o no cuantificables se ignoran.
+// El mejor valor sigue la dirección configurada en las reglas de comparación
+// o, si la clave no tiene regla, la dirección por defecto de la dimensión.
+func (s *ItemServiceImpl) buildSpecRanges(items []models.Item) map[string]models.SpecRange {
+	ranges := make(map[string]models.SpecRange)
+
+	for _, key := range sortedSpecKeys(items) {
+		specRange, ok := normalizedSpecRange(items, key, s.rules)
+		if ok {
+			ranges[key] = specRange
+		}
+	}
+
+	return ranges
+}
+
+// findWinners determina los ítems ganadores de cada criterio: precio, rating y
+// cada especificación cuantificable. Los empates se incluyen todos.
+func (s *ItemServiceImpl) findWinners(items []models.Item, specRanges map[string]models.SpecRange) map[string][]int64 {
+	winners := make(map[string][]int64, len(specRanges)+2)
+	if len(items) == 0 {
+		return winners
+	}
+
+	prices := make(map[int64]float64, len(items))
+	ratings := make(map[int64]float64, len(items))
+	for _, item := range items {
+		prices[item.ID] = item.Price
+		ratings[item.ID] = item.Rating
+	}
+
+	winners[CriterionPrice] = bestItems(items, prices, s.rules.directionFor(CriterionPrice, models.DirectionLower))
+	winners[CriterionRating] = bestItems(items, ratings, s.rules.directionFor(CriterionRating, models.DirectionHigher))
+
+	for key, specRange := range specRanges {
+		winners[key] = specRange.BestItemIDs
+	}
+
+	return winners
+}
+
+// tallyWinners cuenta cuántos criterios gana cada ítem. Todos los ítems
+// comparados aparecen en el resultado, aunque no ganen ningún criterio.
+func tallyWinners(items []models.Item, winners map[string][]int64) map[int64]int {
+	tally := make(map[int64]int, len(items))
+	for _, item := range items {
+		tally[item.ID] = 0
+	}
+
+	for _, ids := range winners {
+		for _, id := range ids {
+			tally[id]++
+		}
+	}
+
+	return tally
+}
+
+// bestItems devuelve, en el orden de items, los IDs cuyo valor es el mejor
+// según la dirección indicada.
+func bestItems(items []models.Item, values map[int64]float64, direction models.Direction) []int64 {
+	first := true
+	var best float64
+
+	for _, item := range items {
+		value, ok := values[item.ID]
+		if !ok {
+			continue
+		}
+		if first || (direction == models.DirectionLower && value < best) || (direction == models.DirectionHigher && value > best) {
+			best = value
+			first = false
+		}
+	}
+
+	ids := []int64{}
+	for _, item := range items {
+		if value, ok := values[item.ID]; ok && value == best {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// normalizedSpecRange calcula el SpecRange de una clave. Devuelve false si
+// ningún ítem tiene un valor cuantificable para ella.
+func normalizedSpecRange(items []models.Item, key string, rules ComparisonRules) (models.SpecRange, bool) {
+	var specRange models.SpecRange
+	found := false
+
+	for _, item := range items {
+		raw, ok := item.Specifications[key]
+		if !ok {
+			continue
+		}
+
+		normalized, ok := models.NormalizeSpecValue(raw)
+		if !ok {
+			continue
+		}
+
+		if !found {
+			found = true
+			specRange = models.SpecRange{
+				Dimension: normalized.Dimension,
+				Unit:      normalized.Unit,
+				Direction: rules.directionFor(key, normalized.Dimension.DefaultDirection()),
+				Min:       normalized.Value,
+				Max:       normalized.Value,
+				Values:    make(map[int64]float64),
+			}
+		} else if normalized.Dimension != specRange.Dimension {
+			continue
+		}
+
+		specRange.Values[item.ID] = normalized.Value
+		if normalized.Value < specRange.Min {
+			specRange.Min = normalized.Value
+		}
+		if normalized.Value > specRange.Max {
+			specRange.Max = normalized.Value
+		}
+	}
+
+	if !found {
+		return specRange, false
+	}
+
+	specRange.Best = specRange.Max
+	if specRange.Direction == models.DirectionLower {
+		specRange.Best = specRange.Min
+	}
+	specRange.BestItemIDs = bestItems(items, specRange.Values, specRange.Direction)
+
+	return specRange, true
+}
+
+// sortedSpecKeys devuelve las claves de especificación presentes en al menos
+// un ítem, ordenadas alfabéticamente.
+func sortedSpecKeys(items []models.Item) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+
+	for _, item := range items {
+		for key := range item.Specifications {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"sort"
+	"strings"
+)
+
+// validateItemWrite valida un ítem antes de crearlo o actualizarlo: los campos
+// básicos, la existencia de su categoría y sus especificaciones contra el
+// esquema de la categoría. Todos los problemas se devuelven juntos.
+func (s *ItemServiceImpl) validateItemWrite(ctx context.Context, item *models.Item) error {
+	fields := itemFieldErrors(item)
+
+	categoryFields, err := s.categoryFieldErrors(ctx, item)
+	if err != nil {
+		return err
+	}
+	fields = append(fields, categoryFields...)
+
+	if len(fields) > 0 {
+		return itemValidationError(fields)
+	}
+	return nil
+}
+
+// categoryFieldErrors comprueba que la categoría asignada a un ítem exista y que
+// sus especificaciones cumplan el esquema de la categoría (incluidos los campos
+// heredados). Un ítem sin categoría no se valida contra ningún esquema.
+func (s *ItemServiceImpl) categoryFieldErrors(ctx context.Context, item *models.Item) ([]errors.FieldError, error) {
+	if item.CategoryID == nil {
+		return nil, nil
+	}
+
+	categoryID := *item.CategoryID
+	if categoryID <= 0 {
+		return []errors.FieldError{{Field: "category_id", Message: "category_id debe ser un entero positivo"}}, nil
+	}
+
+	if s.categories == nil {
+		return nil, nil
+	}
+
+	if _, err := s.categories.GetByID(ctx, categoryID); err != nil {
+		if stdErrors.Is(err, repositories.ErrNotFound) {
+			return []errors.FieldError{{Field: "category_id", Message: fmt.Sprintf("la categoría %d no existe", categoryID)}}, nil
+		}
+		return nil, errors.NewInternalServerError("error al obtener la categoría", err)
+	}
+
+	specFields, err := s.categories.SpecFields(ctx, categoryID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener el esquema de especificaciones", err)
+	}
+
+	var fields []errors.FieldError
+	for _, problem := range models.ResolveSpecSchema(categoryID, specFields).Validate(item.Specifications) {
+		fields = append(fields, errors.FieldError{
+			Field:   "specifications." + problem.Key,
+			Message: fmt.Sprintf("especificación %q: %s", problem.Key, problem.Message),
+		})
+	}
+
+	return fields, nil
+}
+
+// checkCategories verifica que los ítems comparados pertenezcan al mismo árbol
+// de categorías, es decir, que compartan la categoría raíz. Los ítems sin
+// categoría no se tienen en cuenta.
+// Si hay más de una raíz devuelve una advertencia o, con strict, un error de validación.
+func (s *ItemServiceImpl) checkCategories(ctx context.Context, items []models.Item, strict bool) ([]string, error) {
+	if s.categories == nil {
+		return nil, nil
+	}
+
+	categorized := 0
+	for _, item := range items {
+		if item.CategoryID != nil {
+			categorized++
+		}
+	}
+	if categorized < 2 {
+		return nil, nil
+	}
+
+	categories, err := s.categories.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener las categorías", err)
+	}
+
+	byID := make(map[int64]models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	groups := make(map[int64][]int64)
+	for _, item := range items {
+		if item.CategoryID == nil {
+			continue
+		}
+		root := models.RootCategoryID(*item.CategoryID, byID)
+		groups[root] = append(groups[root], item.ID)
+	}
+
+	if len(groups) < 2 {
+		return nil, nil
+	}
+
+	descriptions := make([]string, 0, len(groups))
+	for root, ids := range groups {
+		name := fmt.Sprintf("categoría %d", root)
+		if category, ok := byID[root]; ok {
+			name = category.Name
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s (items %v)", name, ids))
+	}
+	sort.Strings(descriptions)
+
+	message := "los items pertenecen a categorías no relacionadas: " + strings.Join(descriptions, ", ")
+	if strict {
+		return nil, errors.NewValidationError(message, nil)
+	}
+
+	return []string{message}, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"project/internal/currency"
+	"project/internal/errors"
+	"project/internal/models"
+	"strings"
+)
+
+// ConvertPrices normaliza y valida el código de moneda y convierte a esa moneda el
+// precio de cada ítem. Todos los precios se convierten con los mismos tipos de cambio.
+func (s *ItemServiceImpl) ConvertPrices(ctx context.Context, items []models.Item, code string) error {
+	target, err := resolveCurrency(code)
+	if err != nil {
+		return err
+	}
+	return s.newPriceConverter(target).convertItems(ctx, items)
+}
+
+// resolveCurrency normaliza un código de moneda recibido como parámetro y comprueba
+// que sea una de las monedas soportadas.
+func resolveCurrency(code string) (string, error) {
+	code = models.NormalizeCurrency(code)
+	if !models.IsSupportedCurrency(code) {
+		return "", errors.NewValidationError(unsupportedCurrencyMessage(code), nil)
+	}
+	return code, nil
+}
+
+// unsupportedCurrencyMessage describe el error de una moneda no soportada.
+func unsupportedCurrencyMessage(code string) string {
+	return fmt.Sprintf("moneda no soportada: %q (debe ser una de %s)", code, strings.Join(models.SupportedCurrencies, ", "))
+}
+
+// comparisonCurrency devuelve la moneda en la que se comparan los ítems: la pedida
+// o, si no se pidió ninguna, la común a todos los ítems o DefaultCurrency si difieren.
+func comparisonCurrency(requested string, items []models.Item) (string, error) {
+	if requested != "" {
+		return resolveCurrency(requested)
+	}
+	for _, item := range items {
+		if item.Currency != items[0].Currency {
+			return models.DefaultCurrency, nil
+		}
+	}
+	if len(items) == 0 || items[0].Currency == "" {
+		return models.DefaultCurrency, nil
+	}
+	return items[0].Currency, nil
+}
+
+// setPriceRates completa query.PriceRates para que los filtros y el orden por precio
+// comparen todos los ítems en la moneda de la consulta (DefaultCurrency si no indica
+// una). Sin proveedor de tipos de cambio, o si la consulta no usa el precio, no hace nada.
+func (s *ItemServiceImpl) setPriceRates(ctx context.Context, query *models.ItemQuery) error {
+	if s.rates == nil || !usesPrice(*query) {
+		return nil
+	}
+
+	target := query.Currency
+	if target == "" {
+		target = models.DefaultCurrency
+	}
+
+	converter := s.newPriceConverter(target)
+	rates := make(map[string]float64, len(models.SupportedCurrencies))
+	for _, code := range models.SupportedCurrencies {
+		rate, err := converter.rate(ctx, code)
+		if err != nil {
+			return err
+		}
+		rates[code] = rate
+	}
+	query.PriceRates = rates
+	return nil
+}
+
+// usesPrice indica si la consulta filtra u ordena por precio.
+func usesPrice(query models.ItemQuery) bool {
+	if query.MinPrice != nil || query.MaxPrice != nil {
+		return true
+	}
+	for _, field := range query.Sort {
+		if field.Field == models.SortByPrice {
+			return true
+		}
+	}
+	return false
+}
+
+// priceConverter convierte precios a la moneda target. Guarda los tipos de cambio
+// obtenidos para que todos los precios de una respuesta usen los mismos.
+type priceConverter struct {
+	provider currency.ExchangeRateProvider
+	target   string
+	rates    map[string]float64
+}
+
+// newPriceConverter crea un conversor a target (DefaultCurrency si está vacía) con
+// el proveedor del servicio.
+func (s *ItemServiceImpl) newPriceConverter(target string) *priceConverter {
+	if target == "" {
+		target = models.DefaultCurrency
+	}
+	return &priceConverter{provider: s.rates, target: target, rates: make(map[string]float64)}
+}
+
+// rate devuelve el tipo de cambio de from a la moneda del conversor. Convertir entre
+// monedas distintas sin proveedor configurado es un error de la petición.
+func (c *priceConverter) rate(ctx context.Context, from string) (float64, error) {
+	if from == c.target {
+		return 1, nil
+	}
+	if rate, ok := c.rates[from]; ok {
+		return rate, nil
+	}
+	if c.provider == nil {
+		return 0, errors.NewBadRequestError(
+			fmt.Sprintf("no se puede convertir de %s a %s: la conversión de monedas no está configurada", from, c.target), nil,
+		)
+	}
+
+	rate, err := c.provider.Rate(ctx, from, c.target)
+	if err != nil {
+		return 0, errors.NewInternalServerError(fmt.Sprintf("error al obtener el tipo de cambio de %s a %s", from, c.target), err)
+	}
+	c.rates[from] = rate
+	return rate, nil
+}
+
+// convert convierte amount de from a la moneda del conversor, redondeado a centavos.
+// Un importe sin moneda está en DefaultCurrency.
+func (c *priceConverter) convert(ctx context.Context, amount float64, from string) (float64, error) {
+	if from == "" {
+		from = models.DefaultCurrency
+	}
+	if from == c.target {
+		return amount, nil
+	}
+	rate, err := c.rate(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	return currency.Round(amount * rate), nil
+}
+
+// convertItems convierte el precio de cada ítem y actualiza su moneda.
+func (c *priceConverter) convertItems(ctx context.Context, items []models.Item) error {
+	for i := range items {
+		price, err := c.convert(ctx, items[i].Price, items[i].Currency)
+		if err != nil {
+			return err
+		}
+		items[i].Price, items[i].Currency = price, c.target
+	}
+	return nil
+}
+
+// lowestPrices reduce los precios más bajos por moneda de cada ítem al menor de
+// ellos una vez convertidos.
+func (c *priceConverter) lowestPrices(ctx context.Context, byCurrency map[int64]map[string]float64) (map[int64]float64, error) {
+	lowest := make(map[int64]float64, len(byCurrency))
+	for id, prices := range byCurrency {
+		found := false
+		for code, price := range prices {
+			converted, err := c.convert(ctx, price, code)
+			if err != nil {
+				return nil, err
+			}
+			if !found || converted < lowest[id] {
+				lowest[id] = converted
+				found = true
+			}
+		}
+	}
+	return lowest, nil
+}
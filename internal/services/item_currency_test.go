@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"project/internal/currency"
+	"project/internal/errors"
+	"project/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testRates son los tipos de cambio de los tests: 1 USD = 0.8 EUR = 4000 COP.
+var testRates = currency.NewStaticProvider(currency.RateTable{
+	Base:  "USD",
+	Rates: map[string]float64{"EUR": 0.8, "COP": 4000},
+})
+
+// TestService_CreateItem_Currency: La moneda se normaliza, USD es la moneda por
+// defecto y las monedas no soportadas son un error de validación
+func TestService_CreateItem_Currency(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	item := validItem()
+	created, err := service.CreateItem(context.Background(), item)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", created.Currency)
+
+	item.Currency = " eur "
+	created, err = service.CreateItem(context.Background(), item)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", created.Currency)
+
+	item.Currency = "GBP"
+	_, err = service.CreateItem(context.Background(), item)
+	var domainErr *errors.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Equal(t, "currency", domainErr.Details[0].Field)
+}
+
+// TestService_CompareItems_ConvertsPrices: Los precios se convierten a una misma
+// moneda antes de calcular el rango de precios
+func TestService_CompareItems_ConvertsPrices(t *testing.T) {
+	items := func() []models.Item {
+		first, second := validItem(), validItem()
+		first.ID, first.Price, first.Currency = 1, 100, "USD"
+		second.ID, second.Price, second.Currency = 2, 96, "EUR"
+		return []models.Item{first, second}
+	}
+
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo, WithExchangeRates(testRates))
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items(), nil).Once()
+
+	// Con monedas distintas y sin moneda pedida se compara en USD.
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", response.Comparison.Currency)
+	assert.Equal(t, models.PriceRange{Min: 100, Max: 120}, response.Comparison.PriceRange)
+	assert.Equal(t, 120.0, response.Items[1].Price)
+	assert.Equal(t, "USD", response.Items[1].Currency)
+
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items(), nil).Once()
+	response, err = service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}, Currency: "cop"})
+	require.NoError(t, err)
+	assert.Equal(t, "COP", response.Comparison.Currency)
+	assert.Equal(t, models.PriceRange{Min: 400000, Max: 480000}, response.Comparison.PriceRange)
+}
+
+// TestService_CompareItems_LowestPricesConverted: El precio más bajo de los últimos
+// 30 días es el menor de los registrados en cada moneda una vez convertidos
+func TestService_CompareItems_LowestPricesConverted(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockPrices := new(MockPriceHistoryRepository)
+	service := NewItemService(mockRepo, WithPriceHistory(mockPrices), WithExchangeRates(testRates)).(*ItemServiceImpl)
+	service.now = func() time.Time { return priceTestNow }
+
+	first, second := validItem(), validItem()
+	first.ID, first.Currency = 1, "EUR"
+	second.ID, second.Currency = 2, "EUR"
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return([]models.Item{first, second}, nil)
+	mockPrices.On("LowestPrices", mock.Anything, []int64{1, 2}, mock.Anything).
+		Return(map[int64]map[string]float64{1: {"EUR": 90, "USD": 100}, 2: {"EUR": 100, "USD": 110}}, nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", response.Comparison.Currency)
+	assert.Equal(t, map[int64]float64{1: 80, 2: 88}, response.Comparison.LowestPrices30d)
+}
+
+// TestService_GetAllItems_Currency: Los filtros y el orden por precio usan los tipos
+// de cambio a la moneda pedida y los precios de la página se convierten
+func TestService_GetAllItems_Currency(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo, WithExchangeRates(testRates))
+
+	minPrice := 50.0
+	query := models.ItemQuery{MinPrice: &minPrice, Sort: []models.SortField{{Field: "price"}}, Currency: "eur"}
+	expectedQuery := query
+	expectedQuery.Limit = models.DefaultPageLimit
+	expectedQuery.Currency = "EUR"
+	expectedQuery.PriceRates = map[string]float64{"USD": 0.8, "EUR": 1, "COP": 0.0002}
+
+	mockRepo.On("GetAll", mock.Anything, expectedQuery).
+		Return([]models.Item{{ID: 1, Price: 100, Currency: "USD"}, {ID: 2, Price: 500000, Currency: "COP"}}, nil)
+	mockRepo.On("Count", mock.Anything, expectedQuery).Return(2, nil)
+
+	page, err := service.GetAllItems(context.Background(), query)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.Item{{ID: 1, Price: 80, Currency: "EUR"}, {ID: 2, Price: 100, Currency: "EUR"}}, page.Data)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_ConvertPrices_Errors: Una moneda no soportada es un error de validación
+// y convertir sin proveedor de tipos de cambio, un error de la petición
+func TestService_ConvertPrices_Errors(t *testing.T) {
+	service := NewItemService(new(MockItemRepository))
+	var domainErr *errors.DomainError
+
+	err := service.ConvertPrices(context.Background(), []models.Item{{Price: 10, Currency: "USD"}}, "GBP")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+
+	// Sin conversión necesaria, el proveedor no hace falta.
+	items := []models.Item{{Price: 10, Currency: "USD"}}
+	assert.NoError(t, service.ConvertPrices(context.Background(), items, "usd"))
+
+	items = []models.Item{{Price: 10, Currency: "USD"}}
+	err = service.ConvertPrices(context.Background(), items, "EUR")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeBadRequest, domainErr.Code)
+}
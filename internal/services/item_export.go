@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"project/internal/errors"
+	"project/internal/models"
+)
+
+// ExportItems valida los filtros y escribe en w los items que los cumplen, ordenados
+// por ID. Los items se leen de la base de datos con un cursor y se escriben de a uno,
+// con el precio convertido a query.Currency si la consulta indica una moneda.
+//
+// Las columnas de especificación son las claves de todo el catálogo, no solo las de
+// los items exportados, para que el formato no cambie según los filtros. Si w
+// devuelve un error (por ejemplo, porque el cliente cerró la conexión), la
+// exportación se interrumpe y se devuelve ese error.
+func (s *ItemServiceImpl) ExportItems(ctx context.Context, query models.ItemQuery, w models.ExportWriter) error {
+	specKeys, err := s.repo.SpecKeys(ctx)
+	if err != nil {
+		return errors.NewInternalServerError("error al obtener las claves de especificación", err)
+	}
+
+	query.Currency = models.NormalizeCurrency(query.Currency)
+	if err := validateExportQuery(query, specKeys); err != nil {
+		return err
+	}
+	if err := s.setPriceRates(ctx, &query); err != nil {
+		return err
+	}
+
+	// La exportación no se pagina y su orden es estable.
+	query.Limit, query.Offset, query.Sort = 0, 0, nil
+
+	if err := w.WriteHeader(specKeys); err != nil {
+		return err
+	}
+
+	// Los errores de escritura y de conversión se devuelven tal cual; los del cursor
+	// son internos.
+	var converter *priceConverter
+	if query.Currency != "" {
+		converter = s.newPriceConverter(query.Currency)
+	}
+	writeFailed := false
+	err = s.repo.Stream(ctx, query, func(item models.Item) error {
+		if converter != nil {
+			items := []models.Item{item}
+			if err := converter.convertItems(ctx, items); err != nil {
+				writeFailed = true
+				return err
+			}
+			item = items[0]
+		}
+		if err := w.WriteItem(item); err != nil {
+			writeFailed = true
+			return err
+		}
+		return nil
+	})
+	if err != nil && !writeFailed {
+		return errors.NewInternalServerError("error al exportar los items", err)
+	}
+	return err
+}
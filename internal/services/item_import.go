@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"strings"
+)
+
+// errDryRun revierte la transacción de una importación de prueba.
+var errDryRun = stdErrors.New("dry run")
+
+// ImportItems aplica las filas de un archivo de importación en una única transacción,
+// leyéndolas de a una. El handler recibe el archivo completo antes de llamar a este
+// método, para que la transacción no espere al cliente.
+//
+// Las filas con id actualizan el item existente y las demás crean uno nuevo. Cada fila
+// se valida con las mismas reglas que CreateItem y UpdateItem; las inválidas se
+// rechazan con sus motivos sin interrumpir la importación. Un error de lectura o de
+// base de datos revierte la transacción completa. Con dryRun la transacción se
+// revierte siempre, después de haber aplicado todas las filas. Los cambios quedan en
+// el historial de revisiones con origen models.SourceImport.
+func (s *ItemServiceImpl) ImportItems(ctx context.Context, rows models.ImportReader, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{
+		DryRun:   dryRun,
+		Accepted: []models.ImportAccepted{},
+		Rejected: []models.ImportRejected{},
+	}
+
+	ctx = models.WithSource(ctx, models.SourceImport)
+	err := s.repo.InTransaction(ctx, func(tx repositories.ItemRepository) error {
+		for {
+			row, err := rows.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			report.Total++
+			if err := s.importRow(ctx, tx, row, report); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+
+	if err != nil && !stdErrors.Is(err, errDryRun) {
+		return nil, importError(err)
+	}
+
+	if dryRun {
+		// Los IDs asignados dentro de la transacción revertida no existen.
+		for i := range report.Accepted {
+			if report.Accepted[i].Action == models.ImportActionCreated {
+				report.Accepted[i].ID = 0
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// importError convierte un error de la importación en un error de dominio.
+func importError(err error) error {
+	var domainErr *errors.DomainError
+	if stdErrors.As(err, &domainErr) {
+		return domainErr
+	}
+	return errors.NewInternalServerError("error al importar los items", err)
+}
+
+// importRow valida y aplica una fila dentro de la transacción, registrando el
+// resultado en el informe. Solo devuelve error si la importación debe abortarse.
+func (s *ItemServiceImpl) importRow(ctx context.Context, tx repositories.ItemRepository, row models.ImportRow, report *models.ImportReport) error {
+	item := row.Item
+
+	reject := func(reasons []errors.FieldError) {
+		report.Rejected = append(report.Rejected, models.ImportRejected{
+			Line:    row.Line,
+			Name:    strings.TrimSpace(item.Name),
+			Reasons: reasons,
+		})
+	}
+
+	if len(row.Problems) > 0 {
+		reject(row.Problems)
+		return nil
+	}
+
+	if item.ID < 0 {
+		reject([]errors.FieldError{{Field: "id", Message: "el id debe ser un entero positivo"}})
+		return nil
+	}
+
+	if err := s.validateItemWrite(ctx, &item); err != nil {
+		var domainErr *errors.DomainError
+		if stdErrors.As(err, &domainErr) && domainErr.Code == errors.ErrorCodeValidation {
+			reject(domainErr.Details)
+			return nil
+		}
+		return err
+	}
+
+	action := models.ImportActionCreated
+	if item.ID == 0 {
+		if err := tx.Create(ctx, &item); err != nil {
+			return errors.NewInternalServerError("error al crear el item", err)
+		}
+	} else {
+		action = models.ImportActionUpdated
+		if err := tx.Update(ctx, &item); err != nil {
+			if stdErrors.Is(err, repositories.ErrNotFound) {
+				reject([]errors.FieldError{{Field: "id", Message: fmt.Sprintf("no existe un item con id %d", item.ID)}})
+				return nil
+			}
+			return errors.NewInternalServerError("error al actualizar el item", err)
+		}
+	}
+
+	report.Accepted = append(report.Accepted, models.ImportAccepted{
+		Line:   row.Line,
+		ID:     item.ID,
+		Name:   item.Name,
+		Action: action,
+	})
+	return nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"project/internal/errors"
+	"project/internal/models"
+	"time"
+)
+
+const (
+	// lowestPriceWindow es la ventana del precio más bajo de las comparaciones.
+	lowestPriceWindow = 30 * 24 * time.Hour
+
+	defaultPriceHistoryDays = 30
+	maxPriceHistoryDays     = 365
+)
+
+// PriceHistory devuelve los precios del ítem en los últimos query.Days días (30 por
+// defecto) con su mínimo, máximo y promedio ponderado por tiempo. Con un intervalo,
+// los precios se agrupan en buckets diarios o semanales con las mismas estadísticas.
+// Todos los precios se expresan en query.Currency o, si no indica una, en la moneda
+// actual del ítem; los anteriores se convierten con el tipo de cambio actual.
+func (s *ItemServiceImpl) PriceHistory(ctx context.Context, id int64, query models.PriceHistoryQuery) (*models.PriceHistory, error) {
+	if s.prices == nil {
+		return nil, errors.NewInternalServerError("el historial de precios no está disponible", nil)
+	}
+
+	if query.Days == 0 {
+		query.Days = defaultPriceHistoryDays
+	}
+	if query.Days < 1 || query.Days > maxPriceHistoryDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("days debe estar entre 1 y %d", maxPriceHistoryDays), nil)
+	}
+	if query.Interval != "" && query.Interval != models.PriceIntervalDaily && query.Interval != models.PriceIntervalWeekly {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("interval debe ser %q o %q", models.PriceIntervalDaily, models.PriceIntervalWeekly), nil,
+		)
+	}
+
+	var target string
+	if query.Currency != "" {
+		var err error
+		if target, err = resolveCurrency(query.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	item, err := s.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		target = item.Currency
+	}
+	converter := s.newPriceConverter(target)
+
+	to := s.now().UTC().Truncate(time.Second)
+	from := to.AddDate(0, 0, -query.Days)
+
+	points, err := s.prices.PriceHistory(ctx, id, from)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener el historial de precios", err)
+	}
+	if len(points) == 0 {
+		// Sin historial registrado, el precio actual rige desde el inicio de la ventana.
+		points = []models.PricePoint{{Price: item.Price, Currency: item.Currency, RecordedAt: from}}
+	}
+	for i := range points {
+		if points[i].Price, err = converter.convert(ctx, points[i].Price, points[i].Currency); err != nil {
+			return nil, err
+		}
+		points[i].Currency = converter.target
+	}
+
+	currentPrice, err := converter.convert(ctx, item.Price, item.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.PriceHistory{
+		ItemID:       id,
+		Currency:     converter.target,
+		CurrentPrice: currentPrice,
+		From:         from,
+		To:           to,
+		Interval:     query.Interval,
+	}
+	history.Stats, _ = summarizePrices(points, from, to)
+
+	if query.Interval == "" {
+		history.Points = points
+	} else {
+		history.Buckets = bucketPrices(points, from, to, query.Interval)
+	}
+
+	return history, nil
+}
+
+// bucketPrices agrupa los precios vigentes entre from y to en días o semanas UTC.
+// El primer y el último bucket se recortan a la ventana.
+func bucketPrices(points []models.PricePoint, from, to time.Time, interval string) []models.PriceBucket {
+	buckets := []models.PriceBucket{}
+	for start := bucketStart(from, interval); start.Before(to); {
+		end := start.AddDate(0, 0, 1)
+		if interval == models.PriceIntervalWeekly {
+			end = start.AddDate(0, 0, 7)
+		}
+
+		stats, ok := summarizePrices(points, laterTime(start, from), earlierTime(end, to))
+		if ok {
+			buckets = append(buckets, models.PriceBucket{Start: start, PriceStats: stats})
+		}
+		start = end
+	}
+	return buckets
+}
+
+// bucketStart devuelve el inicio del día o de la semana (el lunes) UTC que contiene t.
+func bucketStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == models.PriceIntervalWeekly {
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	}
+	return day
+}
+
+// summarizePrices calcula el mínimo, el máximo y el promedio ponderado por tiempo de
+// los precios vigentes entre start y end. Cada punto rige hasta el siguiente. Devuelve
+// false si ningún precio rige en ese período (por ejemplo, antes del alta del ítem).
+func summarizePrices(points []models.PricePoint, start, end time.Time) (models.PriceStats, bool) {
+	var stats models.PriceStats
+	var weighted, total float64
+	found := false
+
+	for i, point := range points {
+		segmentStart := laterTime(point.RecordedAt, start)
+		segmentEnd := end
+		if i+1 < len(points) {
+			segmentEnd = earlierTime(points[i+1].RecordedAt, end)
+		}
+		if !segmentEnd.After(segmentStart) {
+			continue
+		}
+
+		if !found || point.Price < stats.Min {
+			stats.Min = point.Price
+		}
+		if !found || point.Price > stats.Max {
+			stats.Max = point.Price
+		}
+		found = true
+
+		duration := segmentEnd.Sub(segmentStart).Seconds()
+		weighted += point.Price * duration
+		total += duration
+	}
+
+	if !found {
+		return stats, false
+	}
+	stats.Avg = math.Round(weighted/total*100) / 100
+	return stats, true
+}
+
+// laterTime devuelve el mayor de a y b.
+func laterTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// earlierTime devuelve el menor de a y b.
+func earlierTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"project/internal/errors"
+	"project/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPriceHistoryRepository es una implementación mock de PriceHistoryRepository para pruebas
+type MockPriceHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceHistoryRepository) PriceHistory(ctx context.Context, itemID int64, since time.Time) ([]models.PricePoint, error) {
+	args := m.Called(ctx, itemID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PricePoint), args.Error(1)
+}
+
+func (m *MockPriceHistoryRepository) LowestPrices(ctx context.Context, itemIDs []int64, since time.Time) (map[int64]map[string]float64, error) {
+	args := m.Called(ctx, itemIDs, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]map[string]float64), args.Error(1)
+}
+
+// priceTestNow es el momento actual de los tests de precios: miércoles 12 de marzo de 2025.
+var priceTestNow = time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC)
+
+// newPriceTestService crea un servicio con historial de precios y el reloj fijo en priceTestNow.
+func newPriceTestService(repo *MockItemRepository, prices *MockPriceHistoryRepository) *ItemServiceImpl {
+	service := NewItemService(repo, WithPriceHistory(prices)).(*ItemServiceImpl)
+	service.now = func() time.Time { return priceTestNow }
+	return service
+}
+
+// TestService_PriceHistory_Buckets: Las estadísticas se ponderan por el tiempo que
+// rige cada precio, también dentro de cada bucket diario o semanal
+func TestService_PriceHistory_Buckets(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockPrices := new(MockPriceHistoryRepository)
+	service := newPriceTestService(mockRepo, mockPrices)
+
+	item := validItem()
+	item.ID = 1
+	item.Price = 80
+	from := priceTestNow.AddDate(0, 0, -7)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&item, nil)
+	mockPrices.On("PriceHistory", mock.Anything, int64(1), from).Return([]models.PricePoint{
+		{Price: 100, RecordedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Price: 80, RecordedAt: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	history, err := service.PriceHistory(context.Background(), 1, models.PriceHistoryQuery{Days: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, from, history.From)
+	assert.Equal(t, 80.0, history.CurrentPrice)
+	// 100 durante 5 días y 80 durante 2.
+	assert.Equal(t, models.PriceStats{Min: 80, Max: 100, Avg: 94.29}, history.Stats)
+	assert.Len(t, history.Points, 2)
+	assert.Nil(t, history.Buckets)
+
+	history, err = service.PriceHistory(context.Background(), 1, models.PriceHistoryQuery{Days: 7, Interval: models.PriceIntervalDaily})
+	assert.NoError(t, err)
+	assert.Nil(t, history.Points)
+	assert.Len(t, history.Buckets, 8)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), history.Buckets[0].Start)
+	assert.Equal(t, models.PriceStats{Min: 80, Max: 100, Avg: 90}, history.Buckets[5].PriceStats)
+
+	history, err = service.PriceHistory(context.Background(), 1, models.PriceHistoryQuery{Days: 7, Interval: models.PriceIntervalWeekly})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PriceBucket{
+		{Start: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), PriceStats: models.PriceStats{Min: 100, Max: 100, Avg: 100}},
+		{Start: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), PriceStats: models.PriceStats{Min: 80, Max: 100, Avg: 84}},
+	}, history.Buckets)
+}
+
+// TestService_PriceHistory_InvalidQuery: La ventana y el intervalo se validan antes de consultar
+func TestService_PriceHistory_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query models.PriceHistoryQuery
+	}{
+		{name: "negative days", query: models.PriceHistoryQuery{Days: -1}},
+		{name: "too many days", query: models.PriceHistoryQuery{Days: 366}},
+		{name: "unknown interval", query: models.PriceHistoryQuery{Interval: "hourly"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			mockPrices := new(MockPriceHistoryRepository)
+			service := newPriceTestService(mockRepo, mockPrices)
+
+			_, err := service.PriceHistory(context.Background(), 1, tt.query)
+
+			domainErr, ok := err.(*errors.DomainError)
+			assert.True(t, ok)
+			assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+			mockPrices.AssertNotCalled(t, "PriceHistory", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestService_CompareItems_LowestPrices: La comparación incluye el precio más bajo
+// de los últimos 30 días de cada ítem
+func TestService_CompareItems_LowestPrices(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockPrices := new(MockPriceHistoryRepository)
+	service := newPriceTestService(mockRepo, mockPrices)
+
+	first, second := validItem(), validItem()
+	first.ID, second.ID = 1, 2
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return([]models.Item{first, second}, nil)
+	mockPrices.On("LowestPrices", mock.Anything, []int64{1, 2}, priceTestNow.Add(-30*24*time.Hour)).
+		Return(map[int64]map[string]float64{1: {"USD": 89.99}, 2: {"USD": 100}}, nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]float64{1: 89.99, 2: 100}, response.Comparison.LowestPrices30d)
+	mockPrices.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"project/internal/errors"
+	"project/internal/models"
+	"project/internal/repositories"
+	"reflect"
+	"sort"
+)
+
+// ItemRevisions devuelve el historial de revisiones del ítem. Los ítems eliminados
+// conservan su historial; si el ítem no tiene revisiones y no existe, devuelve un
+// error NotFound.
+func (s *ItemServiceImpl) ItemRevisions(ctx context.Context, id int64) (*models.ItemRevisionHistory, error) {
+	if id <= 0 {
+		return nil, errors.NewValidationError("ID inválido", nil)
+	}
+
+	revisions, err := s.repo.Revisions(ctx, id)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener las revisiones del item", err)
+	}
+
+	if len(revisions) == 0 {
+		// Los ítems anteriores al historial no tienen revisiones hasta su primer cambio.
+		if _, err := s.GetItemByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.ItemRevisionHistory{ItemID: id, Revisions: revisions}, nil
+}
+
+// DiffItemRevisions compara los valores del ítem después de la revisión from y
+// después de la revisión to. Con to igual a 0 se compara con la última revisión.
+func (s *ItemServiceImpl) DiffItemRevisions(ctx context.Context, id, from, to int64) (*models.RevisionDiff, error) {
+	if from <= 0 || to < 0 {
+		return nil, errors.NewValidationError("las revisiones deben ser enteros positivos", nil)
+	}
+
+	history, err := s.ItemRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 && len(history.Revisions) > 0 {
+		to = history.Revisions[len(history.Revisions)-1].Revision
+	}
+
+	fromValues, err := revisionValues(history, from)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := revisionValues(history, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionDiff{
+		ItemID:  id,
+		From:    from,
+		To:      to,
+		Changes: diffSnapshots(fromValues, toValues),
+	}, nil
+}
+
+// RestoreItemRevision vuelve a aplicar al ítem los valores que tenía después de la
+// revisión indicada. El resultado se valida como cualquier actualización y queda
+// registrado como una nueva revisión con origen models.SourceRestore.
+func (s *ItemServiceImpl) RestoreItemRevision(ctx context.Context, id, revision int64) (*models.Item, error) {
+	if id <= 0 || revision <= 0 {
+		return nil, errors.NewValidationError("ID o revisión inválidos", nil)
+	}
+
+	rev, err := s.repo.Revision(ctx, id, revision)
+	if err != nil {
+		if stdErrors.Is(err, repositories.ErrNotFound) {
+			return nil, revisionNotFound(id, revision)
+		}
+		return nil, errors.NewInternalServerError("error al obtener la revisión del item", err)
+	}
+	if rev.NewValues == nil {
+		return nil, deletedRevisionError(revision)
+	}
+
+	item, err := s.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev.NewValues.Apply(item)
+	if err := s.validateItemWrite(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if err := s.persistUpdate(models.WithSource(ctx, models.SourceRestore), item, nil); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// revisionValues devuelve los valores del ítem después de la revisión indicada.
+func revisionValues(history *models.ItemRevisionHistory, revision int64) (*models.ItemSnapshot, error) {
+	for _, rev := range history.Revisions {
+		if rev.Revision != revision {
+			continue
+		}
+		if rev.NewValues == nil {
+			return nil, deletedRevisionError(revision)
+		}
+		return rev.NewValues, nil
+	}
+	return nil, revisionNotFound(history.ItemID, revision)
+}
+
+// revisionNotFound es el error de una revisión inexistente.
+func revisionNotFound(id, revision int64) error {
+	return errors.NewDomainError(
+		errors.ErrorCodeNotFound,
+		fmt.Sprintf("la revisión %d del item %d no existe", revision, id),
+		nil,
+	)
+}
+
+// deletedRevisionError es el error de usar como estado del ítem la revisión de su baja.
+func deletedRevisionError(revision int64) error {
+	return errors.NewValidationError(
+		fmt.Sprintf("la revisión %d corresponde a la eliminación del item y no tiene valores", revision),
+		nil,
+	)
+}
+
+// diffSnapshots devuelve los campos que cambian entre from y to, en el orden de los
+// campos del ítem y con las especificaciones, ordenadas por clave, al final.
+func diffSnapshots(from, to *models.ItemSnapshot) []models.FieldChange {
+	changes := []models.FieldChange{}
+	compare := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, models.FieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	compare("name", from.Name, to.Name)
+	compare("image_url", from.ImageURL, to.ImageURL)
+	compare("description", from.Description, to.Description)
+	compare("price", from.Price, to.Price)
+	compare("currency", from.Currency, to.Currency)
+	compare("rating", from.Rating, to.Rating)
+	compare("category_id", categoryValue(from.CategoryID), categoryValue(to.CategoryID))
+
+	keys := make(map[string]bool)
+	for key := range from.Specifications {
+		keys[key] = true
+	}
+	for key := range to.Specifications {
+		keys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		compare("specifications."+key, from.Specifications[key], to.Specifications[key])
+	}
+
+	return changes
+}
+
+// categoryValue devuelve la categoría como valor comparable: nil o el ID.
+func categoryValue(id *int64) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
@@ -9,16 +9,79 @@ import (
 // relacionada con los ítems. Esta capa actúa como intermediaria entre
 // los controladores (handlers) y la capa de persistencia (repositories).
 type ItemService interface {
-	// GetAllItems obtiene todos los ítems disponibles en el sistema.
+	// GetAllItems obtiene una página de ítems aplicando los filtros,
+	// el ordenamiento y la paginación de la consulta.
 	// Recibe un contexto para controlar tiempos de ejecución o cancelaciones.
-	GetAllItems(ctx context.Context) ([]models.Item, error)
+	GetAllItems(ctx context.Context, query models.ItemQuery) (*models.ItemPage, error)
 
 	// GetItemByID obtiene un ítem por su identificador único.
 	// Retorna un puntero a Item si existe, o un error si no se encuentra.
 	GetItemByID(ctx context.Context, id int64) (*models.Item, error)
 
+	// ConvertPrices convierte a la moneda indicada el precio de cada ítem. Una moneda
+	// no soportada es un error de validación.
+	ConvertPrices(ctx context.Context, items []models.Item, currency string) error
+
+	// CatalogState devuelve la versión y la fecha de la última modificación del
+	// catálogo, que permiten validar las respuestas de los listados en caché.
+	CatalogState(ctx context.Context) (*models.CatalogState, error)
+
+	// SearchItems realiza una búsqueda de texto completo en el catálogo y
+	// devuelve los resultados ordenados por relevancia con fragmentos resaltados.
+	SearchItems(ctx context.Context, text string, limit int) (*models.SearchResponse, error)
+
 	// CompareItems recibe una lista de IDs y devuelve una estructura
 	// con la información necesaria para comparar esos ítems.
-	// Si algún ID no existe, devuelve un error.
-	CompareItems(ctx context.Context, itemIDs []int64) (*models.CompareResponse, error)
+	// Si algún ID no existe, devuelve un error. Si los ítems pertenecen a
+	// categorías no relacionadas, advierte o (con Strict) devuelve un error.
+	CompareItems(ctx context.Context, req models.CompareRequest) (*models.CompareResponse, error)
+
+	// ScoreItems compara los ítems indicados y los ordena según una puntuación
+	// 0-100 ponderada por criterio. Los criterios desconocidos son un error de validación.
+	ScoreItems(ctx context.Context, req models.ScoreRequest) (*models.ScoreResponse, error)
+
+	// CreateItem valida y persiste un nuevo ítem. Devuelve el ítem con su ID asignado.
+	CreateItem(ctx context.Context, item models.Item) (*models.Item, error)
+
+	// UpdateItem reemplaza por completo el ítem indicado (PUT).
+	// Si el ítem no existe, devuelve un error NotFound.
+	UpdateItem(ctx context.Context, id int64, item models.Item) (*models.Item, error)
+
+	// UpdateItemVersioned es como UpdateItem, pero solo reemplaza el ítem si su
+	// versión actual es expectedVersion. Si otra escritura lo modificó antes,
+	// devuelve un error Conflict y el ítem no cambia.
+	UpdateItemVersioned(ctx context.Context, id int64, expectedVersion int64, item models.Item) (*models.Item, error)
+
+	// PatchItem aplica una actualización parcial sobre el ítem indicado (PATCH).
+	// El resultado se valida igual que en UpdateItem. Si el patch incluye una
+	// versión, se aplica como en UpdateItemVersioned.
+	PatchItem(ctx context.Context, id int64, patch models.ItemPatch) (*models.Item, error)
+
+	// ImportItems aplica en una única transacción las filas de un archivo de
+	// importación y devuelve las filas aceptadas y las rechazadas con sus motivos.
+	// Con dryRun valida y aplica las filas, pero revierte la transacción.
+	ImportItems(ctx context.Context, rows models.ImportReader, dryRun bool) (*models.ImportReport, error)
+
+	// ExportItems escribe en w, ordenados por ID, todos los ítems que cumplen los
+	// filtros de la consulta. La paginación y el ordenamiento de la consulta se ignoran.
+	ExportItems(ctx context.Context, query models.ItemQuery, w models.ExportWriter) error
+
+	// ItemRevisions devuelve el historial de cambios del ítem, incluso si fue eliminado.
+	ItemRevisions(ctx context.Context, id int64) (*models.ItemRevisionHistory, error)
+
+	// DiffItemRevisions compara campo a campo (y especificación a especificación) los
+	// valores del ítem después de dos revisiones. Con to igual a 0 usa la última.
+	DiffItemRevisions(ctx context.Context, id, from, to int64) (*models.RevisionDiff, error)
+
+	// RestoreItemRevision reemplaza el ítem por los valores que tenía después de la
+	// revisión indicada, validándolos como en UpdateItem.
+	RestoreItemRevision(ctx context.Context, id, revision int64) (*models.Item, error)
+
+	// PriceHistory devuelve los precios del ítem en una ventana que termina en el
+	// momento actual, con sus estadísticas y, opcionalmente, agrupados por día o semana.
+	PriceHistory(ctx context.Context, id int64, query models.PriceHistoryQuery) (*models.PriceHistory, error)
+
+	// DeleteItem elimina el ítem indicado.
+	// Si el ítem no existe, devuelve un error NotFound.
+	DeleteItem(ctx context.Context, id int64) error
 }
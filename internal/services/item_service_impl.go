@@ -4,32 +4,126 @@ import (
 	"context"
 	stdErrors "errors"
 	"fmt"
+	"project/internal/currency"
 	"project/internal/errors"
 	"project/internal/models"
 	"project/internal/repositories"
+	"reflect"
 	"sort"
+	"strings"
+	"time"
 )
 
 // ItemServiceImpl implementa la interfaz ItemService.
 // Esta capa representa la lógica de negocio y orquesta
 // las llamadas hacia el repositorio.
 type ItemServiceImpl struct {
-	repo repositories.ItemRepository
+	repo  repositories.ItemRepository
+	rules ComparisonRules
+
+	// categories es opcional: sin él no se verifican las categorías de los
+	// ítems al escribirlos ni al compararlos.
+	categories repositories.CategoryRepository
+
+	// prices es opcional: sin él no hay historial de precios ni precio más bajo
+	// de los últimos 30 días en las comparaciones.
+	prices repositories.PriceHistoryRepository
+
+	// rates es opcional: sin él solo se pueden expresar los precios en la moneda
+	// de cada ítem, y los filtros y el orden por precio no convierten.
+	rates currency.ExchangeRateProvider
+
+	// now devuelve el momento actual; los tests lo reemplazan.
+	now func() time.Time
+}
+
+// ItemServiceOption configura opciones adicionales de ItemServiceImpl.
+type ItemServiceOption func(*ItemServiceImpl)
+
+// WithCategories habilita la validación de categorías y las advertencias de
+// comparación entre categorías no relacionadas.
+func WithCategories(categories repositories.CategoryRepository) ItemServiceOption {
+	return func(s *ItemServiceImpl) {
+		s.categories = categories
+	}
+}
+
+// WithPriceHistory habilita el historial de precios y el precio más bajo de los
+// últimos 30 días en las comparaciones.
+func WithPriceHistory(prices repositories.PriceHistoryRepository) ItemServiceOption {
+	return func(s *ItemServiceImpl) {
+		s.prices = prices
+	}
+}
+
+// WithExchangeRates habilita la conversión de precios entre monedas con los tipos
+// de cambio del proveedor.
+func WithExchangeRates(rates currency.ExchangeRateProvider) ItemServiceOption {
+	return func(s *ItemServiceImpl) {
+		s.rates = rates
+	}
 }
 
 // NewItemService crea una nueva instancia del servicio.
-func NewItemService(repo repositories.ItemRepository) ItemService {
-	return &ItemServiceImpl{repo: repo}
+// Las opciones permiten ajustar, por ejemplo, las reglas de comparación.
+func NewItemService(repo repositories.ItemRepository, opts ...ItemServiceOption) ItemService {
+	s := &ItemServiceImpl{
+		repo:  repo,
+		rules: DefaultComparisonRules(),
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// GetAllItems obtiene todos los ítems desde el repositorio.
+// GetAllItems obtiene una página de ítems desde el repositorio junto con
+// el total de ítems que cumplen los filtros. Con query.Currency, los precios de
+// la página se convierten a esa moneda.
 // Si algo falla, envía un error de servidor interno.
-func (s *ItemServiceImpl) GetAllItems(ctx context.Context) ([]models.Item, error) {
-	items, err := s.repo.GetAll(ctx)
+func (s *ItemServiceImpl) GetAllItems(ctx context.Context, query models.ItemQuery) (*models.ItemPage, error) {
+	var knownSpecKeys []string
+	if len(query.SpecFilters) > 0 {
+		keys, err := s.repo.SpecKeys(ctx)
+		if err != nil {
+			return nil, errors.NewInternalServerError("error al obtener las claves de especificación", err)
+		}
+		knownSpecKeys = keys
+	}
+
+	query.Currency = models.NormalizeCurrency(query.Currency)
+	if err := validateItemQuery(&query, knownSpecKeys); err != nil {
+		return nil, err
+	}
+	if err := s.setPriceRates(ctx, &query); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetAll(ctx, query)
 	if err != nil {
 		return nil, errors.NewInternalServerError("error al obtener los items", err)
 	}
-	return items, nil
+
+	if query.Currency != "" {
+		if err := s.newPriceConverter(query.Currency).convertItems(ctx, items); err != nil {
+			return nil, err
+		}
+	}
+
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al contar los items", err)
+	}
+
+	return &models.ItemPage{
+		Data: items,
+		Pagination: models.Pagination{
+			Total:  total,
+			Limit:  query.Limit,
+			Offset: query.Offset,
+		},
+	}, nil
 }
 
 // GetItemByID devuelve un ítem según su ID.
@@ -50,9 +144,158 @@ func (s *ItemServiceImpl) GetItemByID(ctx context.Context, id int64) (*models.It
 	return item, nil
 }
 
+// CatalogState devuelve el estado del catálogo desde el repositorio.
+// Si algo falla, envía un error de servidor interno.
+func (s *ItemServiceImpl) CatalogState(ctx context.Context) (*models.CatalogState, error) {
+	state, err := s.repo.CatalogState(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al obtener el estado del catálogo", err)
+	}
+
+	return state, nil
+}
+
+// SearchItems valida el texto de búsqueda y delega la búsqueda al repositorio.
+func (s *ItemServiceImpl) SearchItems(ctx context.Context, text string, limit int) (*models.SearchResponse, error) {
+	text = strings.TrimSpace(text)
+	if err := validateSearch(text, &limit); err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.Search(ctx, text, limit)
+	if err != nil {
+		return nil, errors.NewInternalServerError("error al buscar los items", err)
+	}
+
+	return &models.SearchResponse{
+		Query:   text,
+		Results: results,
+	}, nil
+}
+
+// CreateItem valida el ítem recibido y lo inserta en el repositorio.
+func (s *ItemServiceImpl) CreateItem(ctx context.Context, item models.Item) (*models.Item, error) {
+	item.ID = 0
+	if err := s.validateItemWrite(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, &item); err != nil {
+		return nil, errors.NewInternalServerError("error al crear el item", err)
+	}
+
+	return &item, nil
+}
+
+// UpdateItem reemplaza por completo un ítem existente.
+func (s *ItemServiceImpl) UpdateItem(ctx context.Context, id int64, item models.Item) (*models.Item, error) {
+	return s.updateItem(ctx, id, item, nil)
+}
+
+// UpdateItemVersioned reemplaza por completo un ítem existente si su versión
+// actual es expectedVersion.
+func (s *ItemServiceImpl) UpdateItemVersioned(ctx context.Context, id int64, expectedVersion int64, item models.Item) (*models.Item, error) {
+	return s.updateItem(ctx, id, item, &expectedVersion)
+}
+
+// updateItem valida y persiste el ítem. Con expectedVersion, la actualización
+// solo se aplica si la versión actual del ítem coincide.
+func (s *ItemServiceImpl) updateItem(ctx context.Context, id int64, item models.Item, expectedVersion *int64) (*models.Item, error) {
+	if id <= 0 {
+		return nil, errors.NewValidationError("ID inválido", nil)
+	}
+	if expectedVersion != nil && *expectedVersion <= 0 {
+		return nil, errors.NewValidationError("la versión esperada debe ser mayor que 0", nil)
+	}
+
+	item.ID = id
+	if err := s.validateItemWrite(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	if err := s.persistUpdate(ctx, &item, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// PatchItem obtiene el ítem actual, le aplica los campos enviados y
+// persiste el resultado tras validarlo. Si el patch incluye una versión, la
+// escritura solo se aplica si el ítem sigue en esa versión.
+func (s *ItemServiceImpl) PatchItem(ctx context.Context, id int64, patch models.ItemPatch) (*models.Item, error) {
+	if patch.Version != nil && *patch.Version <= 0 {
+		return nil, errors.NewValidationError("la versión esperada debe ser mayor que 0", nil)
+	}
+
+	item, err := s.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch.Apply(item)
+	if err := s.validateItemWrite(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if err := s.persistUpdate(ctx, item, patch.Version); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// persistUpdate guarda el ítem, con la condición de versión si expectedVersion no es nil.
+func (s *ItemServiceImpl) persistUpdate(ctx context.Context, item *models.Item, expectedVersion *int64) error {
+	var err error
+	if expectedVersion != nil {
+		err = s.repo.UpdateVersioned(ctx, item, *expectedVersion)
+	} else {
+		err = s.repo.Update(ctx, item)
+	}
+
+	if stdErrors.Is(err, repositories.ErrVersionConflict) {
+		return errors.NewConflictError(
+			fmt.Sprintf("el item %d fue modificado y ya no está en la versión %d; obténgalo de nuevo y reintente", item.ID, *expectedVersion),
+			err,
+		)
+	}
+	if err != nil {
+		return translateWriteError(err, item.ID, "error al actualizar el item")
+	}
+	return nil
+}
+
+// DeleteItem elimina un ítem existente.
+func (s *ItemServiceImpl) DeleteItem(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return errors.NewValidationError("ID inválido", nil)
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return translateWriteError(err, id, "error al eliminar el item")
+	}
+
+	return nil
+}
+
+// translateWriteError convierte los errores del repositorio en una operación
+// de escritura a errores de dominio.
+func translateWriteError(err error, id int64, message string) error {
+	if stdErrors.Is(err, repositories.ErrNotFound) {
+		return errors.NewNotFoundError("Item", id)
+	}
+	return errors.NewInternalServerError(message, err)
+}
+
 // CompareItems compara múltiples ítems y genera un informe
 // con rangos de precio, rating y especificaciones comunes/únicas.
-func (s *ItemServiceImpl) CompareItems(ctx context.Context, itemIDs []int64) (*models.CompareResponse, error) {
+// Los precios se convierten a una misma moneda antes de compararlos.
+// Si los ítems pertenecen a categorías no relacionadas se añade una advertencia
+// o, con req.Strict, se rechaza la comparación.
+func (s *ItemServiceImpl) CompareItems(ctx context.Context, req models.CompareRequest) (*models.CompareResponse, error) {
+	itemIDs := req.ItemIDs
+
 	// Validación de reglas del negocio
 	if len(itemIDs) < 2 {
 		return nil, errors.NewValidationError("se requieren al menos 2 items para comparar", nil)
@@ -74,11 +317,37 @@ func (s *ItemServiceImpl) CompareItems(ctx context.Context, itemIDs []int64) (*m
 		return nil, errors.NewNotFoundError(fmt.Sprintf("Items con IDs %v no encontrados", missingIDs), nil)
 	}
 
+	warnings, err := s.checkCategories(ctx, items, req.Strict)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := comparisonCurrency(req.Currency, items)
+	if err != nil {
+		return nil, err
+	}
+	converter := s.newPriceConverter(target)
+	if err := converter.convertItems(ctx, items); err != nil {
+		return nil, err
+	}
+
 	comparison := s.generateComparison(items)
+	comparison.Currency = target
+
+	if s.prices != nil {
+		byCurrency, err := s.prices.LowestPrices(ctx, itemIDs, s.now().Add(-lowestPriceWindow))
+		if err != nil {
+			return nil, errors.NewInternalServerError("error al obtener los precios más bajos de los items", err)
+		}
+		if comparison.LowestPrices30d, err = converter.lowestPrices(ctx, byCurrency); err != nil {
+			return nil, err
+		}
+	}
 
 	return &models.CompareResponse{
 		Items:      items,
 		Comparison: comparison,
+		Warnings:   warnings,
 	}, nil
 }
 
@@ -103,12 +372,19 @@ func (s *ItemServiceImpl) generateComparison(items []models.Item) models.Compari
 
 	commonSpecs := s.findCommonSpecs(items)
 	uniqueSpecs := s.findUniqueSpecs(items)
+	specDiff := s.buildSpecDiff(items)
+	specRanges := s.buildSpecRanges(items)
+	winners := s.findWinners(items, specRanges)
 
 	return models.ComparisonDetails{
 		PriceRange:  priceRange,
 		RatingRange: ratingRange,
 		CommonSpecs: commonSpecs,
 		UniqueSpecs: uniqueSpecs,
+		SpecDiff:    specDiff,
+		SpecRanges:  specRanges,
+		Winners:     winners,
+		WinnerTally: tallyWinners(items, winners),
 	}
 }
 
@@ -168,6 +444,45 @@ func (s *ItemServiceImpl) findUniqueSpecs(items []models.Item) map[int64][]strin
 	return unique
 }
 
+// buildSpecDiff construye la matriz de especificaciones: para cada clave presente
+// en al menos un ítem, el valor de cada ítem y si todos los valores son idénticos.
+func (s *ItemServiceImpl) buildSpecDiff(items []models.Item) map[string]models.SpecDiff {
+	diff := make(map[string]models.SpecDiff)
+
+	for _, item := range items {
+		for key := range item.Specifications {
+			if _, done := diff[key]; done {
+				continue
+			}
+
+			values := make(map[int64]interface{}, len(items))
+			identical := true
+			for _, other := range items {
+				value, ok := other.Specifications[key]
+				values[other.ID] = value
+				if !ok || !specValuesEqual(value, item.Specifications[key]) {
+					identical = false
+				}
+			}
+
+			diff[key] = models.SpecDiff{Values: values, Identical: identical}
+		}
+	}
+
+	return diff
+}
+
+// specValuesEqual compara dos valores de especificación. Los textos se comparan
+// sin distinguir mayúsculas ni espacios en los extremos; el resto por igualdad profunda.
+func specValuesEqual(a, b interface{}) bool {
+	aStr, aIsStr := a.(string)
+	bStr, bIsStr := b.(string)
+	if aIsStr && bIsStr {
+		return strings.EqualFold(strings.TrimSpace(aStr), strings.TrimSpace(bStr))
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 // uniqueIDs elimina IDs duplicados de la lista
 func uniqueIDs(ids []int64) []int64 {
 	seen := make(map[int64]bool)
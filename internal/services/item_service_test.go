@@ -3,6 +3,9 @@ package services
 import (
 	"context"
 	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
 	"project/internal/errors"
 	"project/internal/models"
 	"project/internal/repositories"
@@ -17,14 +20,32 @@ type MockItemRepository struct {
 	mock.Mock
 }
 
-func (m *MockItemRepository) GetAll(ctx context.Context) ([]models.Item, error) {
-	args := m.Called(ctx)
+func (m *MockItemRepository) GetAll(ctx context.Context, query models.ItemQuery) ([]models.Item, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Item), args.Error(1)
 }
 
+// Stream llama a fn con los items configurados en el mock.
+func (m *MockItemRepository) Stream(ctx context.Context, query models.ItemQuery, fn func(item models.Item) error) error {
+	args := m.Called(ctx, query)
+	if items, ok := args.Get(0).([]models.Item); ok {
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockItemRepository) Count(ctx context.Context, query models.ItemQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockItemRepository) GetByID(ctx context.Context, id int64) (*models.Item, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -41,11 +62,84 @@ func (m *MockItemRepository) GetByIDs(ctx context.Context, ids []int64) ([]model
 	return args.Get(0).([]models.Item), args.Error(1)
 }
 
-func (m *MockItemRepository) Seed(ctx context.Context) error {
+func (m *MockItemRepository) CatalogState(ctx context.Context) (*models.CatalogState, error) {
 	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CatalogState), args.Error(1)
+}
+
+func (m *MockItemRepository) SpecKeys(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockItemRepository) Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error) {
+	args := m.Called(ctx, text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SearchResult), args.Error(1)
+}
+
+func (m *MockItemRepository) Create(ctx context.Context, item *models.Item) error {
+	args := m.Called(ctx, item)
 	return args.Error(0)
 }
 
+func (m *MockItemRepository) Update(ctx context.Context, item *models.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) UpdateVersioned(ctx context.Context, item *models.Item, expectedVersion int64) error {
+	args := m.Called(ctx, item, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) Revisions(ctx context.Context, itemID int64) ([]models.ItemRevision, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemRevision), args.Error(1)
+}
+
+func (m *MockItemRepository) Revision(ctx context.Context, itemID, revision int64) (*models.ItemRevision, error) {
+	args := m.Called(ctx, itemID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemRevision), args.Error(1)
+}
+
+func (m *MockItemRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// InTransaction ejecuta fn con el propio mock, de modo que las expectativas de
+// las operaciones dentro de la transacción se configuran igual que fuera de ella.
+func (m *MockItemRepository) InTransaction(ctx context.Context, fn func(tx repositories.ItemRepository) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+func (m *MockItemRepository) Seed(ctx context.Context, fixturePaths ...string) (*models.SeedReport, error) {
+	args := m.Called(ctx, fixturePaths)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeedReport), args.Error(1)
+}
+
 func (m *MockItemRepository) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -93,14 +187,98 @@ func TestService_GetAllItems_OK(t *testing.T) {
 		{ID: 2, Name: "Item 2"},
 	}
 
-	mockRepo.On("GetAll", mock.Anything).Return(expectedItems, nil)
+	expectedQuery := models.ItemQuery{Limit: models.DefaultPageLimit}
+	mockRepo.On("GetAll", mock.Anything, expectedQuery).Return(expectedItems, nil)
+	mockRepo.On("Count", mock.Anything, expectedQuery).Return(2, nil)
 
-	items, err := service.GetAllItems(context.Background())
+	page, err := service.GetAllItems(context.Background(), models.ItemQuery{})
 
 	assert.NoError(t, err)
-	assert.NotNil(t, items)
-	assert.Len(t, items, 2)
-	assert.Equal(t, expectedItems, items)
+	assert.NotNil(t, page)
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, expectedItems, page.Data)
+	assert.Equal(t, 2, page.Pagination.Total)
+	assert.Equal(t, models.DefaultPageLimit, page.Pagination.Limit)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_GetAllItems_InvalidQuery: limit fuera de rango, sort no soportado y
+// rango de precios incoherente. Debe devolver ValidationError sin llamar al repo
+func TestService_GetAllItems_InvalidQuery(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	minPrice, maxPrice := 500.0, 100.0
+	query := models.ItemQuery{
+		Limit:    500,
+		Sort:     []models.SortField{{Field: "description"}},
+		MinPrice: &minPrice,
+		MaxPrice: &maxPrice,
+	}
+
+	page, err := service.GetAllItems(context.Background(), query)
+
+	assert.Error(t, err)
+	assert.Nil(t, page)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "limit")
+	assert.Contains(t, domainErr.Message, "description")
+	assert.Contains(t, domainErr.Message, "min_price")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_GetAllItems_SpecFilters: Los filtros con claves existentes llegan al repositorio
+func TestService_GetAllItems_SpecFilters(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	filters := []models.SpecFilter{
+		{Key: "memory", Operator: models.SpecOpEquals, Value: "32GB"},
+		{Key: "weight", Operator: models.SpecOpLess, Value: "2kg"},
+	}
+	expectedQuery := models.ItemQuery{Limit: models.DefaultPageLimit, SpecFilters: filters}
+
+	mockRepo.On("SpecKeys", mock.Anything).Return([]string{"memory", "processor", "weight"}, nil)
+	mockRepo.On("GetAll", mock.Anything, expectedQuery).Return([]models.Item{{ID: 2}}, nil)
+	mockRepo.On("Count", mock.Anything, expectedQuery).Return(1, nil)
+
+	page, err := service.GetAllItems(context.Background(), models.ItemQuery{SpecFilters: filters})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_GetAllItems_InvalidSpecFilters: Claves con formato inválido, claves desconocidas y
+// valores no numéricos en operadores numéricos deben devolver ValidationError
+func TestService_GetAllItems_InvalidSpecFilters(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("SpecKeys", mock.Anything).Return([]string{"memory", "weight"}, nil)
+
+	query := models.ItemQuery{SpecFilters: []models.SpecFilter{
+		{Key: "battery life", Operator: models.SpecOpEquals, Value: "10"},
+		{Key: "colour", Operator: models.SpecOpEquals, Value: "red"},
+		{Key: "weight", Operator: models.SpecOpLess, Value: "light"},
+	}}
+
+	page, err := service.GetAllItems(context.Background(), query)
+
+	assert.Error(t, err)
+	assert.Nil(t, page)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "battery life")
+	assert.Contains(t, domainErr.Message, "colour")
+	assert.Contains(t, domainErr.Message, "spec.weight_lt")
+
 	mockRepo.AssertExpectations(t)
 }
 
@@ -110,12 +288,12 @@ func TestService_GetAllItems_RepoError(t *testing.T) {
 	service := NewItemService(mockRepo)
 
 	repoError := sql.ErrConnDone
-	mockRepo.On("GetAll", mock.Anything).Return(nil, repoError)
+	mockRepo.On("GetAll", mock.Anything, mock.Anything).Return(nil, repoError)
 
-	items, err := service.GetAllItems(context.Background())
+	page, err := service.GetAllItems(context.Background(), models.ItemQuery{})
 
 	assert.Error(t, err)
-	assert.Nil(t, items)
+	assert.Nil(t, page)
 
 	domainErr, ok := err.(*errors.DomainError)
 	assert.True(t, ok)
@@ -254,7 +432,7 @@ func TestService_CompareItems_OK(t *testing.T) {
 
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2, 3}).Return(items, nil)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2, 3})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2, 3}})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -291,6 +469,23 @@ func TestService_CompareItems_OK(t *testing.T) {
 	assert.NotContains(t, response.Comparison.UniqueSpecs[int64(2)], "size")
 	assert.NotContains(t, response.Comparison.UniqueSpecs[int64(3)], "size")
 
+	// Validar spec_diff: una entrada por clave con el valor de cada item
+	assert.Len(t, response.Comparison.SpecDiff, 4)
+
+	// "material" es común y con el mismo valor en los 3 items
+	assert.True(t, response.Comparison.SpecDiff["material"].Identical)
+
+	// "color" es común pero con valores distintos
+	colorDiff := response.Comparison.SpecDiff["color"]
+	assert.False(t, colorDiff.Identical)
+	assert.Equal(t, map[int64]interface{}{1: "red", 2: "blue", 3: "green"}, colorDiff.Values)
+
+	// "size" falta en el item 3: su valor es nil y no puede ser idéntico
+	sizeDiff := response.Comparison.SpecDiff["size"]
+	assert.False(t, sizeDiff.Identical)
+	assert.Nil(t, sizeDiff.Values[3])
+	assert.Len(t, sizeDiff.Values, 3)
+
 	mockRepo.AssertExpectations(t)
 }
 
@@ -299,7 +494,7 @@ func TestService_CompareItems_ValidationError_TooFew(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	service := NewItemService(mockRepo)
 
-	response, err := service.CompareItems(context.Background(), []int64{1})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1}})
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -318,7 +513,7 @@ func TestService_CompareItems_ValidationError_Empty(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	service := NewItemService(mockRepo)
 
-	response, err := service.CompareItems(context.Background(), []int64{})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{}})
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -340,7 +535,7 @@ func TestService_CompareItems_ValidationError_TooMany(t *testing.T) {
 		ids[i] = int64(i + 1)
 	}
 
-	response, err := service.CompareItems(context.Background(), ids)
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: ids})
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -362,7 +557,7 @@ func TestService_CompareItems_RepoError(t *testing.T) {
 	repoError := sql.ErrConnDone
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(nil, repoError)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -389,7 +584,7 @@ func TestService_CompareItems_MissingItems(t *testing.T) {
 
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2, 3}).Return(foundItems, nil)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2, 3})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2, 3}})
 
 	assert.Error(t, err)
 	assert.Nil(t, response)
@@ -416,7 +611,7 @@ func TestService_CompareItems_DuplicateIDs(t *testing.T) {
 	// Se pasan IDs duplicados [1, 2, 1], pero uniqueIDs debería convertirlos a [1, 2]
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2, 1})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2, 1}})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -437,7 +632,7 @@ func TestService_CompareItems_EmptySpecs(t *testing.T) {
 
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -477,7 +672,7 @@ func TestService_CompareItems_AllCommonSpecs(t *testing.T) {
 
 	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
 
-	response, err := service.CompareItems(context.Background(), []int64{1, 2})
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -485,6 +680,764 @@ func TestService_CompareItems_AllCommonSpecs(t *testing.T) {
 	assert.Contains(t, response.Comparison.CommonSpecs, "color")
 	assert.Contains(t, response.Comparison.CommonSpecs, "material")
 	assert.Empty(t, response.Comparison.UniqueSpecs)
+	assert.True(t, response.Comparison.SpecDiff["color"].Identical)
+	assert.True(t, response.Comparison.SpecDiff["material"].Identical)
 
 	mockRepo.AssertExpectations(t)
 }
+
+// validItem devuelve un ítem que cumple todas las reglas de validación
+func validItem() models.Item {
+	return models.Item{
+		Name:        "Test Item",
+		ImageURL:    "https://example.com/image.jpg",
+		Description: "Test Description",
+		Price:       100.0,
+		Rating:      4.5,
+		Specifications: models.Specifications{
+			"color": "red",
+		},
+	}
+}
+
+// TestService_CreateItem_OK: El item es válido y el repo le asigna un ID
+func TestService_CreateItem_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item")).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Item).ID = 10
+		}).
+		Return(nil)
+
+	item, err := service.CreateItem(context.Background(), validItem())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, item)
+	assert.Equal(t, int64(10), item.ID)
+	assert.Equal(t, "Test Item", item.Name)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_CreateItem_ValidationError: Nombre vacío, precio negativo, rating fuera de rango
+// y URL inválida. Debe devolver ValidationError sin llamar al repo
+func TestService_CreateItem_ValidationError(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	item := models.Item{
+		Name:     "   ",
+		ImageURL: "ftp://example.com/image.jpg",
+		Price:    -1,
+		Rating:   7,
+	}
+
+	created, err := service.CreateItem(context.Background(), item)
+
+	assert.Error(t, err)
+	assert.Nil(t, created)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "nombre")
+	assert.Contains(t, domainErr.Message, "precio")
+	assert.Contains(t, domainErr.Message, "rating")
+	assert.Contains(t, domainErr.Message, "image_url")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_UpdateItem_NotFound: El repo devuelve ErrNotFound. Debe traducirse a NOT_FOUND
+func TestService_UpdateItem_NotFound(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Item")).Return(repositories.ErrNotFound)
+
+	item, err := service.UpdateItem(context.Background(), 999, validItem())
+
+	assert.Error(t, err)
+	assert.Nil(t, item)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeNotFound, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "999")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_UpdateItemVersioned_Conflict: El repo devuelve ErrVersionConflict. Debe traducirse a CONFLICT
+func TestService_UpdateItemVersioned_Conflict(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("UpdateVersioned", mock.Anything, mock.AnythingOfType("*models.Item"), int64(3)).Return(repositories.ErrVersionConflict)
+
+	item, err := service.UpdateItemVersioned(context.Background(), 1, 3, validItem())
+
+	assert.Nil(t, item)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeConflict, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "versión 3")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_UpdateItemVersioned_InvalidVersion: Una versión no positiva es un error de validación
+func TestService_UpdateItemVersioned_InvalidVersion(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	_, err := service.UpdateItemVersioned(context.Background(), 1, -1, validItem())
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "UpdateVersioned", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestService_PatchItem_Versioned: Un patch con versión usa la actualización versionada
+func TestService_PatchItem_Versioned(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	existing := validItem()
+	existing.ID = 1
+	existing.Version = 2
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&existing, nil)
+	mockRepo.On("UpdateVersioned", mock.Anything, mock.AnythingOfType("*models.Item"), int64(2)).Return(nil)
+
+	newPrice, version := 80.0, int64(2)
+	item, err := service.PatchItem(context.Background(), 1, models.ItemPatch{Price: &newPrice, Version: &version})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 80.0, item.Price)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestService_PatchItem_OK: Solo se modifican los campos enviados
+func TestService_PatchItem_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	existing := validItem()
+	existing.ID = 1
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Item")).Return(nil)
+
+	newPrice := 80.0
+	item, err := service.PatchItem(context.Background(), 1, models.ItemPatch{Price: &newPrice})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, item)
+	assert.Equal(t, 80.0, item.Price)
+	assert.Equal(t, "Test Item", item.Name)
+	assert.Equal(t, 4.5, item.Rating)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_PatchItem_ValidationError: El resultado del patch es inválido. No debe llamar a Update
+func TestService_PatchItem_ValidationError(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	existing := validItem()
+	existing.ID = 1
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&existing, nil)
+
+	badRating := 5.5
+	item, err := service.PatchItem(context.Background(), 1, models.ItemPatch{Rating: &badRating})
+
+	assert.Error(t, err)
+	assert.Nil(t, item)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_DeleteItem_OK: El repo elimina el item sin error
+func TestService_DeleteItem_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+
+	err := service.DeleteItem(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_DeleteItem_NotFound: El repo devuelve ErrNotFound. Debe traducirse a NOT_FOUND
+func TestService_DeleteItem_NotFound(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("Delete", mock.Anything, int64(999)).Return(repositories.ErrNotFound)
+
+	err := service.DeleteItem(context.Background(), 999)
+
+	assert.Error(t, err)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeNotFound, domainErr.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_SearchItems_OK: El texto se normaliza y se aplica el límite por defecto
+func TestService_SearchItems_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	results := []models.SearchResult{
+		{Item: models.Item{ID: 2, Name: "Dell XPS 15"}, Score: 3.2, Snippet: "<mark>Intel</mark> Core i7"},
+	}
+	mockRepo.On("Search", mock.Anything, "intel", models.DefaultPageLimit).Return(results, nil)
+
+	response, err := service.SearchItems(context.Background(), "  intel ", 0)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "intel", response.Query)
+	assert.Equal(t, results, response.Results)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_SearchItems_EmptyQuery: Un texto vacío debe devolver ValidationError sin llamar al repo
+func TestService_SearchItems_EmptyQuery(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	response, err := service.SearchItems(context.Background(), "   ", 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "q")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_CompareItems_SpecRanges: Los valores con unidades se normalizan y se
+// reportan min/max/mejor por especificación
+func TestService_CompareItems_SpecRanges(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	items := []models.Item{
+		{
+			ID: 1, Name: "Laptop A", Price: 2499.99, Rating: 4.8,
+			Specifications: models.Specifications{
+				"storage":      "512GB SSD",
+				"weight":       "2.15 kg",
+				"battery_life": "Up to 22 hours",
+				"processor":    "Apple M2 Pro",
+			},
+		},
+		{
+			ID: 2, Name: "Laptop B", Price: 1899.99, Rating: 4.6,
+			Specifications: models.Specifications{
+				"storage":      "1TB SSD",
+				"weight":       "1920 g",
+				"battery_life": "Up to 10 hours",
+				"processor":    "Intel Core i7-13700H",
+			},
+		},
+	}
+
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+	assert.NoError(t, err)
+	ranges := response.Comparison.SpecRanges
+
+	// El almacenamiento se compara en GB: 1TB (1024GB) es mayor que 512GB
+	storage := ranges["storage"]
+	assert.Equal(t, models.DimensionDataSize, storage.Dimension)
+	assert.Equal(t, "GB", storage.Unit)
+	assert.Equal(t, 512.0, storage.Min)
+	assert.Equal(t, 1024.0, storage.Max)
+	assert.Equal(t, 1024.0, storage.Best)
+	assert.Equal(t, []int64{2}, storage.BestItemIDs)
+
+	// En el peso un valor menor es mejor; 1920 g se convierte a 1.92 kg
+	weight := ranges["weight"]
+	assert.Equal(t, models.DirectionLower, weight.Direction)
+	assert.InDelta(t, 1.92, weight.Min, 1e-9)
+	assert.Equal(t, []int64{2}, weight.BestItemIDs)
+
+	battery := ranges["battery_life"]
+	assert.Equal(t, "h", battery.Unit)
+	assert.Equal(t, 22.0, battery.Best)
+	assert.Equal(t, []int64{1}, battery.BestItemIDs)
+
+	// El procesador no es cuantificable y no tiene rango
+	assert.NotContains(t, ranges, "processor")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_CompareItems_Winners: Se declara el ganador de precio, rating y cada
+// especificación cuantificable, y se cuenta cuántos criterios gana cada item
+func TestService_CompareItems_Winners(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	items := []models.Item{
+		{
+			ID: 1, Name: "Laptop A", Price: 2499.99, Rating: 4.8,
+			Specifications: models.Specifications{"memory": "32GB", "weight": "2.15 kg"},
+		},
+		{
+			ID: 2, Name: "Laptop B", Price: 1899.99, Rating: 4.8,
+			Specifications: models.Specifications{"memory": "16GB", "weight": "1.92 kg"},
+		},
+	}
+
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+	assert.NoError(t, err)
+	winners := response.Comparison.Winners
+	assert.Equal(t, []int64{2}, winners["price"])
+	assert.Equal(t, []int64{1, 2}, winners["rating"], "los empates se incluyen todos")
+	assert.Equal(t, []int64{1}, winners["memory"])
+	assert.Equal(t, []int64{2}, winners["weight"])
+
+	assert.Equal(t, map[int64]int{1: 2, 2: 3}, response.Comparison.WinnerTally)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_CompareItems_CustomRules: Las reglas configuradas reemplazan la
+// dirección por defecto de cada criterio
+func TestService_CompareItems_CustomRules(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	rules := DefaultComparisonRules()
+	rules["weight"] = models.DirectionHigher
+	rules["price"] = models.DirectionHigher
+	service := NewItemService(mockRepo, WithComparisonRules(rules))
+
+	items := []models.Item{
+		{ID: 1, Name: "A", Price: 100, Rating: 4, Specifications: models.Specifications{"weight": "2 kg"}},
+		{ID: 2, Name: "B", Price: 200, Rating: 3, Specifications: models.Specifications{"weight": "1 kg"}},
+	}
+
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+
+	response, err := service.CompareItems(context.Background(), models.CompareRequest{ItemIDs: []int64{1, 2}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.DirectionHigher, response.Comparison.SpecRanges["weight"].Direction)
+	assert.Equal(t, []int64{1}, response.Comparison.Winners["weight"])
+	assert.Equal(t, []int64{2}, response.Comparison.Winners["price"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestLoadComparisonRules: El archivo se combina con las reglas por defecto y
+// rechaza direcciones desconocidas
+func TestLoadComparisonRules(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "rules.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"display": "higher", "weight": "higher"}`), 0o600))
+
+	rules, err := LoadComparisonRules(valid)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DirectionHigher, rules["display"])
+	assert.Equal(t, models.DirectionHigher, rules["weight"])
+	assert.Equal(t, models.DirectionLower, rules["price"])
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"weight": "lighter"}`), 0o600))
+
+	_, err = LoadComparisonRules(invalid)
+	assert.Error(t, err)
+}
+
+// TestService_ScoreItems_OK: Cada criterio se normaliza de 0 a 100 según su
+// dirección y la puntuación final pondera los criterios
+func TestService_ScoreItems_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	items := []models.Item{
+		{
+			ID: 1, Name: "Laptop A", Price: 2000, Rating: 4.8,
+			Specifications: models.Specifications{"battery_life": "Up to 20 hours", "weight": "2 kg"},
+		},
+		{
+			ID: 2, Name: "Laptop B", Price: 1000, Rating: 4.0,
+			Specifications: models.Specifications{"battery_life": "10 hours", "weight": "1 kg"},
+		},
+		{
+			ID: 3, Name: "Laptop C", Price: 1500, Rating: 4.4,
+			Specifications: models.Specifications{"battery_life": "15 hours"},
+		},
+	}
+
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2, 3}).Return(items, nil)
+
+	response, err := service.ScoreItems(context.Background(), models.ScoreRequest{
+		ItemIDs: []int64{1, 2, 3},
+		Weights: map[string]float64{"price": 50, "battery_life": 30, "weight": 20},
+	})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, response.Weights["price"], 1e-9)
+
+	// B: precio 100, batería 0, peso 100 -> 50 + 0 + 20 = 70
+	// C: precio 50, batería 50, sin peso -> 25 + 15 + 0 = 40
+	// A: precio 0, batería 100, peso 0 -> 0 + 30 + 0 = 30
+	assert.Len(t, response.Ranking, 3)
+	assert.Equal(t, int64(2), response.Ranking[0].ItemID)
+	assert.Equal(t, 1, response.Ranking[0].Rank)
+	assert.Equal(t, 70.0, response.Ranking[0].Score)
+	assert.Equal(t, int64(3), response.Ranking[1].ItemID)
+	assert.Equal(t, 40.0, response.Ranking[1].Score)
+	assert.Equal(t, int64(1), response.Ranking[2].ItemID)
+	assert.Equal(t, 30.0, response.Ranking[2].Score)
+
+	battery := response.Ranking[2].Breakdown["battery_life"]
+	assert.Equal(t, 100.0, battery.Score)
+	assert.Equal(t, 30.0, battery.Contribution)
+	assert.Equal(t, "h", battery.Unit)
+
+	// El item C no tiene peso: no aporta puntos en ese criterio
+	missing := response.Ranking[1].Breakdown["weight"]
+	assert.Nil(t, missing.Value)
+	assert.Equal(t, 0.0, missing.Score)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_ScoreItems_UnknownCriterion: Un criterio que no es precio, rating ni
+// especificación cuantificable devuelve ValidationError
+func TestService_ScoreItems_UnknownCriterion(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	items := []models.Item{
+		{ID: 1, Name: "A", Price: 100, Rating: 4, Specifications: models.Specifications{"color": "red"}},
+		{ID: 2, Name: "B", Price: 200, Rating: 3, Specifications: models.Specifications{"color": "blue"}},
+	}
+	mockRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(items, nil)
+
+	response, err := service.ScoreItems(context.Background(), models.ScoreRequest{
+		ItemIDs: []int64{1, 2},
+		Weights: map[string]float64{"price": 1, "color": 1, "speed": 1},
+	})
+
+	assert.Nil(t, response)
+	var domainErr *errors.DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "color, speed")
+}
+
+// TestService_ScoreItems_InvalidWeights: Pesos vacíos, negativos o que suman 0
+// se rechazan antes de consultar el repositorio
+func TestService_ScoreItems_InvalidWeights(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	for _, weights := range []map[string]float64{
+		nil,
+		{"price": -1},
+		{"price": 0, "rating": 0},
+	} {
+		_, err := service.ScoreItems(context.Background(), models.ScoreRequest{ItemIDs: []int64{1, 2}, Weights: weights})
+
+		var domainErr *errors.DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	}
+
+	mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+// sliceImportReader entrega filas de importación desde memoria; err se devuelve
+// después de la última fila en lugar de io.EOF.
+type sliceImportReader struct {
+	rows []models.ImportRow
+	err  error
+}
+
+func (r *sliceImportReader) Next() (models.ImportRow, error) {
+	if len(r.rows) == 0 {
+		if r.err != nil {
+			return models.ImportRow{}, r.err
+		}
+		return models.ImportRow{}, io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row, nil
+}
+
+// TestService_ImportItems_AcceptedAndRejected: Las filas válidas se crean o
+// actualizan y las inválidas se informan con sus motivos
+func TestService_ImportItems_AcceptedAndRejected(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	update := validItem()
+	update.ID = 7
+	missing := validItem()
+	missing.ID = 99
+	invalid := validItem()
+	invalid.Price = 0
+
+	rows := &sliceImportReader{rows: []models.ImportRow{
+		{Line: 2, Item: validItem()},
+		{Line: 3, Item: update},
+		{Line: 4, Item: invalid},
+		{Line: 5, Item: missing},
+		{Line: 6, Problems: []errors.FieldError{{Field: "rating", Message: "el rating debe ser un número"}}},
+	}}
+
+	mockRepo.On("InTransaction", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item")).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Item).ID = 42 }).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *models.Item) bool { return item.ID == 7 })).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *models.Item) bool { return item.ID == 99 })).
+		Return(repositories.ErrNotFound)
+
+	report, err := service.ImportItems(context.Background(), rows, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, []models.ImportAccepted{
+		{Line: 2, ID: 42, Name: "Test Item", Action: models.ImportActionCreated},
+		{Line: 3, ID: 7, Name: "Test Item", Action: models.ImportActionUpdated},
+	}, report.Accepted)
+
+	assert.Len(t, report.Rejected, 3)
+	assert.Equal(t, 4, report.Rejected[0].Line)
+	assert.Equal(t, "price", report.Rejected[0].Reasons[0].Field)
+	assert.Equal(t, "no existe un item con id 99", report.Rejected[1].Reasons[0].Message)
+	assert.Equal(t, "rating", report.Rejected[2].Reasons[0].Field)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_ImportItems_DryRun: La transacción se revierte y los IDs de los
+// items que se habrían creado no se informan
+func TestService_ImportItems_DryRun(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("InTransaction", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item")).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Item).ID = 42 }).Return(nil)
+
+	report, err := service.ImportItems(context.Background(), &sliceImportReader{rows: []models.ImportRow{{Line: 2, Item: validItem()}}}, true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, int64(0), report.Accepted[0].ID)
+	assert.Equal(t, models.ImportActionCreated, report.Accepted[0].Action)
+}
+
+// TestService_ImportItems_ReadError: Un error de lectura aborta la importación
+func TestService_ImportItems_ReadError(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	readErr := errors.NewBadRequestError("el archivo supera el tamaño máximo de 64 MB", nil)
+	mockRepo.On("InTransaction", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item")).Return(nil)
+
+	report, err := service.ImportItems(context.Background(), &sliceImportReader{
+		rows: []models.ImportRow{{Line: 2, Item: validItem()}},
+		err:  readErr,
+	}, false)
+
+	assert.Nil(t, report)
+	assert.Equal(t, readErr, err)
+}
+
+// recordingExportWriter guarda lo que el servicio escribe en la exportación.
+type recordingExportWriter struct {
+	specKeys []string
+	items    []models.Item
+	err      error
+}
+
+func (w *recordingExportWriter) WriteHeader(specKeys []string) error {
+	w.specKeys = specKeys
+	return nil
+}
+
+func (w *recordingExportWriter) WriteItem(item models.Item) error {
+	w.items = append(w.items, item)
+	return w.err
+}
+
+// TestService_ExportItems_OK: Se escriben las claves del catálogo y los items del
+// cursor, sin paginación ni orden personalizado
+func TestService_ExportItems_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	minID := int64(2)
+	items := []models.Item{validItem(), validItem()}
+	mockRepo.On("SpecKeys", mock.Anything).Return([]string{"color"}, nil)
+	mockRepo.On("Stream", mock.Anything, models.ItemQuery{MinID: &minID}).Return(items, nil)
+
+	writer := &recordingExportWriter{}
+	err := service.ExportItems(context.Background(), models.ItemQuery{
+		MinID: &minID,
+		Limit: 5,
+		Sort:  []models.SortField{{Field: "price"}},
+	}, writer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"color"}, writer.specKeys)
+	assert.Equal(t, items, writer.items)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_ExportItems_InvalidFilters: Los filtros incoherentes se rechazan
+// antes de escribir nada
+func TestService_ExportItems_InvalidFilters(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	minID, maxID := int64(10), int64(1)
+	mockRepo.On("SpecKeys", mock.Anything).Return([]string{"color"}, nil)
+
+	writer := &recordingExportWriter{}
+	err := service.ExportItems(context.Background(), models.ItemQuery{
+		MinID:       &minID,
+		MaxID:       &maxID,
+		SpecFilters: []models.SpecFilter{{Key: "weight", Operator: models.SpecOpEquals, Value: "1kg"}},
+	}, writer)
+
+	var domainErr *errors.DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	assert.Contains(t, domainErr.Message, "min_id no puede ser mayor que max_id")
+	assert.Contains(t, domainErr.Message, `clave de especificación desconocida: "weight"`)
+	assert.Nil(t, writer.specKeys)
+	mockRepo.AssertNotCalled(t, "Stream", mock.Anything, mock.Anything)
+}
+
+// TestService_ExportItems_WriteError: Un error de escritura interrumpe la
+// exportación y se devuelve sin envolver
+func TestService_ExportItems_WriteError(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("SpecKeys", mock.Anything).Return([]string{}, nil)
+	mockRepo.On("Stream", mock.Anything, models.ItemQuery{}).Return([]models.Item{validItem(), validItem()}, nil)
+
+	writer := &recordingExportWriter{err: io.ErrClosedPipe}
+	err := service.ExportItems(context.Background(), models.ItemQuery{}, writer)
+
+	assert.Equal(t, io.ErrClosedPipe, err)
+	assert.Len(t, writer.items, 1)
+}
+
+// revisionSnapshot devuelve el snapshot de validItem con el precio indicado
+func revisionSnapshot(price float64) *models.ItemSnapshot {
+	item := validItem()
+	return &models.ItemSnapshot{
+		Name:           item.Name,
+		ImageURL:       item.ImageURL,
+		Description:    item.Description,
+		Price:          price,
+		Rating:         item.Rating,
+		Specifications: models.Specifications{"color": "red"},
+	}
+}
+
+// TestService_DiffItemRevisions_OK: Sin to se compara con la última revisión y
+// las especificaciones se comparan por clave
+func TestService_DiffItemRevisions_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	latest := revisionSnapshot(80)
+	latest.Specifications = models.Specifications{"weight": 250.0}
+	mockRepo.On("Revisions", mock.Anything, int64(1)).Return([]models.ItemRevision{
+		{ItemID: 1, Revision: 1, Operation: models.RevisionCreate, NewValues: revisionSnapshot(100)},
+		{ItemID: 1, Revision: 2, Operation: models.RevisionUpdate, OldValues: revisionSnapshot(100), NewValues: latest},
+	}, nil)
+
+	diff, err := service.DiffItemRevisions(context.Background(), 1, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), diff.To)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "price", From: 100.0, To: 80.0},
+		{Field: "specifications.color", From: "red", To: nil},
+		{Field: "specifications.weight", From: nil, To: 250.0},
+	}, diff.Changes)
+
+	_, err = service.DiffItemRevisions(context.Background(), 1, 1, 3)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeNotFound, domainErr.Code)
+}
+
+// TestService_RestoreItemRevision_OK: Se aplican los valores de la revisión y se
+// guarda como una actualización con origen restore
+func TestService_RestoreItemRevision_OK(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	current := validItem()
+	current.ID = 1
+	current.Price = 80
+	mockRepo.On("Revision", mock.Anything, int64(1), int64(1)).
+		Return(&models.ItemRevision{ItemID: 1, Revision: 1, NewValues: revisionSnapshot(100)}, nil)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&current, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(ctx context.Context) bool {
+		return models.ActorFromContext(ctx).Source == models.SourceRestore
+	}), mock.AnythingOfType("*models.Item")).Return(nil)
+
+	item, err := service.RestoreItemRevision(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, item.Price)
+	assert.Equal(t, int64(1), item.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestService_RestoreItemRevision_DeletedRevision: La revisión de una baja no tiene
+// valores que restaurar
+func TestService_RestoreItemRevision_DeletedRevision(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	service := NewItemService(mockRepo)
+
+	mockRepo.On("Revision", mock.Anything, int64(1), int64(3)).
+		Return(&models.ItemRevision{ItemID: 1, Revision: 3, Operation: models.RevisionDelete, OldValues: revisionSnapshot(100)}, nil)
+
+	_, err := service.RestoreItemRevision(context.Background(), 1, 3)
+
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorCodeValidation, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
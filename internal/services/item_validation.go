@@ -0,0 +1,213 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"project/internal/errors"
+	"project/internal/models"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxItemNameLength   = 200
+	maxSearchTextLength = 200
+	minItemRating       = 0.0
+	maxItemRating       = 5.0
+)
+
+// itemFieldErrors aplica las reglas de negocio que debe cumplir un ítem antes de
+// ser persistido. Normaliza espacios en los campos de texto y devuelve todos
+// los problemas encontrados, uno por campo.
+func itemFieldErrors(item *models.Item) []errors.FieldError {
+	item.Name = strings.TrimSpace(item.Name)
+	item.ImageURL = strings.TrimSpace(item.ImageURL)
+	item.Description = strings.TrimSpace(item.Description)
+	item.Currency = models.NormalizeCurrency(item.Currency)
+	if item.Currency == "" {
+		item.Currency = models.DefaultCurrency
+	}
+
+	var fields []errors.FieldError
+
+	if item.Name == "" {
+		fields = append(fields, errors.FieldError{Field: "name", Message: "el nombre es obligatorio"})
+	} else if len(item.Name) > maxItemNameLength {
+		fields = append(fields, errors.FieldError{Field: "name", Message: "el nombre no puede superar los 200 caracteres"})
+	}
+
+	if item.Price <= 0 {
+		fields = append(fields, errors.FieldError{Field: "price", Message: "el precio debe ser mayor que 0"})
+	}
+
+	if !models.IsSupportedCurrency(item.Currency) {
+		fields = append(fields, errors.FieldError{Field: "currency", Message: unsupportedCurrencyMessage(item.Currency)})
+	}
+
+	if item.Rating < minItemRating || item.Rating > maxItemRating {
+		fields = append(fields, errors.FieldError{Field: "rating", Message: "el rating debe estar entre 0 y 5"})
+	}
+
+	if !isValidImageURL(item.ImageURL) {
+		fields = append(fields, errors.FieldError{Field: "image_url", Message: "image_url debe ser una URL http(s) válida"})
+	}
+
+	if item.Specifications == nil {
+		item.Specifications = models.Specifications{}
+	}
+
+	return fields
+}
+
+// itemValidationError construye el error de validación de un ítem: el mensaje
+// resume todos los problemas y Details los asocia a cada campo.
+func itemValidationError(fields []errors.FieldError) error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+	return errors.NewFieldValidationError("item inválido: "+strings.Join(messages, "; "), fields)
+}
+
+// isValidImageURL comprueba que la URL sea absoluta, use http o https y tenga host.
+func isValidImageURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// specKeyPattern define el formato válido de una clave de especificación
+// usada en filtros: minúsculas, dígitos y guiones bajos.
+var specKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateItemQuery aplica los valores por defecto de paginación y valida
+// los límites, el ordenamiento y la coherencia de los filtros.
+// knownSpecKeys son las claves de especificación existentes en el catálogo;
+// un filtro sobre cualquier otra clave se considera inválido.
+func validateItemQuery(query *models.ItemQuery, knownSpecKeys []string) error {
+	if query.Limit == 0 {
+		query.Limit = models.DefaultPageLimit
+	}
+
+	var problems []string
+
+	if query.Limit < 0 || query.Limit > models.MaxPageLimit {
+		problems = append(problems, fmt.Sprintf("limit debe estar entre 1 y %d", models.MaxPageLimit))
+	}
+
+	if query.Offset < 0 {
+		problems = append(problems, "offset no puede ser negativo")
+	}
+
+	for _, field := range query.Sort {
+		if !models.IsSortField(field.Field) {
+			problems = append(problems, fmt.Sprintf("campo de ordenamiento no soportado: %q", field.Field))
+		}
+	}
+
+	problems = append(problems, itemFilterProblems(*query, knownSpecKeys)...)
+
+	if len(problems) > 0 {
+		return errors.NewValidationError("parámetros de consulta inválidos: "+strings.Join(problems, "; "), nil)
+	}
+
+	return nil
+}
+
+// validateExportQuery valida los filtros de una exportación. La exportación no se
+// pagina y siempre se ordena por ID, por lo que solo se admiten filtros.
+func validateExportQuery(query models.ItemQuery, knownSpecKeys []string) error {
+	if problems := itemFilterProblems(query, knownSpecKeys); len(problems) > 0 {
+		return errors.NewValidationError("parámetros de exportación inválidos: "+strings.Join(problems, "; "), nil)
+	}
+	return nil
+}
+
+// itemFilterProblems valida la coherencia de los filtros de una consulta de items.
+func itemFilterProblems(query models.ItemQuery, knownSpecKeys []string) []string {
+	var problems []string
+
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		problems = append(problems, "min_price no puede ser mayor que max_price")
+	}
+
+	if query.MinRating != nil && (*query.MinRating < minItemRating || *query.MinRating > maxItemRating) {
+		problems = append(problems, "min_rating debe estar entre 0 y 5")
+	}
+
+	if query.MinID != nil && *query.MinID <= 0 {
+		problems = append(problems, "min_id debe ser un entero positivo")
+	}
+	if query.MaxID != nil && *query.MaxID <= 0 {
+		problems = append(problems, "max_id debe ser un entero positivo")
+	}
+	if query.MinID != nil && query.MaxID != nil && *query.MinID > *query.MaxID {
+		problems = append(problems, "min_id no puede ser mayor que max_id")
+	}
+
+	if query.CategoryID != nil && *query.CategoryID <= 0 {
+		problems = append(problems, "category_id debe ser un entero positivo")
+	}
+
+	if query.Currency != "" && !models.IsSupportedCurrency(query.Currency) {
+		problems = append(problems, unsupportedCurrencyMessage(query.Currency))
+	}
+
+	return append(problems, specFilterProblems(query.SpecFilters, knownSpecKeys)...)
+}
+
+// specFilterProblems valida el formato y la existencia de las claves de los
+// filtros de especificación y que los operadores numéricos reciban un número,
+// con o sin unidad.
+func specFilterProblems(filters []models.SpecFilter, knownSpecKeys []string) []string {
+	known := make(map[string]bool, len(knownSpecKeys))
+	for _, key := range knownSpecKeys {
+		known[key] = true
+	}
+
+	var problems []string
+	for _, filter := range filters {
+		switch {
+		case !specKeyPattern.MatchString(filter.Key):
+			problems = append(problems, fmt.Sprintf("clave de especificación inválida: %q", filter.Key))
+			continue
+		case !known[filter.Key]:
+			problems = append(problems, fmt.Sprintf("clave de especificación desconocida: %q", filter.Key))
+			continue
+		}
+
+		if filter.Operator.IsNumeric() {
+			if _, ok := filter.Quantity(); !ok {
+				problems = append(problems, fmt.Sprintf("spec.%s_%s requiere un valor numérico", filter.Key, filter.Operator))
+			}
+		}
+	}
+	return problems
+}
+
+// validateSearch valida el texto de búsqueda y aplica el límite de resultados por defecto.
+func validateSearch(text string, limit *int) error {
+	if *limit == 0 {
+		*limit = models.DefaultPageLimit
+	}
+
+	var problems []string
+
+	if text == "" {
+		problems = append(problems, "el parámetro q es obligatorio")
+	} else if len(text) > maxSearchTextLength {
+		problems = append(problems, "el parámetro q no puede superar los 200 caracteres")
+	}
+
+	if *limit < 0 || *limit > models.MaxPageLimit {
+		problems = append(problems, fmt.Sprintf("limit debe estar entre 1 y %d", models.MaxPageLimit))
+	}
+
+	if len(problems) > 0 {
+		return errors.NewValidationError("búsqueda inválida: "+strings.Join(problems, "; "), nil)
+	}
+
+	return nil
+}